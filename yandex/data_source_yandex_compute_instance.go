@@ -1,6 +1,7 @@
 package yandex
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -36,6 +37,7 @@ func dataSourceYandexComputeInstance() *schema.Resource {
 			},
 			"zone": {
 				Type:     schema.TypeString,
+				Optional: true,
 				Computed: true,
 			},
 			"description": {
@@ -297,6 +299,42 @@ func dataSourceYandexComputeInstance() *schema.Resource {
 							Type:     schema.TypeString,
 							Computed: true,
 						},
+						"initialize_params": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"name": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"description": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"size": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+									"block_size": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+									"type": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"image_id": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"snapshot_id": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+								},
+							},
+						},
 					},
 				},
 			},
@@ -445,6 +483,19 @@ func dataSourceYandexComputeInstance() *schema.Resource {
 				Optional: true,
 				Computed: true,
 			},
+
+			"gpu_settings": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"gpu_cluster_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
 		},
 	}
 
@@ -463,7 +514,7 @@ func dataSourceYandexComputeInstanceRead(d *schema.ResourceData, meta interface{
 	_, instanceNameOk := d.GetOk("name")
 
 	if instanceNameOk {
-		instanceID, err = resolveObjectID(ctx, config, d, sdkresolvers.InstanceResolver)
+		instanceID, err = findComputeInstanceByName(ctx, config, d)
 		if err != nil {
 			return fmt.Errorf("failed to resolve data source instance by name: %v", err)
 		}
@@ -497,6 +548,15 @@ func dataSourceYandexComputeInstanceRead(d *schema.ResourceData, meta interface{
 	if err != nil {
 		return err
 	}
+	for _, secondaryDisk := range secondaryDisks {
+		disk, err := config.sdk.Compute().Disk().Get(ctx, &compute.GetDiskRequest{
+			DiskId: secondaryDisk["disk_id"].(string),
+		})
+		if err != nil {
+			return err
+		}
+		secondaryDisk["initialize_params"] = flattenInstanceDiskInitializeParams(disk)
+	}
 
 	schedulingPolicy, err := flattenInstanceSchedulingPolicy(instance)
 	if err != nil {
@@ -574,7 +634,63 @@ func dataSourceYandexComputeInstanceRead(d *schema.ResourceData, meta interface{
 		d.Set("gpu_cluster_id", instance.GpuSettings.GpuClusterId)
 	}
 
+	if err := d.Set("gpu_settings", flattenInstanceGpuSettings(instance.GpuSettings)); err != nil {
+		return err
+	}
+
 	d.SetId(instance.Id)
 
 	return nil
 }
+
+func findComputeInstanceByName(ctx context.Context, config *Config, d *schema.ResourceData) (string, error) {
+	folderID, err := getFolderID(d, config)
+	if err != nil {
+		return "", err
+	}
+
+	name := d.Get("name").(string)
+	zone := d.Get("zone").(string)
+
+	instances := []*compute.Instance{}
+	pageToken := ""
+	for {
+		resp, err := config.sdk.Compute().Instance().List(ctx, &compute.ListInstancesRequest{
+			FolderId:  folderID,
+			Filter:    sdkresolvers.CreateResolverFilter("name", name),
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return "", fmt.Errorf("error while getting list of instances in folder %q: %s", folderID, err)
+		}
+		instances = append(instances, resp.Instances...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+
+	return selectComputeInstanceByName(instances, name, zone, folderID)
+}
+
+func selectComputeInstanceByName(instances []*compute.Instance, name, zone, folderID string) (string, error) {
+	var ids []string
+	for _, instance := range instances {
+		if instance.Name != name || (zone != "" && instance.ZoneId != zone) {
+			continue
+		}
+		ids = append(ids, instance.Id)
+	}
+
+	switch len(ids) {
+	case 0:
+		if zone != "" {
+			return "", fmt.Errorf("instance with name %q not found in folder %q and zone %q", name, folderID, zone)
+		}
+		return "", fmt.Errorf("instance with name %q not found in folder %q", name, folderID)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("more than one instance with name %q found in folder %q, set zone to choose one of: %s", name, folderID, strings.Join(ids, ", "))
+	}
+}
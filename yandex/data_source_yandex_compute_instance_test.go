@@ -8,6 +8,9 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
 )
 
 func TestAccDataSourceComputeInstance_byID(t *testing.T) {
@@ -50,6 +53,28 @@ func TestAccDataSourceComputeInstance_byName(t *testing.T) {
 	})
 }
 
+func TestSelectComputeInstanceByName(t *testing.T) {
+	instances := []*compute.Instance{
+		{Id: "id-a", Name: "web", ZoneId: "ru-central1-a"},
+		{Id: "id-b", Name: "web", ZoneId: "ru-central1-b"},
+		{Id: "id-c", Name: "db", ZoneId: "ru-central1-a"},
+	}
+
+	id, err := selectComputeInstanceByName(instances, "db", "", "folder")
+	require.NoError(t, err)
+	require.Equal(t, "id-c", id)
+
+	id, err = selectComputeInstanceByName(instances, "web", "ru-central1-b", "folder")
+	require.NoError(t, err)
+	require.Equal(t, "id-b", id)
+
+	_, err = selectComputeInstanceByName(instances, "web", "", "folder")
+	require.ErrorContains(t, err, "id-a, id-b")
+
+	_, err = selectComputeInstanceByName(instances, "db", "ru-central1-b", "folder")
+	require.ErrorContains(t, err, "not found")
+}
+
 func testAccDataSourceComputeInstanceAttributesCheck(datasourceName string, resourceName string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		ds, ok := s.RootModule().Resources[datasourceName]
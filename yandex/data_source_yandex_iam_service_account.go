@@ -34,6 +34,12 @@ func dataSourceYandexIAMServiceAccount() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"labels": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
 			"created_at": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -74,6 +80,7 @@ func dataSourceYandexIAMServiceAccountRead(d *schema.ResourceData, meta interfac
 	d.Set("folder_id", sa.FolderId)
 	d.Set("name", sa.Name)
 	d.Set("description", sa.Description)
+	d.Set("labels", sa.Labels)
 	d.Set("created_at", getTimestamp(sa.CreatedAt))
 	d.SetId(sa.Id)
 
@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/kms/v1"
+	"github.com/yandex-cloud/go-sdk/sdkresolvers"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
@@ -21,6 +22,7 @@ func dataSourceYandexKMSSymmetricKey() *schema.Resource {
 			"name": {
 				Type:     schema.TypeString,
 				Optional: true,
+				Computed: true,
 			},
 
 			"description": {
@@ -76,9 +78,16 @@ func dataSourceYandexKMSSymmetricKey() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+
+			"primary_version_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
 			"symmetric_key_id": {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
+				Computed:     true,
 				ValidateFunc: validation.StringLenBetween(0, 50),
 			},
 		},
@@ -87,15 +96,31 @@ func dataSourceYandexKMSSymmetricKey() *schema.Resource {
 
 func dataSourceYandexKMSSymmetricKeyRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	config := meta.(*Config)
+
+	err := checkOneOf(data, "symmetric_key_id", "name")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	keyID := data.Get("symmetric_key_id").(string)
+	_, keyNameOk := data.GetOk("name")
+
+	if keyNameOk {
+		keyID, err = resolveObjectID(ctx, config, data, sdkresolvers.SymmetricKeyResolver)
+		if err != nil {
+			return diag.Errorf("failed to resolve data source kms symmetric key by name: %v", err)
+		}
+	}
+
 	req := &kms.GetSymmetricKeyRequest{
-		KeyId: data.Get("symmetric_key_id").(string),
+		KeyId: keyID,
 	}
 
 	md := new(metadata.MD)
 	resp, err := config.sdk.KMS().SymmetricKey().Get(ctx, req, grpc.Header(md))
 
 	if err != nil {
-		return diag.FromErr(handleNotFoundError(err, data, fmt.Sprintf("kms symmetric key %q", data.Get("symmetric_key_id").(string))))
+		return diag.FromErr(handleNotFoundError(err, data, fmt.Sprintf("kms symmetric key %q", keyID)))
 	}
 	data.SetId(resp.Id)
 
@@ -111,6 +136,8 @@ func dataSourceYandexKMSSymmetricKeyRead(ctx context.Context, data *schema.Resou
 		return diag.FromErr(err)
 	}
 	data.Set("name", resp.GetName())
+	data.Set("primary_version_id", resp.GetPrimaryVersion().GetId())
+	data.Set("rotation_period", formatDuration(resp.GetRotationPeriod()))
 	data.Set("rotated_at", rotatedAt)
 	data.Set("status", resp.GetStatus().String())
 	data.Set("symmetric_key_id", resp.GetId())
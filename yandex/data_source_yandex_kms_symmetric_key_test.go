@@ -16,6 +16,7 @@ func TestAccDataSourceKMSSymmetricKey_basic(t *testing.T) {
 	keyDesc := "Terraform Test"
 	folderID := getExampleFolderID()
 	basicData := "data.yandex_kms_symmetric_key.basic_key"
+	byNameData := "data.yandex_kms_symmetric_key.by_name"
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
 		ProviderFactories: testAccProviderFactories,
@@ -34,7 +35,13 @@ func TestAccDataSourceKMSSymmetricKey_basic(t *testing.T) {
 					resource.TestCheckResourceAttr(basicData, "labels.%", "2"),
 					resource.TestCheckResourceAttr(basicData, "labels.key1", "value1"),
 					resource.TestCheckResourceAttr(basicData, "labels.key2", "value2"),
+					resource.TestCheckResourceAttr(basicData, "default_algorithm", "AES_256"),
+					resource.TestCheckResourceAttr(basicData, "rotation_period", "24h0m0s"),
+					resource.TestCheckResourceAttrSet(basicData, "primary_version_id"),
 					testAccCheckCreatedAtAttr(basicData),
+					testAccDataSourceKmsSymmetricKeyExists(byNameData),
+					resource.TestCheckResourceAttrPair(byNameData, "symmetric_key_id", "yandex_kms_symmetric_key.basic_key", "id"),
+					resource.TestCheckResourceAttrPair(byNameData, "primary_version_id", basicData, "primary_version_id"),
 				),
 			},
 		},
@@ -44,8 +51,10 @@ func TestAccDataSourceKMSSymmetricKey_basic(t *testing.T) {
 func testAccKMSSymmetricKeyResourceAndData(name, desc string) string {
 	return fmt.Sprintf(`
 resource "yandex_kms_symmetric_key" "basic_key" {
-  name        = "%v"
-  description = "%v"
+  name              = "%v"
+  description       = "%v"
+  default_algorithm = "AES_256"
+  rotation_period   = "24h"
   labels = {
     key1 = "value1"
     key2 = "value2"
@@ -55,6 +64,10 @@ resource "yandex_kms_symmetric_key" "basic_key" {
 data "yandex_kms_symmetric_key" "basic_key" {
   symmetric_key_id = yandex_kms_symmetric_key.basic_key.id
 }
+
+data "yandex_kms_symmetric_key" "by_name" {
+  name = yandex_kms_symmetric_key.basic_key.name
+}
 `, name, desc)
 }
 
@@ -9,6 +9,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/lockbox/v1"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
@@ -56,9 +57,10 @@ func dataSourceYandexLockboxSecretVersion() *schema.Resource {
 func dataSourceYandexLockboxSecretVersionRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	config := meta.(*Config)
 
+	secretID := d.Get("secret_id").(string)
 	id := d.Get("version_id").(string)
 	req := &lockbox.GetPayloadRequest{
-		SecretId:  d.Get("secret_id").(string),
+		SecretId:  secretID,
 		VersionId: id,
 	}
 
@@ -66,10 +68,15 @@ func dataSourceYandexLockboxSecretVersionRead(ctx context.Context, d *schema.Res
 
 	payload, err := config.sdk.LockboxPayload().Payload().Get(ctx, req)
 	if err != nil {
+		if isStatusWithCode(err, codes.PermissionDenied) {
+			return diag.Errorf("access denied to payload of Lockbox secret %q, "+
+				"reading entries requires the lockbox.payloadViewer role: %s", secretID, err)
+		}
 		return diag.FromErr(handleNotFoundError(err, d, fmt.Sprintf("secret version payload %q", id)))
 	}
 
 	d.SetId(payload.VersionId)
+	d.Set("version_id", payload.VersionId)
 
 	entries, err := flattenLockboxSecretVersionEntriesSlice(payload.GetEntries())
 	if err != nil {
@@ -81,7 +88,7 @@ func dataSourceYandexLockboxSecretVersionRead(ctx context.Context, d *schema.Res
 		return diag.FromErr(err)
 	}
 
-	log.Printf("[INFO] read Lockbox version with ID: %s", id)
+	log.Printf("[INFO] read Lockbox version with ID: %s", payload.VersionId)
 
 	return diag.FromErr(err)
 }
@@ -16,6 +16,7 @@ func TestAccDataSourceLockboxVersion_basic(t *testing.T) {
 	secretName := "a" + acctest.RandString(10)
 	basicData1 := "data.yandex_lockbox_secret_version.basic_version1"
 	basicData2 := "data.yandex_lockbox_secret_version.basic_version2"
+	currentData := "data.yandex_lockbox_secret_version.current_version"
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
 		ProviderFactories: testAccProviderFactories,
@@ -37,6 +38,13 @@ func TestAccDataSourceLockboxVersion_basic(t *testing.T) {
 						{Key: "key2", Val: "val2"},
 						{Key: "key3", Val: "val3"},
 					}),
+					// without version_id the current version is read
+					testAccDataSourceLockboxSecretVersionExists(currentData),
+					resource.TestCheckResourceAttrPair(currentData, "version_id", "yandex_lockbox_secret_version.basic_version2", "id"),
+					testAccCheckYandexLockboxVersionStateEntries(currentData, []*lockboxEntryCheck{
+						{Key: "key2", Val: "val2"},
+						{Key: "key3", Val: "val3"},
+					}),
 				),
 			},
 		},
@@ -63,6 +71,7 @@ resource "yandex_lockbox_secret_version" "basic_version1" {
 
 resource "yandex_lockbox_secret_version" "basic_version2" {
   secret_id   = yandex_lockbox_secret.basic_secret.id
+  depends_on  = [yandex_lockbox_secret_version.basic_version1]
   entries {
       key        = "key2"
       text_value = "val2"
@@ -82,6 +91,11 @@ data "yandex_lockbox_secret_version" "basic_version2" {
   secret_id = yandex_lockbox_secret.basic_secret.id
   version_id = yandex_lockbox_secret_version.basic_version2.id
 }
+
+data "yandex_lockbox_secret_version" "current_version" {
+  secret_id  = yandex_lockbox_secret.basic_secret.id
+  depends_on = [yandex_lockbox_secret_version.basic_version2]
+}
 `, name)
 }
 
@@ -0,0 +1,124 @@
+package yandex
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/mdb/clickhouse/v1"
+)
+
+func dataSourceYandexMDBClickHouseBackups() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceYandexMDBClickHouseBackupsRead,
+		Schema: map[string]*schema.Schema{
+			"cluster_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"folder_id"},
+			},
+			"folder_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"cluster_id"},
+			},
+			"backups": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"folder_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"source_cluster_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"source_shard_names": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"started_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"created_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceYandexMDBClickHouseBackupsRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	ctx := context.Background()
+
+	var backups []*clickhouse.Backup
+	var err error
+
+	if clusterID, ok := d.GetOk("cluster_id"); ok {
+		backups, err = listClickHouseClusterBackups(ctx, config, clusterID.(string))
+		if err != nil {
+			return err
+		}
+		d.SetId(clusterID.(string))
+	} else {
+		folderID, err := getFolderID(d, config)
+		if err != nil {
+			return err
+		}
+		backups, err = listClickHouseFolderBackups(ctx, config, folderID)
+		if err != nil {
+			return err
+		}
+		d.Set("folder_id", folderID)
+		d.SetId(folderID)
+	}
+
+	return d.Set("backups", flattenClickHouseBackups(backups))
+}
+
+func listClickHouseFolderBackups(ctx context.Context, config *Config, folderID string) ([]*clickhouse.Backup, error) {
+	backups, err := listMDBPages(ctx, func(ctx context.Context, pageToken string) ([]*clickhouse.Backup, string, error) {
+		resp, err := config.sdk.MDB().Clickhouse().Backup().List(ctx, &clickhouse.ListBackupsRequest{
+			FolderId:  folderID,
+			PageSize:  defaultMDBPageSize,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Backups, resp.NextPageToken, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error while getting list of backups in folder '%s': %s", folderID, err)
+	}
+	return backups, nil
+}
+
+func flattenClickHouseBackups(backups []*clickhouse.Backup) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(backups))
+	for _, b := range backups {
+		result = append(result, map[string]interface{}{
+			"id":                 b.Id,
+			"folder_id":          b.FolderId,
+			"source_cluster_id":  b.SourceClusterId,
+			"source_shard_names": b.SourceShardNames,
+			"started_at":         getTimestamp(b.StartedAt),
+			"created_at":         getTimestamp(b.CreatedAt),
+		})
+	}
+	return result
+}
@@ -0,0 +1,75 @@
+package yandex
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/mdb/clickhouse/v1"
+)
+
+const chBackupsDataSource = "data.yandex_mdb_clickhouse_backups.bar"
+
+func TestFlattenClickHouseBackups(t *testing.T) {
+	createdAt := time.Date(2023, 7, 25, 10, 0, 0, 0, time.UTC)
+
+	actual := flattenClickHouseBackups([]*clickhouse.Backup{
+		{
+			Id:               "backup1",
+			FolderId:         "folder1",
+			SourceClusterId:  "cluster1",
+			SourceShardNames: []string{"shard1"},
+			StartedAt:        timestamppb.New(createdAt.Add(-time.Minute)),
+			CreatedAt:        timestamppb.New(createdAt),
+		},
+	})
+
+	require.Equal(t, []map[string]interface{}{
+		{
+			"id":                 "backup1",
+			"folder_id":          "folder1",
+			"source_cluster_id":  "cluster1",
+			"source_shard_names": []string{"shard1"},
+			"started_at":         "2023-07-25T09:59:00Z",
+			"created_at":         "2023-07-25T10:00:00Z",
+		},
+	}, actual)
+	require.Empty(t, flattenClickHouseBackups(nil))
+}
+
+func TestAccDataSourceMDBClickHouseBackups_byClusterID(t *testing.T) {
+	t.Parallel()
+
+	chName := acctest.RandomWithPrefix("ds-ch-backups")
+	chDesc := "ClickHouse Backups Terraform Datasource Test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMDBClickHouseClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceMDBClickHouseBackupsConfig(chName, chDesc),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(chBackupsDataSource, "cluster_id", chResource, "id"),
+					resource.TestCheckResourceAttrPair(chBackupsDataSource, "backups.0.source_cluster_id", chResource, "id"),
+					resource.TestCheckResourceAttrSet(chBackupsDataSource, "backups.0.id"),
+					resource.TestCheckResourceAttrSet(chBackupsDataSource, "backups.0.created_at"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceMDBClickHouseBackupsConfig(name, desc string) string {
+	return testAccMDBClickHouseClusterBackupConfig(name, desc) + fmt.Sprintf(`
+data "yandex_mdb_clickhouse_backups" "bar" {
+  cluster_id = "${%s.cluster_id}"
+}
+`, chBackupResource)
+}
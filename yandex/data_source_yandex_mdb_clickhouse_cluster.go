@@ -6,6 +6,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/mdb/clickhouse/v1"
 	"github.com/yandex-cloud/go-sdk/sdkresolvers"
 )
 
@@ -20,13 +21,28 @@ func dataSourceYandexMDBClickHouseClusterRead(d *schema.ResourceData, meta inter
 	config := meta.(*Config)
 	ctx := context.Background()
 
+	_, clusterIDOk := d.GetOk("cluster_id")
+	_, clusterNameOk := d.GetOk("name")
+	_, labelsOk := d.GetOk("labels")
+	_, descriptionOk := d.GetOk("description")
+
+	if !clusterIDOk && !clusterNameOk && (labelsOk || descriptionOk) {
+		clusterID, err := findClickHouseClusterByFilter(ctx, config, d)
+		if err != nil {
+			return err
+		}
+
+		d.Set("cluster_id", clusterID)
+		d.SetId(clusterID)
+		return resourceYandexMDBClickHouseClusterRead(d, meta)
+	}
+
 	err := checkOneOf(d, "cluster_id", "name")
 	if err != nil {
 		return err
 	}
 
 	clusterID := d.Get("cluster_id").(string)
-	_, clusterNameOk := d.GetOk("name")
 
 	if clusterNameOk {
 		clusterID, err = resolveObjectID(ctx, config, d, sdkresolvers.ClickhouseClusterResolver)
@@ -40,3 +56,45 @@ func dataSourceYandexMDBClickHouseClusterRead(d *schema.ResourceData, meta inter
 	d.SetId(clusterID)
 	return resourceYandexMDBClickHouseClusterRead(d, meta)
 }
+
+func findClickHouseClusterByFilter(ctx context.Context, config *Config, d *schema.ResourceData) (string, error) {
+	folderID, err := getFolderID(d, config)
+	if err != nil {
+		return "", err
+	}
+
+	clusters, err := listClickHouseClusters(ctx, config, folderID)
+	if err != nil {
+		return "", err
+	}
+
+	labels := convertTypesMap(d.Get("labels"))
+	description := d.Get("description").(string)
+
+	matched := filterClickHouseClusters(clusters, labels, description)
+	switch len(matched) {
+	case 0:
+		return "", fmt.Errorf("failed to find ClickHouse Cluster matching labels %v and description %q in folder %q", labels, description, folderID)
+	case 1:
+		return matched[0].Id, nil
+	default:
+		ids := make([]string, 0, len(matched))
+		for _, cluster := range matched {
+			ids = append(ids, cluster.Id)
+		}
+		return "", fmt.Errorf("more than one ClickHouse Cluster matches labels %v and description %q: %s", labels, description, getJoinedKeys(ids))
+	}
+}
+
+func filterClickHouseClusters(clusters []*clickhouse.Cluster, labels map[string]string, description string) []*clickhouse.Cluster {
+	var result []*clickhouse.Cluster
+	for _, cluster := range clusters {
+		if description != "" && cluster.Description != description {
+			continue
+		}
+		if matchLabels(cluster.Labels, labels) {
+			result = append(result, cluster)
+		}
+	}
+	return result
+}
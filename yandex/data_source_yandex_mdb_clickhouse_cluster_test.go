@@ -2,11 +2,14 @@ package yandex
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/mdb/clickhouse/v1"
 )
 
 func TestAccDataSourceMDBClickHouseCluster_byID(t *testing.T) {
@@ -55,6 +58,66 @@ func TestAccDataSourceMDBClickHouseCluster_byName(t *testing.T) {
 	})
 }
 
+func TestAccDataSourceMDBClickHouseCluster_byLabels(t *testing.T) {
+	t.Parallel()
+
+	chName := acctest.RandomWithPrefix("ds-ch-by-labels")
+	chDesc := "ClickHouseCluster Terraform Datasource Test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMDBClickHouseClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceMDBClickHouseClusterByLabelsConfig(chName, chDesc),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"data.yandex_mdb_clickhouse_cluster.bar", "cluster_id",
+						"yandex_mdb_clickhouse_cluster.foo", "id",
+					),
+					resource.TestCheckResourceAttr("data.yandex_mdb_clickhouse_cluster.bar", "name", chName),
+					resource.TestCheckResourceAttr("data.yandex_mdb_clickhouse_cluster.bar", "labels.ds_key", chName),
+				),
+			},
+		},
+	})
+}
+
+func TestFilterClickHouseClusters(t *testing.T) {
+	clusters := []*clickhouse.Cluster{
+		{Id: "c1", Description: "first", Labels: map[string]string{"env": "prod", "team": "a"}},
+		{Id: "c2", Description: "second", Labels: map[string]string{"env": "prod", "team": "b"}},
+		{Id: "c3", Description: "first", Labels: map[string]string{"env": "test"}},
+	}
+
+	tests := []struct {
+		name        string
+		labels      map[string]string
+		description string
+		expected    []string
+	}{
+		{name: "unique label", labels: map[string]string{"team": "b"}, expected: []string{"c2"}},
+		{name: "all labels must match", labels: map[string]string{"env": "prod", "team": "a"}, expected: []string{"c1"}},
+		{name: "several matches", labels: map[string]string{"env": "prod"}, expected: []string{"c1", "c2"}},
+		{name: "labels and description", labels: map[string]string{"env": "test"}, description: "first", expected: []string{"c3"}},
+		{name: "description only", description: "first", expected: []string{"c1", "c3"}},
+		{name: "no matches", labels: map[string]string{"env": "stage"}, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []string
+			for _, cluster := range filterClickHouseClusters(clusters, tt.labels, tt.description) {
+				ids = append(ids, cluster.Id)
+			}
+			if !reflect.DeepEqual(ids, tt.expected) {
+				t.Errorf("filterClickHouseClusters() = %v, want %v", ids, tt.expected)
+			}
+		})
+	}
+}
+
 func testAccDataSourceMDBClickHouseClusterAttributesCheck(datasourceName string, resourceName string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		ds, ok := s.RootModule().Resources[datasourceName]
@@ -95,6 +158,7 @@ func testAccDataSourceMDBClickHouseClusterAttributesCheck(datasourceName string,
 			"sql_user_management",
 			"sql_database_management",
 			"embedded_keeper",
+			"keeper_hosts",
 			"service_account_id",
 			"deletion_protection",
 		}
@@ -162,3 +226,36 @@ func testAccDataSourceMDBClickHouseClusterConfig(chName, chDesc, bucket string,
 
 	return testAccMDBClickHouseClusterConfigMain(chName, chDesc, "PRESTABLE", false, bucket, randInt, MaintenanceWindowWeekly) + mdbClickHouseClusterByNameConfig
 }
+
+func testAccDataSourceMDBClickHouseClusterByLabelsConfig(chName, chDesc string) string {
+	return fmt.Sprintf(clickHouseVPCDependencies+`
+resource "yandex_mdb_clickhouse_cluster" "foo" {
+  name           = "%[1]s"
+  description    = "%[2]s"
+  environment    = "PRESTABLE"
+  network_id     = "${yandex_vpc_network.mdb-ch-test-net.id}"
+
+  labels = {
+    ds_key = "%[1]s"
+  }
+
+  clickhouse {
+    resources {
+      resource_preset_id = "s2.micro"
+      disk_type_id       = "network-ssd"
+      disk_size          = 16
+    }
+  }
+
+  host {
+    type      = "CLICKHOUSE"
+    zone      = "ru-central1-a"
+    subnet_id = "${yandex_vpc_subnet.mdb-ch-test-subnet-a.id}"
+  }
+}
+
+data "yandex_mdb_clickhouse_cluster" "bar" {
+  labels = yandex_mdb_clickhouse_cluster.foo.labels
+}
+`, chName, chDesc)
+}
@@ -0,0 +1,68 @@
+package yandex
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/mdb/clickhouse/v1"
+)
+
+func dataSourceYandexMDBClickHouseUser() *schema.Resource {
+	user := resourceYandexMDBClickHouseCluster().Schema["user"].Elem.(*schema.Resource)
+
+	dataSource := convertResourceToDataSource(user)
+	delete(dataSource.Schema, "password")
+	dataSource.Schema["name"].Computed = false
+	dataSource.Schema["name"].Required = true
+	dataSource.Schema["cluster_id"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+	}
+	dataSource.Read = dataSourceYandexMDBClickHouseUserRead
+	return dataSource
+}
+
+func dataSourceYandexMDBClickHouseUserRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	ctx := context.Background()
+
+	clusterID := d.Get("cluster_id").(string)
+	userName := d.Get("name").(string)
+
+	user, err := config.sdk.MDB().Clickhouse().User().Get(ctx, &clickhouse.GetUserRequest{
+		ClusterId: clusterID,
+		UserName:  userName,
+	})
+	if err != nil {
+		return fmt.Errorf("error while getting ClickHouse user %q in cluster %q: %s", userName, clusterID, err)
+	}
+
+	permissions := schema.NewSet(clickHouseUserPermissionHash, nil)
+	for _, perm := range user.Permissions {
+		permissions.Add(map[string]interface{}{"database_name": perm.DatabaseName})
+	}
+	if err := d.Set("permission", permissions); err != nil {
+		return err
+	}
+
+	var settings []interface{}
+	if user.Settings != nil {
+		settings = []interface{}{flattenClickHouseUserSettings(user.Settings)}
+	}
+	if err := d.Set("settings", settings); err != nil {
+		return err
+	}
+
+	quotas := schema.NewSet(clickHouseUserQuotaHash, nil)
+	for _, quota := range user.Quotas {
+		quotas.Add(flattenClickHouseUserQuota(quota))
+	}
+	if err := d.Set("quota", quotas); err != nil {
+		return err
+	}
+
+	d.SetId(constructResourceId(clusterID, userName))
+	return nil
+}
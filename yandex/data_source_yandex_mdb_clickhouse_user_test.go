@@ -0,0 +1,93 @@
+package yandex
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const chUserDataSource = "data.yandex_mdb_clickhouse_user.john"
+
+func TestAccDataSourceMDBClickHouseUser_basic(t *testing.T) {
+	t.Parallel()
+
+	chName := acctest.RandomWithPrefix("ds-ch-user")
+	chDesc := "ClickHouse User Terraform Datasource Test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMDBClickHouseClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceMDBClickHouseUserConfig(chName, chDesc),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(chUserDataSource, "cluster_id", chResource, "id"),
+					resource.TestCheckResourceAttr(chUserDataSource, "name", "john"),
+					resource.TestCheckResourceAttr(chUserDataSource, "permission.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs(chUserDataSource, "permission.*", map[string]string{
+						"database_name": "testdb",
+					}),
+					resource.TestCheckResourceAttr(chUserDataSource, "settings.0.max_threads", "4"),
+					resource.TestCheckResourceAttr(chUserDataSource, "quota.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs(chUserDataSource, "quota.*", map[string]string{
+						"interval_duration": "3600000",
+						"queries":           "1000",
+					}),
+					resource.TestCheckNoResourceAttr(chUserDataSource, "password"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceMDBClickHouseUserConfig(name, desc string) string {
+	return fmt.Sprintf(clickHouseVPCDependencies+`
+resource "yandex_mdb_clickhouse_cluster" "foo" {
+  name           = "%s"
+  description    = "%s"
+  environment    = "PRESTABLE"
+  network_id     = "${yandex_vpc_network.mdb-ch-test-net.id}"
+
+  clickhouse {
+    resources {
+      resource_preset_id = "s2.micro"
+      disk_type_id       = "network-ssd"
+      disk_size          = 16
+    }
+  }
+
+  database {
+    name = "testdb"
+  }
+
+  user {
+    name     = "john"
+    password = "password"
+    permission {
+      database_name = "testdb"
+    }
+    settings {
+      max_threads = 4
+    }
+    quota {
+      interval_duration = 3600000
+      queries           = 1000
+    }
+  }
+
+  host {
+    type      = "CLICKHOUSE"
+    zone      = "ru-central1-a"
+    subnet_id = "${yandex_vpc_subnet.mdb-ch-test-subnet-a.id}"
+  }
+}
+
+data "yandex_mdb_clickhouse_user" "john" {
+  cluster_id = "${yandex_mdb_clickhouse_cluster.foo.id}"
+  name       = "john"
+}
+`, name, desc)
+}
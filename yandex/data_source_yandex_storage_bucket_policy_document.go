@@ -0,0 +1,237 @@
+package yandex
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/yandex-cloud/terraform-provider-yandex/yandex/internal/hashcode"
+)
+
+const storagePolicyDocumentDefaultVersion = "2012-10-17"
+
+const (
+	storagePolicyPrincipalAll            = "*"
+	storagePolicyPrincipalCanonicalUser  = "CanonicalUser"
+	storagePolicyPrincipalServiceAccount = "ServiceAccount"
+)
+
+type storagePolicyDocument struct {
+	Version   string                    `json:"Version"`
+	Statement []*storagePolicyStatement `json:"Statement"`
+}
+
+type storagePolicyStatement struct {
+	Sid       string                 `json:"Sid,omitempty"`
+	Effect    string                 `json:"Effect"`
+	Principal interface{}            `json:"Principal,omitempty"`
+	Action    interface{}            `json:"Action,omitempty"`
+	Resource  interface{}            `json:"Resource,omitempty"`
+	Condition map[string]interface{} `json:"Condition,omitempty"`
+}
+
+// dataSourceYandexStorageBucketPolicyDocument renders a policy document for the policy attribute of yandex_storage_bucket.
+func dataSourceYandexStorageBucketPolicyDocument() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceYandexStorageBucketPolicyDocumentRead,
+
+		Schema: map[string]*schema.Schema{
+			"version": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  storagePolicyDocumentDefaultVersion,
+			},
+
+			"statement": {
+				Type:     schema.TypeList,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"sid": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"effect": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "Allow",
+							ValidateFunc: validation.StringInSlice([]string{"Allow", "Deny"}, false),
+						},
+						"actions": {
+							Type:     schema.TypeSet,
+							Required: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Set:      schema.HashString,
+						},
+						"resources": {
+							Type:     schema.TypeSet,
+							Required: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Set:      schema.HashString,
+						},
+						"principals": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"type": {
+										Type:     schema.TypeString,
+										Required: true,
+										ValidateFunc: validation.StringInSlice([]string{
+											storagePolicyPrincipalAll,
+											storagePolicyPrincipalCanonicalUser,
+											storagePolicyPrincipalServiceAccount,
+										}, false),
+									},
+									"identifiers": {
+										Type:     schema.TypeSet,
+										Required: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+										Set:      schema.HashString,
+									},
+								},
+							},
+						},
+						"condition": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"test": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"variable": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"values": {
+										Type:     schema.TypeSet,
+										Required: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+										Set:      schema.HashString,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+
+			"json": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceYandexStorageBucketPolicyDocumentRead(d *schema.ResourceData, meta interface{}) error {
+	doc := &storagePolicyDocument{
+		Version: d.Get("version").(string),
+	}
+
+	for i, v := range d.Get("statement").([]interface{}) {
+		statement, err := expandStoragePolicyStatement(v.(map[string]interface{}))
+		if err != nil {
+			return fmt.Errorf("statement.%d: %s", i, err)
+		}
+		doc.Statement = append(doc.Statement, statement)
+	}
+
+	jsonDoc, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		// should never happen if the above code is correct
+		return err
+	}
+	stringDoc := string(jsonDoc)
+
+	d.Set("json", stringDoc)
+	d.SetId(strconv.Itoa(hashcode.String(stringDoc)))
+
+	return nil
+}
+
+func expandStoragePolicyStatement(v map[string]interface{}) (*storagePolicyStatement, error) {
+	statement := &storagePolicyStatement{
+		Sid:      v["sid"].(string),
+		Effect:   v["effect"].(string),
+		Action:   storagePolicyStringOrSlice(convertStringSet(v["actions"].(*schema.Set))),
+		Resource: storagePolicyStringOrSlice(convertStringSet(v["resources"].(*schema.Set))),
+	}
+
+	principal, err := expandStoragePolicyPrincipals(v["principals"].([]interface{}))
+	if err != nil {
+		return nil, err
+	}
+	statement.Principal = principal
+
+	for _, c := range v["condition"].([]interface{}) {
+		condition := c.(map[string]interface{})
+		test := condition["test"].(string)
+		if statement.Condition == nil {
+			statement.Condition = map[string]interface{}{}
+		}
+		variables, ok := statement.Condition[test].(map[string]interface{})
+		if !ok {
+			variables = map[string]interface{}{}
+			statement.Condition[test] = variables
+		}
+		variable := condition["variable"].(string)
+		if _, ok := variables[variable]; ok {
+			return nil, fmt.Errorf("condition with test %q and variable %q is specified more than once", test, variable)
+		}
+		variables[variable] = storagePolicyStringOrSlice(convertStringSet(condition["values"].(*schema.Set)))
+	}
+
+	return statement, nil
+}
+
+// expandStoragePolicyPrincipals renders principals in the format Object Storage expects:
+// "*" for everyone, otherwise account IDs under the CanonicalUser key.
+// Service accounts are referenced by their ID, so ServiceAccount is an alias for CanonicalUser.
+func expandStoragePolicyPrincipals(principals []interface{}) (interface{}, error) {
+	if len(principals) == 0 {
+		return nil, nil
+	}
+
+	var ids []string
+	all := false
+	for _, p := range principals {
+		principal := p.(map[string]interface{})
+		identifiers := convertStringSet(principal["identifiers"].(*schema.Set))
+
+		switch principal["type"].(string) {
+		case storagePolicyPrincipalAll:
+			if len(identifiers) != 1 || identifiers[0] != "*" {
+				return nil, fmt.Errorf("principals of type \"*\" must have identifiers = [\"*\"]")
+			}
+			all = true
+		default:
+			ids = append(ids, identifiers...)
+		}
+	}
+
+	if all {
+		if len(ids) > 0 {
+			return nil, fmt.Errorf("principals of type \"*\" can't be combined with other principals")
+		}
+		return storagePolicyPrincipalAll, nil
+	}
+
+	return map[string]interface{}{
+		storagePolicyPrincipalCanonicalUser: storagePolicyStringOrSlice(ids),
+	}, nil
+}
+
+func storagePolicyStringOrSlice(values []string) interface{} {
+	if len(values) == 1 {
+		return values[0]
+	}
+	sort.Strings(values)
+	return values
+}
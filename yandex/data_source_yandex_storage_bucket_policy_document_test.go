@@ -0,0 +1,191 @@
+package yandex
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDataSourceYandexStorageBucketPolicyDocumentRead(t *testing.T) {
+	raw := map[string]interface{}{
+		"statement": []interface{}{
+			map[string]interface{}{
+				"sid":       "ReadObjects",
+				"actions":   []interface{}{"s3:GetObject"},
+				"resources": []interface{}{"arn:aws:s3:::my-bucket/*"},
+				"principals": []interface{}{
+					map[string]interface{}{
+						"type":        "ServiceAccount",
+						"identifiers": []interface{}{"ajeserviceaccount"},
+					},
+				},
+			},
+			map[string]interface{}{
+				"effect":    "Deny",
+				"actions":   []interface{}{"s3:PutObject", "s3:DeleteObject"},
+				"resources": []interface{}{"arn:aws:s3:::my-bucket/*", "arn:aws:s3:::my-bucket"},
+				"principals": []interface{}{
+					map[string]interface{}{
+						"type":        "*",
+						"identifiers": []interface{}{"*"},
+					},
+				},
+				"condition": []interface{}{
+					map[string]interface{}{
+						"test":     "IpAddress",
+						"variable": "aws:sourceip",
+						"values":   []interface{}{"10.0.0.0/8"},
+					},
+				},
+			},
+		},
+	}
+	d := schema.TestResourceDataRaw(t, dataSourceYandexStorageBucketPolicyDocument().Schema, raw)
+
+	if err := dataSourceYandexStorageBucketPolicyDocumentRead(d, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Sid": "ReadObjects",
+      "Effect": "Allow",
+      "Principal": {
+        "CanonicalUser": "ajeserviceaccount"
+      },
+      "Action": "s3:GetObject",
+      "Resource": "arn:aws:s3:::my-bucket/*"
+    },
+    {
+      "Effect": "Deny",
+      "Principal": "*",
+      "Action": [
+        "s3:DeleteObject",
+        "s3:PutObject"
+      ],
+      "Resource": [
+        "arn:aws:s3:::my-bucket",
+        "arn:aws:s3:::my-bucket/*"
+      ],
+      "Condition": {
+        "IpAddress": {
+          "aws:sourceip": "10.0.0.0/8"
+        }
+      }
+    }
+  ]
+}`
+	if actual := d.Get("json").(string); actual != expected {
+		t.Fatalf("Got:\n\n%s\n\nExpected:\n\n%s", actual, expected)
+	}
+	if d.Id() == "" {
+		t.Fatalf("expected ID to be set")
+	}
+}
+
+func TestExpandStoragePolicyPrincipals(t *testing.T) {
+	principals := func(ps ...map[string]interface{}) []interface{} {
+		var result []interface{}
+		for _, p := range ps {
+			result = append(result, map[string]interface{}{
+				"type":        p["type"],
+				"identifiers": schema.NewSet(schema.HashString, p["identifiers"].([]interface{})),
+			})
+		}
+		return result
+	}
+
+	tests := []struct {
+		name       string
+		principals []interface{}
+		expected   string
+		wantErr    string
+	}{
+		{
+			name:     "none",
+			expected: `null`,
+		},
+		{
+			name: "service accounts",
+			principals: principals(
+				map[string]interface{}{"type": "ServiceAccount", "identifiers": []interface{}{"sa2", "sa1"}},
+				map[string]interface{}{"type": "CanonicalUser", "identifiers": []interface{}{"user1"}},
+			),
+			expected: `{"CanonicalUser":["sa1","sa2","user1"]}`,
+		},
+		{
+			name: "everyone",
+			principals: principals(
+				map[string]interface{}{"type": "*", "identifiers": []interface{}{"*"}},
+			),
+			expected: `"*"`,
+		},
+		{
+			name: "everyone with identifier",
+			principals: principals(
+				map[string]interface{}{"type": "*", "identifiers": []interface{}{"sa1"}},
+			),
+			wantErr: `must have identifiers = ["*"]`,
+		},
+		{
+			name: "everyone with service account",
+			principals: principals(
+				map[string]interface{}{"type": "*", "identifiers": []interface{}{"*"}},
+				map[string]interface{}{"type": "ServiceAccount", "identifiers": []interface{}{"sa1"}},
+			),
+			wantErr: "can't be combined with other principals",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := expandStoragePolicyPrincipals(tt.principals)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			actual, err := json.Marshal(principal)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if string(actual) != tt.expected {
+				t.Fatalf("Got %s, expected %s", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDataSourceYandexStorageBucketPolicyDocumentDuplicateCondition(t *testing.T) {
+	condition := func(value string) map[string]interface{} {
+		return map[string]interface{}{
+			"test":     "IpAddress",
+			"variable": "aws:sourceip",
+			"values":   []interface{}{value},
+		}
+	}
+	raw := map[string]interface{}{
+		"statement": []interface{}{
+			map[string]interface{}{
+				"actions":   []interface{}{"s3:GetObject"},
+				"resources": []interface{}{"arn:aws:s3:::my-bucket/*"},
+				"condition": []interface{}{condition("10.0.0.0/8"), condition("192.168.0.0/16")},
+			},
+		},
+	}
+	d := schema.TestResourceDataRaw(t, dataSourceYandexStorageBucketPolicyDocument().Schema, raw)
+
+	err := dataSourceYandexStorageBucketPolicyDocumentRead(d, nil)
+	if err == nil || !strings.Contains(err.Error(), "specified more than once") {
+		t.Fatalf("expected duplicate condition error, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package yandex
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	"github.com/yandex-cloud/terraform-provider-yandex/yandex/internal/hashcode"
+)
+
+// Presigned URLs signed with Signature Version 4 can't be valid for more than 7 days.
+const storageObjectPresignMaxExpires = 7 * 24 * time.Hour
+
+func dataSourceYandexStorageObjectPresign() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceYandexStorageObjectPresignRead,
+
+		Schema: map[string]*schema.Schema{
+			"bucket": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"key": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"http_method": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  http.MethodGet,
+				ValidateFunc: validation.StringInSlice([]string{
+					http.MethodGet, http.MethodPut, http.MethodHead, http.MethodDelete,
+				}, false),
+			},
+
+			"expires": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "15m",
+				ValidateFunc: validateStorageObjectPresignExpires,
+			},
+
+			"access_key": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"secret_key": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+
+			"url": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceYandexStorageObjectPresignRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	s3conn, err := getS3Client(d, config)
+	if err != nil {
+		return fmt.Errorf("error getting storage client: %s", err)
+	}
+
+	bucket := d.Get("bucket").(string)
+	key := d.Get("key").(string)
+	method := d.Get("http_method").(string)
+
+	expires, err := time.ParseDuration(d.Get("expires").(string))
+	if err != nil {
+		return fmt.Errorf("error parsing expires: %s", err)
+	}
+
+	var req *request.Request
+	switch method {
+	case http.MethodGet:
+		req, _ = s3conn.GetObjectRequest(&s3.GetObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	case http.MethodPut:
+		req, _ = s3conn.PutObjectRequest(&s3.PutObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	case http.MethodHead:
+		req, _ = s3conn.HeadObjectRequest(&s3.HeadObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	case http.MethodDelete:
+		req, _ = s3conn.DeleteObjectRequest(&s3.DeleteObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	default:
+		return fmt.Errorf("unsupported http_method %q", method)
+	}
+
+	log.Printf("[DEBUG] Presigning %s request for storage object %q in bucket %q", method, key, bucket)
+
+	url, err := req.Presign(expires)
+	if err != nil {
+		return fmt.Errorf("error presigning %s request for storage object %q in bucket %q: %s", method, key, bucket, err)
+	}
+
+	d.Set("url", url)
+	d.SetId(strconv.Itoa(hashcode.String(fmt.Sprintf("%s/%s/%s", method, bucket, key))))
+
+	return nil
+}
+
+// validateStorageObjectPresignExpires checks that expires is a positive duration
+// not longer than the signature version 4 limit.
+func validateStorageObjectPresignExpires(v interface{}, k string) (ws []string, errors []error) {
+	expires, err := time.ParseDuration(v.(string))
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q must be a duration, e.g. \"15m\" or \"24h\": %s", k, err))
+		return
+	}
+	if expires <= 0 || expires > storageObjectPresignMaxExpires {
+		errors = append(errors, fmt.Errorf("%q must be positive and at most %s, got %s", k, storageObjectPresignMaxExpires, expires))
+	}
+	return
+}
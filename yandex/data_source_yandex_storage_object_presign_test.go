@@ -0,0 +1,77 @@
+package yandex
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestValidateStorageObjectPresignExpires(t *testing.T) {
+	cc := []struct {
+		expires string
+		wantErr bool
+	}{
+		{expires: "15m"},
+		{expires: "168h"},
+		{expires: "169h", wantErr: true},
+		{expires: "0s", wantErr: true},
+		{expires: "-1h", wantErr: true},
+		{expires: "7d", wantErr: true},
+	}
+
+	for _, c := range cc {
+		t.Run(c.expires, func(t *testing.T) {
+			_, errs := validateStorageObjectPresignExpires(c.expires, "expires")
+			if c.wantErr != (len(errs) > 0) {
+				t.Fatalf("validateStorageObjectPresignExpires(%q) errors = %v, want error: %v", c.expires, errs, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestDataSourceYandexStorageObjectPresignRead(t *testing.T) {
+	config := &Config{StorageEndpoint: "https://storage.example.net"}
+
+	for _, method := range []string{"GET", "PUT", "HEAD", "DELETE"} {
+		t.Run(method, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, dataSourceYandexStorageObjectPresign().Schema, map[string]interface{}{
+				"bucket":      "my-bucket",
+				"key":         "path/to/artifact.tar.gz",
+				"http_method": method,
+				"expires":     "1h",
+				"access_key":  "test-access-key",
+				"secret_key":  "test-secret-key",
+			})
+
+			if err := dataSourceYandexStorageObjectPresignRead(d, config); err != nil {
+				t.Fatalf("dataSourceYandexStorageObjectPresignRead() error = %v", err)
+			}
+
+			u, err := url.Parse(d.Get("url").(string))
+			if err != nil {
+				t.Fatalf("failed to parse presigned url: %v", err)
+			}
+			if !strings.HasSuffix(u.Host, "storage.example.net") ||
+				!strings.Contains(u.Host+u.Path, "my-bucket") ||
+				!strings.HasSuffix(u.Path, "/path/to/artifact.tar.gz") {
+				t.Fatalf("unexpected presigned url %s", u)
+			}
+
+			query := u.Query()
+			if got := query.Get("X-Amz-Expires"); got != "3600" {
+				t.Fatalf("X-Amz-Expires = %q, want %q", got, "3600")
+			}
+			if !strings.HasPrefix(query.Get("X-Amz-Credential"), "test-access-key/") {
+				t.Fatalf("X-Amz-Credential = %q, want it to start with the access key", query.Get("X-Amz-Credential"))
+			}
+			if query.Get("X-Amz-Signature") == "" {
+				t.Fatalf("presigned url %s has no signature", u)
+			}
+			if d.Id() == "" {
+				t.Fatalf("data source ID is not set")
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package yandex
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/vpc/v1"
+)
+
+func TestAccDataSourceVPCGateway(t *testing.T) {
+	t.Parallel()
+
+	gatewayName := acctest.RandomWithPrefix("tf-gateway")
+	gatewayDesc := "Description for test gateway"
+
+	folderID := getExampleFolderID()
+	var gateway vpc.Gateway
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckVPCRouteTableDestroy,
+			testAccCheckVPCGatewayDestroy,
+			testAccCheckVPCNetworkDestroy,
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceVPCGatewayConfig(gatewayName, gatewayDesc),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVPCGatewayExists("yandex_vpc_gateway.foo", &gateway),
+
+					testAccCheckResourceIDField("data.yandex_vpc_gateway.bar1", "gateway_id"),
+					resource.TestCheckResourceAttr("data.yandex_vpc_gateway.bar1", "name", gatewayName),
+					resource.TestCheckResourceAttr("data.yandex_vpc_gateway.bar1", "description", gatewayDesc),
+					resource.TestCheckResourceAttr("data.yandex_vpc_gateway.bar1", "folder_id", folderID),
+					resource.TestCheckResourceAttr("data.yandex_vpc_gateway.bar1", "labels.tf-label", "tf-label-value"),
+					resource.TestCheckResourceAttr("data.yandex_vpc_gateway.bar1", "shared_egress_gateway.#", "1"),
+					testAccCheckCreatedAtAttr("data.yandex_vpc_gateway.bar1"),
+
+					resource.TestCheckResourceAttrPair("data.yandex_vpc_gateway.bar2", "gateway_id",
+						"yandex_vpc_gateway.foo", "id"),
+					resource.TestCheckResourceAttr("data.yandex_vpc_gateway.bar2", "name", gatewayName),
+					resource.TestCheckResourceAttr("data.yandex_vpc_gateway.bar2", "folder_id", folderID),
+					resource.TestCheckResourceAttr("data.yandex_vpc_gateway.bar2", "shared_egress_gateway.#", "1"),
+
+					resource.TestCheckResourceAttr("data.yandex_vpc_route_table.rt", "static_route.#", "1"),
+					resource.TestCheckResourceAttr("data.yandex_vpc_route_table.rt", "static_route.0.destination_prefix", "0.0.0.0/0"),
+					resource.TestCheckResourceAttrPair("data.yandex_vpc_route_table.rt", "static_route.0.gateway_id",
+						"data.yandex_vpc_gateway.bar1", "gateway_id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceVPCGatewayConfig(name, desc string) string {
+	return fmt.Sprintf(`
+data "yandex_vpc_gateway" "bar1" {
+  gateway_id = "${yandex_vpc_gateway.foo.id}"
+}
+
+data "yandex_vpc_gateway" "bar2" {
+  name      = "${yandex_vpc_gateway.foo.name}"
+  folder_id = "${yandex_vpc_gateway.foo.folder_id}"
+}
+
+data "yandex_vpc_route_table" "rt" {
+  route_table_id = "${yandex_vpc_route_table.foo.id}"
+}
+
+resource "yandex_vpc_gateway" "foo" {
+  name        = "%s"
+  description = "%s"
+
+  labels = {
+    tf-label = "tf-label-value"
+  }
+
+  shared_egress_gateway {}
+}
+
+resource "yandex_vpc_network" "foo" {
+  name = "%s"
+}
+
+resource "yandex_vpc_route_table" "foo" {
+  name       = "%s"
+  network_id = "${yandex_vpc_network.foo.id}"
+
+  static_route {
+    destination_prefix = "0.0.0.0/0"
+    gateway_id         = "${yandex_vpc_gateway.foo.id}"
+  }
+}
+`, name, desc, acctest.RandomWithPrefix("tf-network"), acctest.RandomWithPrefix("tf-route-table"))
+}
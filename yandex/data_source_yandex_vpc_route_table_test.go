@@ -44,6 +44,14 @@ func TestAccDataSourceVPCRouteTable(t *testing.T) {
 					resource.TestCheckResourceAttr("data.yandex_vpc_route_table.bar1", "static_route.0.next_hop_address", "192.168.22.22"),
 					resource.TestCheckResourceAttrSet("data.yandex_vpc_route_table.bar1", "network_id"),
 					testAccCheckCreatedAtAttr("data.yandex_vpc_route_table.bar1"),
+
+					testAccDataSourceVPCRouteTableExists("data.yandex_vpc_route_table.bar2"),
+					resource.TestCheckResourceAttrPair("data.yandex_vpc_route_table.bar2", "route_table_id",
+						"yandex_vpc_route_table.foo1", "id"),
+					resource.TestCheckResourceAttr("data.yandex_vpc_route_table.bar2", "name", routeTableName1),
+					resource.TestCheckResourceAttr("data.yandex_vpc_route_table.bar2", "folder_id", folderID),
+					resource.TestCheckResourceAttr("data.yandex_vpc_route_table.bar2", "static_route.#", "1"),
+					resource.TestCheckResourceAttr("data.yandex_vpc_route_table.bar2", "static_route.0.next_hop_address", "192.168.22.22"),
 				),
 			},
 		},
@@ -86,6 +94,11 @@ data "yandex_vpc_route_table" "bar1" {
   route_table_id = "${yandex_vpc_route_table.foo1.id}"
 }
 
+data "yandex_vpc_route_table" "bar2" {
+  name      = "${yandex_vpc_route_table.foo1.name}"
+  folder_id = "${yandex_vpc_route_table.foo1.folder_id}"
+}
+
 resource "yandex_vpc_network" "foo" {
   name        = "%s"
   description = "description for test"
@@ -1,8 +1,10 @@
 package yandex
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/vpc/v1"
 	"github.com/yandex-cloud/go-sdk/sdkresolvers"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -42,6 +44,7 @@ func dataSourceYandexVPCSecurityGroup() *schema.Resource {
 
 			"labels": {
 				Type:     schema.TypeMap,
+				Optional: true,
 				Computed: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 				Set:      schema.HashString,
@@ -133,13 +136,31 @@ func dataSourceYandexSecurityGroupRule() *schema.Resource {
 func dataSourceYandexVPCSecurityGroupRead(d *schema.ResourceData, meta interface{}) error {
 	config := meta.(*Config)
 
+	_, idOk := d.GetOk("security_group_id")
+	_, nameOk := d.GetOk("name")
+	_, labelsOk := d.GetOk("labels")
+
+	if !idOk && !nameOk && labelsOk {
+		sgID, err := findVPCSecurityGroupByLabels(config.Context(), config, d)
+		if err != nil {
+			return err
+		}
+
+		if err := yandexVPCSecurityGroupRead(d, meta, sgID); err != nil {
+			return err
+		}
+
+		d.SetId(sgID)
+
+		return d.Set("security_group_id", sgID)
+	}
+
 	err := checkOneOf(d, "security_group_id", "name")
 	if err != nil {
 		return err
 	}
 
 	sgID := d.Get("security_group_id").(string)
-	_, nameOk := d.GetOk("name")
 
 	if nameOk {
 		sgID, err = resolveObjectID(config.Context(), config, d, sdkresolvers.SecurityGroupResolver)
@@ -156,3 +177,47 @@ func dataSourceYandexVPCSecurityGroupRead(d *schema.ResourceData, meta interface
 
 	return d.Set("security_group_id", sgID)
 }
+
+func findVPCSecurityGroupByLabels(ctx context.Context, config *Config, d *schema.ResourceData) (string, error) {
+	folderID, err := getFolderID(d, config)
+	if err != nil {
+		return "", err
+	}
+
+	var groups []*vpc.SecurityGroup
+	it := config.sdk.VPC().SecurityGroup().SecurityGroupIterator(ctx, &vpc.ListSecurityGroupsRequest{
+		FolderId: folderID,
+	})
+	for it.Next() {
+		groups = append(groups, it.Value())
+	}
+	if err := it.Error(); err != nil {
+		return "", fmt.Errorf("VPC security group: failed to list security groups in folder %q: %s", folderID, err)
+	}
+
+	labels := convertTypesMap(d.Get("labels"))
+
+	matched := filterVPCSecurityGroupsByLabels(groups, labels)
+	switch len(matched) {
+	case 0:
+		return "", fmt.Errorf("VPC security group: failed to find security group matching labels %v in folder %q", labels, folderID)
+	case 1:
+		return matched[0].Id, nil
+	default:
+		ids := make([]string, 0, len(matched))
+		for _, sg := range matched {
+			ids = append(ids, sg.Id)
+		}
+		return "", fmt.Errorf("VPC security group: more than one security group matches labels %v: %s", labels, getJoinedKeys(ids))
+	}
+}
+
+func filterVPCSecurityGroupsByLabels(groups []*vpc.SecurityGroup, labels map[string]string) []*vpc.SecurityGroup {
+	var result []*vpc.SecurityGroup
+	for _, sg := range groups {
+		if matchLabels(sg.Labels, labels) {
+			result = append(result, sg)
+		}
+	}
+	return result
+}
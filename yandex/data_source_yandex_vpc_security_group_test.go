@@ -3,6 +3,7 @@ package yandex
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -74,6 +75,67 @@ func TestAccDataSourceVPCSecurityGroup_byName(t *testing.T) {
 	})
 }
 
+func TestAccDataSourceVPCSecurityGroup_byLabels(t *testing.T) {
+	t.Parallel()
+
+	name := acctest.RandomWithPrefix("tf-sg")
+	desc := "Description for test"
+	folderID := getExampleFolderID()
+
+	var sg vpc.SecurityGroup
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVPCSecurityGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceVPCSecurityGroupByLabelsConfig(name, desc),
+				Check: resource.ComposeTestCheckFunc(
+					makeCheck(&sg, folderID, name, desc),
+					resource.TestCheckResourceAttrPair("data.yandex_vpc_security_group.sg1", "id", "yandex_vpc_security_group.sg", "id"),
+				),
+			},
+		},
+	})
+}
+
+func TestFilterVPCSecurityGroupsByLabels(t *testing.T) {
+	groups := []*vpc.SecurityGroup{
+		{Id: "sg1", Labels: map[string]string{"env": "prod", "team": "a"}},
+		{Id: "sg2", Labels: map[string]string{"env": "prod", "team": "b"}},
+		{Id: "sg3"},
+	}
+
+	ids := func(groups []*vpc.SecurityGroup) []string {
+		var result []string
+		for _, g := range groups {
+			result = append(result, g.Id)
+		}
+		return result
+	}
+
+	cc := []struct {
+		name     string
+		labels   map[string]string
+		expected []string
+	}{
+		{name: "single match", labels: map[string]string{"team": "a"}, expected: []string{"sg1"}},
+		{name: "multiple matches", labels: map[string]string{"env": "prod"}, expected: []string{"sg1", "sg2"}},
+		{name: "all labels must match", labels: map[string]string{"env": "prod", "team": "c"}, expected: nil},
+		{name: "no labels", labels: nil, expected: []string{"sg1", "sg2", "sg3"}},
+	}
+
+	for _, c := range cc {
+		t.Run(c.name, func(t *testing.T) {
+			actual := ids(filterVPCSecurityGroupsByLabels(groups, c.labels))
+			if !reflect.DeepEqual(actual, c.expected) {
+				t.Fatalf("Got %v, expected %v", actual, c.expected)
+			}
+		})
+	}
+}
+
 func testAccDataSourceVPCSecurityGroupExists(n string, sg *vpc.SecurityGroup) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		ds, ok := s.RootModule().Resources[n]
@@ -143,3 +205,32 @@ data "yandex_vpc_security_group" "sg1" {
   name = "${yandex_vpc_security_group.sg.name}"
 }
 `
+
+func testAccDataSourceVPCSecurityGroupByLabelsConfig(name, desc string) string {
+	return fmt.Sprintf(`
+resource "yandex_vpc_network" "net" {}
+
+resource "yandex_vpc_security_group" "sg" {
+  network_id  = "${yandex_vpc_network.net.id}"
+  name        = "%s"
+  description = "%s"
+
+  labels = {
+    tf-test-name = "%s"
+  }
+
+  ingress {
+    description    = "rule1 description"
+    protocol       = "TCP"
+    v4_cidr_blocks = ["10.0.1.0/24", "10.0.2.0/24"]
+    port           = 8080
+  }
+}
+
+data "yandex_vpc_security_group" "sg1" {
+  labels = {
+    tf-test-name = "${yandex_vpc_security_group.sg.labels["tf-test-name"]}"
+  }
+}
+`, name, desc, name)
+}
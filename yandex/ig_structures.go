@@ -306,8 +306,8 @@ func flattenInstanceGroupHealthChecks(ig *instancegroup.InstanceGroup) ([]map[st
 
 	for i, spec := range ig.HealthChecksSpec.HealthCheckSpecs {
 		specDict := map[string]interface{}{}
-		specDict["interval"] = int(spec.Interval.Seconds)
-		specDict["timeout"] = int(spec.Timeout.Seconds)
+		specDict["interval"] = int(spec.GetInterval().GetSeconds())
+		specDict["timeout"] = int(spec.GetTimeout().GetSeconds())
 		specDict["healthy_threshold"] = int(spec.HealthyThreshold)
 		specDict["unhealthy_threshold"] = int(spec.UnhealthyThreshold)
 
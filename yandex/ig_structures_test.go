@@ -9,6 +9,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1/instancegroup"
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/vpc/v1"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestParseInstanceGroupNetworkSettingsType(t *testing.T) {
@@ -91,6 +92,59 @@ func TestExpandInstanceGroupVariables(t *testing.T) {
 	}
 }
 
+func TestExpandInstanceGroupHealthCheckSpec(t *testing.T) {
+	raw := map[string]interface{}{
+		"health_check": []interface{}{
+			map[string]interface{}{
+				"interval":            10,
+				"timeout":             5,
+				"healthy_threshold":   3,
+				"unhealthy_threshold": 4,
+				"tcp_options": []interface{}{
+					map[string]interface{}{"port": 22},
+				},
+			},
+			map[string]interface{}{
+				"http_options": []interface{}{
+					map[string]interface{}{"port": 8080, "path": "/health"},
+				},
+			},
+		},
+		"max_checking_health_duration": 60,
+	}
+	d := schema.TestResourceDataRaw(t, resourceYandexComputeInstanceGroup().Schema, raw)
+
+	result, err := expandInstanceGroupHealthCheckSpec(d)
+	if err != nil {
+		t.Fatalf("bad: %#v", err)
+	}
+
+	expected := &instancegroup.HealthChecksSpec{
+		HealthCheckSpecs: []*instancegroup.HealthCheckSpec{
+			{
+				Interval:           &duration.Duration{Seconds: 10},
+				Timeout:            &duration.Duration{Seconds: 5},
+				HealthyThreshold:   3,
+				UnhealthyThreshold: 4,
+				HealthCheckOptions: &instancegroup.HealthCheckSpec_TcpOptions_{
+					TcpOptions: &instancegroup.HealthCheckSpec_TcpOptions{Port: 22},
+				},
+			},
+			{
+				HealthyThreshold:   2,
+				UnhealthyThreshold: 2,
+				HealthCheckOptions: &instancegroup.HealthCheckSpec_HttpOptions_{
+					HttpOptions: &instancegroup.HealthCheckSpec_HttpOptions{Port: 8080, Path: "/health"},
+				},
+			},
+		},
+		MaxCheckingHealthDuration: &duration.Duration{Seconds: 60},
+	}
+	if !proto.Equal(result, expected) {
+		t.Fatalf("Got:\n\n%v\n\nExpected:\n\n%v\n", result, expected)
+	}
+}
+
 func TestFlattenInstanceGroupNetworkSettings(t *testing.T) {
 	cases := []struct {
 		name     string
@@ -1059,3 +1113,83 @@ func TestFlattenInstanceGroupPlacementPolicy(t *testing.T) {
 		})
 	}
 }
+
+func TestInstanceGroupDeployPolicyStrategy(t *testing.T) {
+	for _, strategy := range []string{"proactive", "opportunistic"} {
+		t.Run(strategy, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceYandexComputeInstanceGroup().Schema, map[string]interface{}{
+				"deploy_policy": []interface{}{
+					map[string]interface{}{
+						"max_unavailable": 1,
+						"max_expansion":   0,
+						"strategy":        strategy,
+					},
+				},
+			})
+
+			policy, err := expandInstanceGroupDeployPolicy(d)
+			if err != nil {
+				t.Fatalf("expandInstanceGroupDeployPolicy() error = %v", err)
+			}
+
+			res, err := flattenInstanceGroupDeployPolicy(&instancegroup.InstanceGroup{DeployPolicy: policy})
+			if err != nil {
+				t.Fatalf("flattenInstanceGroupDeployPolicy() error = %v", err)
+			}
+			if got := res[0]["strategy"]; got != strategy {
+				t.Errorf("flattenInstanceGroupDeployPolicy() strategy = %v, want %v", got, strategy)
+			}
+		})
+	}
+}
+
+func TestInstanceGroupBothLoadBalancers(t *testing.T) {
+	raw := map[string]interface{}{
+		"load_balancer": []interface{}{
+			map[string]interface{}{"target_group_name": "nlb-tg"},
+		},
+		"application_load_balancer": []interface{}{
+			map[string]interface{}{"target_group_name": "alb-tg"},
+		},
+	}
+	d := schema.TestResourceDataRaw(t, resourceYandexComputeInstanceGroup().Schema, raw)
+
+	lbSpec, err := expandInstanceGroupLoadBalancerSpec(d)
+	if err != nil {
+		t.Fatalf("bad: %#v", err)
+	}
+	albSpec, err := expandInstanceGroupApplicationLoadBalancerSpec(d)
+	if err != nil {
+		t.Fatalf("bad: %#v", err)
+	}
+	if lbSpec.GetTargetGroupSpec().GetName() != "nlb-tg" {
+		t.Fatalf("Got load balancer spec %#v, expected target group 'nlb-tg'", lbSpec)
+	}
+	if albSpec.GetTargetGroupSpec().GetName() != "alb-tg" {
+		t.Fatalf("Got application load balancer spec %#v, expected target group 'alb-tg'", albSpec)
+	}
+
+	ig := &instancegroup.InstanceGroup{
+		LoadBalancerSpec:            lbSpec,
+		LoadBalancerState:           &instancegroup.LoadBalancerState{TargetGroupId: "nlb-tg-id"},
+		ApplicationLoadBalancerSpec: albSpec,
+		ApplicationLoadBalancerState: &instancegroup.ApplicationLoadBalancerState{
+			TargetGroupId: "alb-tg-id",
+		},
+	}
+
+	lb, err := flattenInstanceGroupLoadBalancerSpec(ig)
+	if err != nil {
+		t.Fatalf("bad: %#v", err)
+	}
+	alb, err := flattenInstanceGroupApplicationLoadBalancerSpec(ig)
+	if err != nil {
+		t.Fatalf("bad: %#v", err)
+	}
+	if lb[0]["target_group_id"] != "nlb-tg-id" {
+		t.Fatalf("Got:\n\n%#v\n\nExpected target_group_id 'nlb-tg-id'", lb)
+	}
+	if alb[0]["target_group_id"] != "alb-tg-id" {
+		t.Fatalf("Got:\n\n%#v\n\nExpected target_group_id 'alb-tg-id'", alb)
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"log"
 	"reflect"
+	"sort"
 
 	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"google.golang.org/genproto/googleapis/type/timeofday"
 	"google.golang.org/genproto/protobuf/field_mask"
@@ -161,6 +163,22 @@ func clickHouseShardHash(v interface{}) int {
 	return hashcode.String(buf.String())
 }
 
+func clickHouseCompressionHash(v interface{}) int {
+	var buf bytes.Buffer
+
+	m := v.(map[string]interface{})
+	if n, ok := m["method"]; ok {
+		buf.WriteString(fmt.Sprintf("%s-", n.(string)))
+	}
+	if n, ok := m["min_part_size"]; ok {
+		buf.WriteString(fmt.Sprintf("%v-", n))
+	}
+	if n, ok := m["min_part_size_ratio"]; ok {
+		buf.WriteString(fmt.Sprintf("%v-", n))
+	}
+	return hashcode.String(buf.String())
+}
+
 func clickHouseDatabaseHash(v interface{}) int {
 	m := v.(map[string]interface{})
 
@@ -192,6 +210,7 @@ func clickHouseDatabasesDiff(currDBs []*clickhouse.Database, targetDBs []*clickh
 	for u := range toDelete {
 		toDel = append(toDel, u)
 	}
+	sort.Strings(toDel)
 
 	return toDel, toAdd
 }
@@ -219,6 +238,7 @@ func clickHouseUsersDiff(currUsers []*clickhouse.User, targetUsers []*clickhouse
 	for u := range toDelete {
 		toDel = append(toDel, u)
 	}
+	sort.Strings(toDel)
 
 	return toDel, toAdd
 }
@@ -489,6 +509,36 @@ func parseClickHouseHostType(t string) (clickhouse.Host_Type, error) {
 	return clickhouse.Host_Type(v), nil
 }
 
+func parseClickHouseCompressionMethod(m string) (clickhouseConfig.ClickhouseConfig_Compression_Method, error) {
+	v, ok := clickhouseConfig.ClickhouseConfig_Compression_Method_value[m]
+	// do not allow METHOD_UNSPECIFIED
+	if !ok || v == 0 {
+		return 0, fmt.Errorf("value for 'method' must be one of %s, not `%s`",
+			getJoinedKeys(getEnumValueMapKeysExt(clickhouseConfig.ClickhouseConfig_Compression_Method_value, true)), m)
+	}
+	return clickhouseConfig.ClickhouseConfig_Compression_Method(v), nil
+}
+
+func parseClickHouseKafkaSecurityProtocol(p string) (clickhouseConfig.ClickhouseConfig_Kafka_SecurityProtocol, error) {
+	v, ok := clickhouseConfig.ClickhouseConfig_Kafka_SecurityProtocol_value[p]
+	// do not allow SECURITY_PROTOCOL_UNSPECIFIED
+	if !ok || v == 0 {
+		return 0, fmt.Errorf("value for 'security_protocol' must be one of %s, not `%s`",
+			getJoinedKeys(getEnumValueMapKeysExt(clickhouseConfig.ClickhouseConfig_Kafka_SecurityProtocol_value, true)), p)
+	}
+	return clickhouseConfig.ClickhouseConfig_Kafka_SecurityProtocol(v), nil
+}
+
+func parseClickHouseKafkaSaslMechanism(m string) (clickhouseConfig.ClickhouseConfig_Kafka_SaslMechanism, error) {
+	v, ok := clickhouseConfig.ClickhouseConfig_Kafka_SaslMechanism_value[m]
+	// do not allow SASL_MECHANISM_UNSPECIFIED
+	if !ok || v == 0 {
+		return 0, fmt.Errorf("value for 'sasl_mechanism' must be one of %s, not `%s`",
+			getJoinedKeys(getEnumValueMapKeysExt(clickhouseConfig.ClickhouseConfig_Kafka_SaslMechanism_value, true)), m)
+	}
+	return clickhouseConfig.ClickhouseConfig_Kafka_SaslMechanism(v), nil
+}
+
 func expandClickHouseHosts(d *schema.ResourceData) ([]*clickhouse.HostSpec, error) {
 	var result []*clickhouse.HostSpec
 	hosts := d.Get("host").([]interface{})
@@ -553,7 +603,7 @@ func flattenClickHouseResources(r *clickhouse.Resources) ([]map[string]interface
 
 	res["resource_preset_id"] = r.ResourcePresetId
 	res["disk_type_id"] = r.DiskTypeId
-	res["disk_size"] = toGigabytes(r.DiskSize)
+	res["disk_size"] = toGigabytesRoundUp(r.DiskSize)
 
 	return []map[string]interface{}{res}, nil
 }
@@ -592,6 +642,36 @@ func flattenClickhouseMergeTreeConfig(c *clickhouseConfig.ClickhouseConfig_Merge
 	if c.TtlOnlyDropParts != nil {
 		res["ttl_only_drop_parts"] = c.TtlOnlyDropParts.Value
 	}
+	if c.InactivePartsToDelayInsert != nil {
+		res["inactive_parts_to_delay_insert"] = c.InactivePartsToDelayInsert.Value
+	}
+	if c.InactivePartsToThrowInsert != nil {
+		res["inactive_parts_to_throw_insert"] = c.InactivePartsToThrowInsert.Value
+	}
+	if c.MaxBytesToMergeAtMaxSpaceInPool != nil {
+		res["max_bytes_to_merge_at_max_space_in_pool"] = c.MaxBytesToMergeAtMaxSpaceInPool.Value
+	}
+	if c.AllowRemoteFsZeroCopyReplication != nil {
+		res["allow_remote_fs_zero_copy_replication"] = c.AllowRemoteFsZeroCopyReplication.Value
+	}
+	if c.MergeWithTtlTimeout != nil {
+		res["merge_with_ttl_timeout"] = c.MergeWithTtlTimeout.Value
+	}
+	if c.MergeWithRecompressionTtlTimeout != nil {
+		res["merge_with_recompression_ttl_timeout"] = c.MergeWithRecompressionTtlTimeout.Value
+	}
+	if c.MaxPartsInTotal != nil {
+		res["max_parts_in_total"] = c.MaxPartsInTotal.Value
+	}
+	if c.MaxNumberOfMergesWithTtlInPool != nil {
+		res["max_number_of_merges_with_ttl_in_pool"] = c.MaxNumberOfMergesWithTtlInPool.Value
+	}
+	if c.CleanupDelayPeriod != nil {
+		res["cleanup_delay_period"] = c.CleanupDelayPeriod.Value
+	}
+	if c.NumberOfFreeEntriesInPoolToExecuteMutation != nil {
+		res["number_of_free_entries_in_pool_to_execute_mutation"] = c.NumberOfFreeEntriesInPoolToExecuteMutation.Value
+	}
 
 	return []map[string]interface{}{res}, nil
 }
@@ -634,7 +714,9 @@ func flattenClickhouseRabbitmqSettings(d *schema.ResourceData, c *clickhouseConf
 	if v, ok := d.GetOk("clickhouse.0.config.0.rabbitmq.0.password"); ok {
 		res["password"] = v.(string)
 	}
-	if v, ok := d.GetOk("clickhouse.0.config.0.rabbitmq.0.vhost"); ok {
+	if c.Vhost != "" {
+		res["vhost"] = c.Vhost
+	} else if v, ok := d.GetOk("clickhouse.0.config.0.rabbitmq.0.vhost"); ok {
 		res["vhost"] = v.(string)
 	}
 
@@ -644,7 +726,11 @@ func flattenClickhouseRabbitmqSettings(d *schema.ResourceData, c *clickhouseConf
 func flattenClickhouseCompressionSettings(c []*clickhouseConfig.ClickhouseConfig_Compression) ([]interface{}, error) {
 	var result []interface{}
 
-	for _, r := range c {
+	sorted := make([]*clickhouseConfig.ClickhouseConfig_Compression, len(c))
+	copy(sorted, c)
+	sortClickHouseCompressions(sorted)
+
+	for _, r := range sorted {
 		result = append(result, map[string]interface{}{
 			"method":              r.Method.String(),
 			"min_part_size":       r.MinPartSize,
@@ -783,6 +869,9 @@ func flattenClickHouseConfig(d *schema.ResourceData, c *clickhouseConfig.Clickho
 	if c.EffectiveConfig.TotalMemoryProfilerStep != nil {
 		res["total_memory_profiler_step"] = c.EffectiveConfig.TotalMemoryProfilerStep.Value
 	}
+	if c.EffectiveConfig.TotalMemoryTrackerSampleProbability != nil {
+		res["total_memory_tracker_sample_probability"] = c.EffectiveConfig.TotalMemoryTrackerSampleProbability.Value
+	}
 
 	mergeTreeSettings, err := flattenClickhouseMergeTreeConfig(c.EffectiveConfig.MergeTree)
 	if err != nil {
@@ -872,6 +961,36 @@ func expandClickhouseMergeTreeConfig(d *schema.ResourceData, rootKey string) (*c
 	if v, ok := d.GetOkExists(rootKey + ".ttl_only_drop_parts"); ok {
 		config.TtlOnlyDropParts = &wrappers.BoolValue{Value: v.(bool)}
 	}
+	if v, ok := d.GetOkExists(rootKey + ".inactive_parts_to_delay_insert"); ok {
+		config.InactivePartsToDelayInsert = &wrappers.Int64Value{Value: int64(v.(int))}
+	}
+	if v, ok := d.GetOkExists(rootKey + ".inactive_parts_to_throw_insert"); ok {
+		config.InactivePartsToThrowInsert = &wrappers.Int64Value{Value: int64(v.(int))}
+	}
+	if v, ok := d.GetOkExists(rootKey + ".max_bytes_to_merge_at_max_space_in_pool"); ok {
+		config.MaxBytesToMergeAtMaxSpaceInPool = &wrappers.Int64Value{Value: int64(v.(int))}
+	}
+	if v, ok := d.GetOkExists(rootKey + ".allow_remote_fs_zero_copy_replication"); ok {
+		config.AllowRemoteFsZeroCopyReplication = &wrappers.BoolValue{Value: v.(bool)}
+	}
+	if v, ok := d.GetOkExists(rootKey + ".merge_with_ttl_timeout"); ok {
+		config.MergeWithTtlTimeout = &wrappers.Int64Value{Value: int64(v.(int))}
+	}
+	if v, ok := d.GetOkExists(rootKey + ".merge_with_recompression_ttl_timeout"); ok {
+		config.MergeWithRecompressionTtlTimeout = &wrappers.Int64Value{Value: int64(v.(int))}
+	}
+	if v, ok := d.GetOkExists(rootKey + ".max_parts_in_total"); ok {
+		config.MaxPartsInTotal = &wrappers.Int64Value{Value: int64(v.(int))}
+	}
+	if v, ok := d.GetOkExists(rootKey + ".max_number_of_merges_with_ttl_in_pool"); ok {
+		config.MaxNumberOfMergesWithTtlInPool = &wrappers.Int64Value{Value: int64(v.(int))}
+	}
+	if v, ok := d.GetOkExists(rootKey + ".cleanup_delay_period"); ok {
+		config.CleanupDelayPeriod = &wrappers.Int64Value{Value: int64(v.(int))}
+	}
+	if v, ok := d.GetOkExists(rootKey + ".number_of_free_entries_in_pool_to_execute_mutation"); ok {
+		config.NumberOfFreeEntriesInPoolToExecuteMutation = &wrappers.Int64Value{Value: int64(v.(int))}
+	}
 
 	return config, nil
 }
@@ -939,31 +1058,46 @@ func expandClickhouseRabbitmqSettings(d *schema.ResourceData, rootKey string) (*
 
 func expandClickhouseCompressionSettings(d *schema.ResourceData, rootKey string) ([]*clickhouseConfig.ClickhouseConfig_Compression, error) {
 	var result []*clickhouseConfig.ClickhouseConfig_Compression
-	compressions := d.Get(rootKey).([]interface{})
+	compressions, ok := d.Get(rootKey).(*schema.Set)
+	if !ok {
+		return result, nil
+	}
 
-	for i := range compressions {
-		keyPrefix := rootKey + fmt.Sprintf(".%d", i)
+	for _, c := range compressions.List() {
+		m := c.(map[string]interface{})
 		compression := &clickhouseConfig.ClickhouseConfig_Compression{}
 
-		if v, ok := d.GetOk(keyPrefix + ".method"); ok {
-			if val, err := expandEnum("method", v.(string), clickhouseConfig.ClickhouseConfig_Compression_Method_value); val != nil && err == nil {
-				compression.Method = clickhouseConfig.ClickhouseConfig_Compression_Method(*val)
-			} else {
+		if v, ok := m["method"]; ok {
+			method, err := parseClickHouseCompressionMethod(v.(string))
+			if err != nil {
 				return nil, err
 			}
+			compression.Method = method
 		}
-		if v, ok := d.GetOkExists(keyPrefix + ".min_part_size"); ok {
+		if v, ok := m["min_part_size"]; ok {
 			compression.MinPartSize = int64(v.(int))
 		}
-		if v, ok := d.GetOkExists(keyPrefix + ".min_part_size_ratio"); ok {
+		if v, ok := m["min_part_size_ratio"]; ok {
 			compression.MinPartSizeRatio = v.(float64)
 		}
 
 		result = append(result, compression)
 	}
+
+	sortClickHouseCompressions(result)
 	return result, nil
 }
 
+// Sorts compression rules by min_part_size, so the order doesn't depend on the set hashes.
+func sortClickHouseCompressions(c []*clickhouseConfig.ClickhouseConfig_Compression) {
+	sort.SliceStable(c, func(i, j int) bool {
+		if c[i].MinPartSize != c[j].MinPartSize {
+			return c[i].MinPartSize < c[j].MinPartSize
+		}
+		return c[i].MinPartSizeRatio < c[j].MinPartSizeRatio
+	})
+}
+
 func expandClickhouseGraphiteRollupSettings(d *schema.ResourceData, rootKey string) ([]*clickhouseConfig.ClickhouseConfig_GraphiteRollup, error) {
 	var result []*clickhouseConfig.ClickhouseConfig_GraphiteRollup
 
@@ -1110,6 +1244,9 @@ func expandClickHouseConfig(d *schema.ResourceData, rootKey string) (*clickhouse
 	if v, ok := d.GetOk(rootKey + ".total_memory_profiler_step"); ok {
 		config.TotalMemoryProfilerStep = &wrappers.Int64Value{Value: int64(v.(int))}
 	}
+	if v, ok := d.GetOk(rootKey + ".total_memory_tracker_sample_probability"); ok {
+		config.TotalMemoryTrackerSampleProbability = &wrappers.DoubleValue{Value: v.(float64)}
+	}
 
 	mergeTreeSettings, err := expandClickhouseMergeTreeConfig(d, rootKey+".merge_tree.0")
 	if err != nil {
@@ -1172,8 +1309,8 @@ func expandClickHouseSpec(d *schema.ResourceData) (*clickhouse.ConfigSpec_Clickh
 func flattenClickHouseBackupWindowStart(t *timeofday.TimeOfDay) []map[string]interface{} {
 	res := map[string]interface{}{}
 
-	res["hours"] = int(t.Hours)
-	res["minutes"] = int(t.Minutes)
+	res["hours"] = int(t.GetHours())
+	res["minutes"] = int(t.GetMinutes())
 
 	return []map[string]interface{}{res}
 }
@@ -1746,6 +1883,60 @@ func expandClickHouseUserSettingsExists(d *schema.ResourceData, hash int) *click
 	return result
 }
 
+// clickHouseUserConfiguredSettings returns the sorted names of settings explicitly set in the
+// configuration of the given user. It returns nil when the raw configuration is not available,
+// in which case settings should be taken from the resource data as is.
+func clickHouseUserConfiguredSettings(d *schema.ResourceData, userName string) []string {
+	rawConfig := d.GetRawConfig()
+	if rawConfig.IsNull() || !rawConfig.IsKnown() {
+		return nil
+	}
+
+	users := rawConfig.GetAttr("user")
+	if users.IsNull() || !users.IsKnown() {
+		return nil
+	}
+
+	for it := users.ElementIterator(); it.Next(); {
+		_, u := it.Element()
+		if u.IsNull() || !u.IsKnown() {
+			continue
+		}
+		name := u.GetAttr("name")
+		if name.IsNull() || !name.IsKnown() || name.AsString() != userName {
+			continue
+		}
+
+		configured := []string{}
+		settings := u.GetAttr("settings")
+		if settings.IsNull() || !settings.IsKnown() || settings.LengthInt() == 0 {
+			return configured
+		}
+		for key, value := range settings.Index(cty.NumberIntVal(0)).AsValueMap() {
+			if !value.IsNull() {
+				configured = append(configured, key)
+			}
+		}
+		sort.Strings(configured)
+		return configured
+	}
+
+	return nil
+}
+
+// expandClickHouseUserConfiguredSettings expands only the given user settings, so explicitly
+// configured zero values (e.g. false) are sent while unset settings are left to the server defaults.
+func expandClickHouseUserConfiguredSettings(d *schema.ResourceData, hash int, configured []string) *clickhouse.UserSettings {
+	rootKey := fmt.Sprintf("user.%d.settings.0", hash)
+
+	us := make(map[string]interface{}, len(configured))
+	for _, key := range configured {
+		us[key] = d.Get(rootKey + "." + key)
+	}
+
+	return expandClickHouseUserSettings(us)
+}
+
 func flattenClickHouseUserQuota(quota *clickhouse.UserQuota) map[string]interface{} {
 	p := map[string]interface{}{}
 	if quota.IntervalDuration != nil {
@@ -2131,7 +2322,11 @@ func expandClickHouseUser(u map[string]interface{}, d *schema.ResourceData, hash
 
 	if v, ok := u["settings"]; ok {
 		if d != nil {
-			user.Settings = expandClickHouseUserSettingsExists(d, hash)
+			if configured := clickHouseUserConfiguredSettings(d, user.Name); configured != nil {
+				user.Settings = expandClickHouseUserConfiguredSettings(d, hash, configured)
+			} else {
+				user.Settings = expandClickHouseUserSettingsExists(d, hash)
+			}
 		} else {
 			// for compare, when we have old Set without ResourceData
 			for _, settings := range v.([]interface{}) {
@@ -2164,7 +2359,7 @@ func expandClickHouseUserSpecs(d *schema.ResourceData) ([]*clickhouse.UserSpec,
 	return result, nil
 }
 
-func expandClickhouseShard(s map[string]interface{}, _ *schema.ResourceData, hash int) *clickhouse.ShardConfigSpec {
+func expandClickhouseShard(s map[string]interface{}, _ *schema.ResourceData, hash int, configured []string) *clickhouse.ShardConfigSpec {
 	shardFromSpec := &clickhouse.ShardConfigSpec{
 		Clickhouse: &clickhouse.ShardConfigSpec_Clickhouse{},
 	}
@@ -2174,15 +2369,60 @@ func expandClickhouseShard(s map[string]interface{}, _ *schema.ResourceData, has
 	}
 	shardFromSpec.Clickhouse.Resources = expandClickhouseShardResources(s)
 
+	shardFromSpec.Clickhouse.Config = expandClickhouseShardConfig(s, configured)
+
 	return shardFromSpec
 }
 
 func expandClickhouseShardSpecs(d *schema.ResourceData) (map[string]*clickhouse.ShardConfigSpec, error) {
 	rawShardsFromSpec := d.Get("shard").(*schema.Set)
-	return expandClickhouseShardSpecsFromSchema(rawShardsFromSpec)
+	return expandClickhouseShardSpecsFromSchema(rawShardsFromSpec, clickHouseShardConfiguredSettings(d))
+}
+
+// clickHouseShardConfiguredSettings returns the sorted names of config settings explicitly set in the
+// configuration of each shard, keyed by shard name. It returns nil when the raw configuration is not
+// available, in which case zero values of shard settings are treated as not set.
+func clickHouseShardConfiguredSettings(d *schema.ResourceData) map[string][]string {
+	rawConfig := d.GetRawConfig()
+	if rawConfig.IsNull() || !rawConfig.IsKnown() || !rawConfig.Type().HasAttribute("shard") {
+		return nil
+	}
+
+	shards := rawConfig.GetAttr("shard")
+	if shards.IsNull() || !shards.IsKnown() {
+		return nil
+	}
+
+	result := map[string][]string{}
+	for it := shards.ElementIterator(); it.Next(); {
+		_, shard := it.Element()
+		if shard.IsNull() || !shard.IsKnown() {
+			return nil
+		}
+		name := shard.GetAttr("name")
+		if name.IsNull() || !name.IsKnown() {
+			return nil
+		}
+
+		configured := []string{}
+		config := shard.GetAttr("config")
+		if !config.IsKnown() {
+			return nil
+		}
+		if !config.IsNull() && config.LengthInt() > 0 {
+			for key, value := range config.Index(cty.NumberIntVal(0)).AsValueMap() {
+				if !value.IsNull() {
+					configured = append(configured, key)
+				}
+			}
+		}
+		sort.Strings(configured)
+		result[name.AsString()] = configured
+	}
+	return result
 }
 
-func expandClickhouseShardSpecsFromSchema(rawShardsFromSpec *schema.Set) (map[string]*clickhouse.ShardConfigSpec, error) {
+func expandClickhouseShardSpecsFromSchema(rawShardsFromSpec *schema.Set, configured map[string][]string) (map[string]*clickhouse.ShardConfigSpec, error) {
 	resultShardsFromSpec := map[string]*clickhouse.ShardConfigSpec{}
 
 	log.Printf("[DEBUG] shards config from spec = %v\n", rawShardsFromSpec.List())
@@ -2190,13 +2430,22 @@ func expandClickhouseShardSpecsFromSchema(rawShardsFromSpec *schema.Set) (map[st
 		m := shard.(map[string]interface{})
 		hash := clickHouseShardHash(shard)
 		if v, ok := m["name"]; ok {
-			resultShardsFromSpec[v.(string)] = expandClickhouseShard(m, nil, hash)
+			var shardConfigured []string
+			if configured != nil {
+				shardConfigured = configured[v.(string)]
+				if shardConfigured == nil {
+					shardConfigured = []string{}
+				}
+			}
+			resultShardsFromSpec[v.(string)] = expandClickhouseShard(m, nil, hash, shardConfigured)
 		}
 	}
 	return resultShardsFromSpec, nil
 }
 
-func flattenClickHouseShards(shards []*clickhouse.Shard) ([]map[string]interface{}, error) {
+// flattenClickHouseShards flattens config overrides of a shard only if they are set for the shard
+// in the configuration or differ from the cluster config, so shards inheriting cluster settings have no diff.
+func flattenClickHouseShards(shards []*clickhouse.Shard, clusterConfig *clickhouseConfig.ClickhouseConfig, shardsFromSpec map[string]*clickhouse.ShardConfigSpec) ([]map[string]interface{}, error) {
 	var res []map[string]interface{}
 
 	for _, shard := range shards {
@@ -2215,6 +2464,15 @@ func flattenClickHouseShards(shards []*clickhouse.Shard) ([]map[string]interface
 			log.Printf("[DEBUG] read shard from cluster: shard=%s, resources=%v\n", shard.Name, resources)
 		}
 
+		var configured *clickhouseConfig.ClickhouseConfig
+		if spec, ok := shardsFromSpec[shard.Name]; ok {
+			configured = spec.Clickhouse.Config
+		}
+		shardConfig := flattenClickHouseShardConfig(shard.Config.Clickhouse.GetConfig().GetEffectiveConfig(), clusterConfig, configured)
+		if shardConfig != nil {
+			m["config"] = []map[string]interface{}{shardConfig}
+		}
+
 		res = append(res, m)
 	}
 
@@ -2309,6 +2567,45 @@ func parseClickHouseWeekDay(wd string) (clickhouse.WeeklyMaintenanceWindow_WeekD
 	return clickhouse.WeeklyMaintenanceWindow_WeekDay(val), nil
 }
 
+func parseClickHouseRescheduleType(rt string) (clickhouse.RescheduleMaintenanceRequest_RescheduleType, error) {
+	val, ok := clickhouse.RescheduleMaintenanceRequest_RescheduleType_value[rt]
+	// do not allow RESCHEDULE_TYPE_UNSPECIFIED
+	if !ok || val == 0 {
+		return clickhouse.RescheduleMaintenanceRequest_RESCHEDULE_TYPE_UNSPECIFIED,
+			fmt.Errorf("value for 'reschedule_type' should be one of %s, not `%s`",
+				getJoinedKeys(getEnumValueMapKeysExt(clickhouse.RescheduleMaintenanceRequest_RescheduleType_value, true)), rt)
+	}
+
+	return clickhouse.RescheduleMaintenanceRequest_RescheduleType(val), nil
+}
+
+func expandClickHouseRescheduleMaintenance(d *schema.ResourceData) (*clickhouse.RescheduleMaintenanceRequest, error) {
+	rt, ok := d.GetOk("reschedule_maintenance.0.reschedule_type")
+	if !ok {
+		return nil, nil
+	}
+
+	rescheduleType, err := parseClickHouseRescheduleType(rt.(string))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &clickhouse.RescheduleMaintenanceRequest{
+		ClusterId:      d.Id(),
+		RescheduleType: rescheduleType,
+	}
+
+	// delayed_until is set only with SPECIFIC_TIME, see clickHouseRescheduleMaintenanceDiffCustomize.
+	if delayedUntil, ok := d.GetOk("reschedule_maintenance.0.delayed_until"); ok {
+		result.DelayedUntil, err = parseTimestamp(delayedUntil.(string))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
 func expandClickHouseMaintenanceWindow(d *schema.ResourceData) (*clickhouse.MaintenanceWindow, error) {
 	mwType, ok := d.GetOk("maintenance_window.0.type")
 	if !ok {
@@ -2366,6 +2663,19 @@ func flattenClickHouseMaintenanceWindow(mw *clickhouse.MaintenanceWindow) []map[
 	return []map[string]interface{}{result}
 }
 
+func flattenClickHousePlannedOperation(op *clickhouse.MaintenanceOperation) []map[string]interface{} {
+	if op == nil {
+		return []map[string]interface{}{}
+	}
+
+	return []map[string]interface{}{
+		{
+			"info":          op.GetInfo(),
+			"delayed_until": getTimestamp(op.GetDelayedUntil()),
+		},
+	}
+}
+
 func flattenClickHouseHosts(hs []*clickhouse.Host) ([]map[string]interface{}, error) {
 	res := []map[string]interface{}{}
 
@@ -2377,12 +2687,62 @@ func flattenClickHouseHosts(hs []*clickhouse.Host) ([]map[string]interface{}, er
 		m["shard_name"] = h.ShardName
 		m["assign_public_ip"] = h.AssignPublicIp
 		m["fqdn"] = h.Name
+		m["health"] = h.GetHealth().String()
 		res = append(res, m)
 	}
 
 	return res, nil
 }
 
+// flattenClickHouseShardHosts groups FQDNs of CLICKHOUSE hosts by shard.
+// Shards and FQDNs are sorted by name to keep the attribute stable between refreshes.
+func flattenClickHouseShardHosts(hs []*clickhouse.Host) []map[string]interface{} {
+	fqdns := map[string][]string{}
+	for _, h := range hs {
+		if h.GetType() != clickhouse.Host_CLICKHOUSE {
+			continue
+		}
+		fqdns[h.ShardName] = append(fqdns[h.ShardName], h.Name)
+	}
+
+	shardNames := make([]string, 0, len(fqdns))
+	for name := range fqdns {
+		shardNames = append(shardNames, name)
+	}
+	sort.Strings(shardNames)
+
+	res := make([]map[string]interface{}, 0, len(shardNames))
+	for _, name := range shardNames {
+		sort.Strings(fqdns[name])
+		res = append(res, map[string]interface{}{
+			"shard_name": name,
+			"fqdns":      fqdns[name],
+		})
+	}
+	return res
+}
+
+// flattenClickHouseKeeperHosts returns sorted FQDNs of the hosts that run the
+// coordination service: ZooKeeper hosts and, for embedded Keeper, ClickHouse
+// hosts with a ZooKeeper service.
+func flattenClickHouseKeeperHosts(hs []*clickhouse.Host) []string {
+	fqdns := []string{}
+	for _, h := range hs {
+		if h.GetType() == clickhouse.Host_ZOOKEEPER {
+			fqdns = append(fqdns, h.Name)
+			continue
+		}
+		for _, s := range h.GetServices() {
+			if s.GetType() == clickhouse.Service_ZOOKEEPER {
+				fqdns = append(fqdns, h.Name)
+				break
+			}
+		}
+	}
+	sort.Strings(fqdns)
+	return fqdns
+}
+
 func expandClickHouseShardGroups(d *schema.ResourceData) ([]*clickhouse.ShardGroup, error) {
 	var result []*clickhouse.ShardGroup
 	groups := d.Get("shard_group").([]interface{})
@@ -2523,6 +2883,118 @@ func flattenClickHouseMlModels(schemas []*clickhouse.MlModel) ([]map[string]inte
 	return res, nil
 }
 
+// Settings of ClickHouse config which can be overridden for a shard, named as in the shard config block.
+var clickHouseShardConfigFields = map[string]func(*clickhouseConfig.ClickhouseConfig) **wrapperspb.Int64Value{
+	"max_connections":            func(c *clickhouseConfig.ClickhouseConfig) **wrapperspb.Int64Value { return &c.MaxConnections },
+	"max_concurrent_queries":     func(c *clickhouseConfig.ClickhouseConfig) **wrapperspb.Int64Value { return &c.MaxConcurrentQueries },
+	"keep_alive_timeout":         func(c *clickhouseConfig.ClickhouseConfig) **wrapperspb.Int64Value { return &c.KeepAliveTimeout },
+	"uncompressed_cache_size":    func(c *clickhouseConfig.ClickhouseConfig) **wrapperspb.Int64Value { return &c.UncompressedCacheSize },
+	"mark_cache_size":            func(c *clickhouseConfig.ClickhouseConfig) **wrapperspb.Int64Value { return &c.MarkCacheSize },
+	"max_table_size_to_drop":     func(c *clickhouseConfig.ClickhouseConfig) **wrapperspb.Int64Value { return &c.MaxTableSizeToDrop },
+	"max_partition_size_to_drop": func(c *clickhouseConfig.ClickhouseConfig) **wrapperspb.Int64Value { return &c.MaxPartitionSizeToDrop },
+	"background_pool_size":       func(c *clickhouseConfig.ClickhouseConfig) **wrapperspb.Int64Value { return &c.BackgroundPoolSize },
+	"background_schedule_pool_size": func(c *clickhouseConfig.ClickhouseConfig) **wrapperspb.Int64Value {
+		return &c.BackgroundSchedulePoolSize
+	},
+	"background_fetches_pool_size": func(c *clickhouseConfig.ClickhouseConfig) **wrapperspb.Int64Value {
+		return &c.BackgroundFetchesPoolSize
+	},
+}
+
+// clickHouseShardConfigFieldNames returns names of the shard config settings in a stable order.
+func clickHouseShardConfigFieldNames() []string {
+	names := make([]string, 0, len(clickHouseShardConfigFields))
+	for name := range clickHouseShardConfigFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func clickHouseShardConfigField(c *clickhouseConfig.ClickhouseConfig, name string) **wrapperspb.Int64Value {
+	return clickHouseShardConfigFields[name](c)
+}
+
+func getClickHouseShardConfigField(c *clickhouseConfig.ClickhouseConfig, name string) *wrapperspb.Int64Value {
+	if c == nil {
+		return nil
+	}
+	return *clickHouseShardConfigField(c, name)
+}
+
+// expandClickhouseShardConfig returns nil if the shard has no config overrides.
+// Only configured settings are expanded, so explicit zero values are sent. If configured is nil,
+// zero values are treated as not set, since they can't be distinguished inside a shard set element.
+func expandClickhouseShardConfig(s map[string]interface{}, configured []string) *clickhouseConfig.ClickhouseConfig {
+	v, ok := s["config"].([]interface{})
+	if !ok || len(v) == 0 || v[0] == nil {
+		return nil
+	}
+	spec := v[0].(map[string]interface{})
+
+	names := configured
+	if names == nil {
+		names = clickHouseShardConfigFieldNames()
+	}
+
+	var result *clickhouseConfig.ClickhouseConfig
+	for _, name := range names {
+		if _, ok := clickHouseShardConfigFields[name]; !ok {
+			continue
+		}
+		value, ok := spec[name].(int)
+		if !ok || (configured == nil && value == 0) {
+			continue
+		}
+		if result == nil {
+			result = &clickhouseConfig.ClickhouseConfig{}
+		}
+		*clickHouseShardConfigField(result, name) = &wrapperspb.Int64Value{Value: int64(value)}
+	}
+	return result
+}
+
+func flattenClickHouseShardConfig(shardConfig, clusterConfig, configured *clickhouseConfig.ClickhouseConfig) map[string]interface{} {
+	var result map[string]interface{}
+	for _, name := range clickHouseShardConfigFieldNames() {
+		value := getClickHouseShardConfigField(shardConfig, name)
+		if value == nil {
+			continue
+		}
+		if getClickHouseShardConfigField(configured, name) == nil && value.GetValue() == getClickHouseShardConfigField(clusterConfig, name).GetValue() {
+			continue
+		}
+		if result == nil {
+			result = map[string]interface{}{}
+		}
+		result[name] = int(value.GetValue())
+	}
+	return result
+}
+
+// clickHouseShardConfigUpdatePaths returns update paths of shard config settings which differ from current ones.
+// Settings which are not set in shardSpec are reverted to the values of clusterConfig.
+func clickHouseShardConfigUpdatePaths(current *clickhouseConfig.ClickhouseConfig, shardSpec *clickhouse.ShardConfigSpec, clusterConfig *clickhouseConfig.ClickhouseConfig) []string {
+	var paths []string
+	for _, name := range clickHouseShardConfigFieldNames() {
+		target := getClickHouseShardConfigField(shardSpec.Clickhouse.Config, name)
+		if target == nil {
+			target = getClickHouseShardConfigField(clusterConfig, name)
+			if target == nil {
+				continue
+			}
+			if shardSpec.Clickhouse.Config == nil {
+				shardSpec.Clickhouse.Config = &clickhouseConfig.ClickhouseConfig{}
+			}
+			*clickHouseShardConfigField(shardSpec.Clickhouse.Config, name) = target
+		}
+		if target.GetValue() != getClickHouseShardConfigField(current, name).GetValue() {
+			paths = append(paths, "config_spec.clickhouse.config."+name)
+		}
+	}
+	return paths
+}
+
 func expandClickhouseShardResources(s map[string]interface{}) *clickhouse.Resources {
 	valueResources, ok := s["resources"]
 	if !ok {
@@ -2,11 +2,18 @@ package yandex
 
 import (
 	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/mdb/clickhouse/v1"
+	cfg "github.com/yandex-cloud/go-genproto/yandex/cloud/mdb/clickhouse/v1/config"
+	"google.golang.org/genproto/googleapis/type/timeofday"
 )
 
 func Test_clickHouseHostsDiff(t *testing.T) {
@@ -730,3 +737,535 @@ var targetHostsZooIn2Subnets = []*clickhouse.HostSpec{
 		SubnetId:  "subnet-a",
 	},
 }
+
+func Test_clickHouseDatabasesDiff(t *testing.T) {
+	currDBs := []*clickhouse.Database{
+		{Name: "testdb"},
+		{Name: "olddb2"},
+		{Name: "olddb1"},
+	}
+	targetDBs := []*clickhouse.DatabaseSpec{
+		{Name: "testdb"},
+		{Name: "newdb"},
+	}
+
+	toDelete, toAdd := clickHouseDatabasesDiff(currDBs, targetDBs)
+	require.Equal(t, []string{"olddb1", "olddb2"}, toDelete)
+	require.Equal(t, []string{"newdb"}, toAdd)
+
+	toDelete, toAdd = clickHouseDatabasesDiff(currDBs[:1], targetDBs)
+	require.Empty(t, toDelete, "existing database must not be dropped")
+	require.Equal(t, []string{"newdb"}, toAdd)
+}
+
+func Test_clickHouseUsersDiff(t *testing.T) {
+	currUsers := []*clickhouse.User{
+		{Name: "john"},
+		{Name: "bob"},
+		{Name: "alice"},
+	}
+	targetUsers := []*clickhouse.UserSpec{
+		{Name: "john", Password: "new_password"},
+		{Name: "mary", Password: "password"},
+	}
+
+	toDelete, toAdd := clickHouseUsersDiff(currUsers, targetUsers)
+	require.Equal(t, []string{"alice", "bob"}, toDelete)
+	require.Equal(t, []*clickhouse.UserSpec{{Name: "mary", Password: "password"}}, toAdd)
+
+	toDelete, toAdd = clickHouseUsersDiff(currUsers[:1], targetUsers[:1])
+	require.Empty(t, toDelete, "existing user must not be dropped")
+	require.Empty(t, toAdd, "existing user must not be re-created")
+}
+
+func Test_clickHouseChangedUsers(t *testing.T) {
+	users := func(specs ...map[string]interface{}) *schema.ResourceData {
+		list := make([]interface{}, 0, len(specs))
+		for _, spec := range specs {
+			list = append(list, spec)
+		}
+		return schema.TestResourceDataRaw(t, resourceYandexMDBClickHouseCluster().Schema, map[string]interface{}{
+			"user": list,
+		})
+	}
+	john := map[string]interface{}{"name": "john", "password": "password"}
+	mary := map[string]interface{}{"name": "mary", "password": "password"}
+
+	oldSpecs := users(john).Get("user").(*schema.Set)
+
+	d := users(john, mary)
+	require.Empty(t, clickHouseChangedUsers(oldSpecs, d.Get("user").(*schema.Set), d),
+		"adding a user must not touch the existing ones")
+
+	d = users(map[string]interface{}{"name": "john", "password": "new_password"}, mary)
+	changed := clickHouseChangedUsers(oldSpecs, d.Get("user").(*schema.Set), d)
+	require.Len(t, changed, 1)
+	require.Equal(t, "john", changed[0].Name)
+	require.Equal(t, "new_password", changed[0].Password)
+}
+
+func TestExpandClickHouseCompressionSettings_OrderIndependent(t *testing.T) {
+	lz4 := map[string]interface{}{
+		"method":              "LZ4",
+		"min_part_size":       1024,
+		"min_part_size_ratio": 0.5,
+	}
+	zstd := map[string]interface{}{
+		"method":              "ZSTD",
+		"min_part_size":       2048,
+		"min_part_size_ratio": 0.7,
+	}
+	rawConfig := func(compressions ...interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"clickhouse": []interface{}{map[string]interface{}{
+				"config": []interface{}{map[string]interface{}{
+					"compression": compressions,
+				}},
+			}},
+		}
+	}
+
+	expected := []*cfg.ClickhouseConfig_Compression{
+		{Method: cfg.ClickhouseConfig_Compression_LZ4, MinPartSize: 1024, MinPartSizeRatio: 0.5},
+		{Method: cfg.ClickhouseConfig_Compression_ZSTD, MinPartSize: 2048, MinPartSizeRatio: 0.7},
+	}
+
+	for _, raw := range []map[string]interface{}{rawConfig(lz4, zstd), rawConfig(zstd, lz4)} {
+		d := schema.TestResourceDataRaw(t, resourceYandexMDBClickHouseCluster().Schema, raw)
+		actual, err := expandClickhouseCompressionSettings(d, "clickhouse.0.config.0.compression")
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+
+		// Server may return rules in any order, flatten result must match the config regardless.
+		flattened, err := flattenClickhouseCompressionSettings([]*cfg.ClickhouseConfig_Compression{expected[1], expected[0]})
+		require.NoError(t, err)
+		require.Equal(t, "LZ4", flattened[0].(map[string]interface{})["method"])
+
+		require.NoError(t, d.Set("clickhouse", []map[string]interface{}{
+			{"config": []map[string]interface{}{{"compression": flattened}}},
+		}))
+		after, err := expandClickhouseCompressionSettings(d, "clickhouse.0.config.0.compression")
+		require.NoError(t, err)
+		require.Equal(t, expected, after)
+
+		configured := d.Get("clickhouse.0.config.0.compression").(*schema.Set)
+		fromConfig := schema.NewSet(clickHouseCompressionHash, []interface{}{lz4, zstd})
+		require.True(t, configured.Equal(fromConfig))
+	}
+}
+
+func TestClickHouseMergeTreeConfig_RoundTrip(t *testing.T) {
+	expected := &cfg.ClickhouseConfig_MergeTree{
+		ReplicatedDeduplicationWindow:                  &wrapperspb.Int64Value{Value: 100},
+		ReplicatedDeduplicationWindowSeconds:           &wrapperspb.Int64Value{Value: 604800},
+		PartsToDelayInsert:                             &wrapperspb.Int64Value{Value: 150},
+		PartsToThrowInsert:                             &wrapperspb.Int64Value{Value: 300},
+		InactivePartsToDelayInsert:                     &wrapperspb.Int64Value{Value: 101},
+		InactivePartsToThrowInsert:                     &wrapperspb.Int64Value{Value: 110},
+		MaxReplicatedMergesInQueue:                     &wrapperspb.Int64Value{Value: 16},
+		NumberOfFreeEntriesInPoolToLowerMaxSizeOfMerge: &wrapperspb.Int64Value{Value: 8},
+		MaxBytesToMergeAtMinSpaceInPool:                &wrapperspb.Int64Value{Value: 1048576},
+		MaxBytesToMergeAtMaxSpaceInPool:                &wrapperspb.Int64Value{Value: 161061273600},
+		MinBytesForWidePart:                            &wrapperspb.Int64Value{Value: 512},
+		MinRowsForWidePart:                             &wrapperspb.Int64Value{Value: 16},
+		TtlOnlyDropParts:                               &wrapperspb.BoolValue{Value: true},
+		AllowRemoteFsZeroCopyReplication:               &wrapperspb.BoolValue{Value: true},
+		MergeWithTtlTimeout:                            &wrapperspb.Int64Value{Value: 14400},
+		MergeWithRecompressionTtlTimeout:               &wrapperspb.Int64Value{Value: 14400},
+		MaxPartsInTotal:                                &wrapperspb.Int64Value{Value: 100000},
+		MaxNumberOfMergesWithTtlInPool:                 &wrapperspb.Int64Value{Value: 2},
+		CleanupDelayPeriod:                             &wrapperspb.Int64Value{Value: 30},
+		NumberOfFreeEntriesInPoolToExecuteMutation:     &wrapperspb.Int64Value{Value: 20},
+	}
+
+	flattened, err := flattenClickhouseMergeTreeConfig(expected)
+	require.NoError(t, err)
+
+	d := schema.TestResourceDataRaw(t, resourceYandexMDBClickHouseCluster().Schema, map[string]interface{}{})
+	require.NoError(t, d.Set("clickhouse", []map[string]interface{}{
+		{"config": []map[string]interface{}{{"merge_tree": flattened}}},
+	}))
+
+	actual, err := expandClickhouseMergeTreeConfig(d, "clickhouse.0.config.0.merge_tree.0")
+	require.NoError(t, err)
+	require.True(t, proto.Equal(expected, actual), "expected %v, got %v", expected, actual)
+}
+
+func TestClickHouseResources_DiskSizeRoundTrip(t *testing.T) {
+	expected := &clickhouse.Resources{
+		ResourcePresetId: "s2.micro",
+		DiskTypeId:       "network-ssd",
+		DiskSize:         toBytes(17),
+	}
+
+	flattened, err := flattenClickHouseResources(expected)
+	require.NoError(t, err)
+	assert.Equal(t, 17, flattened[0]["disk_size"])
+
+	d := schema.TestResourceDataRaw(t, resourceYandexMDBClickHouseCluster().Schema, map[string]interface{}{})
+	require.NoError(t, d.Set("clickhouse", []map[string]interface{}{{"resources": flattened}}))
+
+	actual := expandClickHouseResources(d, "clickhouse.0.resources.0")
+	require.True(t, proto.Equal(expected, actual), "expected %v, got %v", expected, actual)
+}
+
+func TestFlattenClickHouseResources_DiskSizeRoundsUp(t *testing.T) {
+	// 17 decimal gigabytes is not a whole number of binary gigabytes.
+	flattened, err := flattenClickHouseResources(&clickhouse.Resources{DiskSize: 17000000000})
+	require.NoError(t, err)
+	assert.Equal(t, 16, flattened[0]["disk_size"])
+
+	flattened, err = flattenClickHouseResources(&clickhouse.Resources{DiskSize: toBytes(16) + 1})
+	require.NoError(t, err)
+	assert.Equal(t, 17, flattened[0]["disk_size"])
+}
+
+func TestParseClickHouseCompressionMethod(t *testing.T) {
+	method, err := parseClickHouseCompressionMethod("ZSTD")
+	require.NoError(t, err)
+	require.Equal(t, cfg.ClickhouseConfig_Compression_ZSTD, method)
+
+	_, err = parseClickHouseCompressionMethod("METHOD_UNSPECIFIED")
+	require.Error(t, err)
+
+	_, err = parseClickHouseCompressionMethod("gzip")
+	require.Error(t, err)
+}
+
+func TestClickHouseKafkaSettingsValidators(t *testing.T) {
+	tests := []struct {
+		name     string
+		validate schema.SchemaValidateFunc
+		value    string
+		valid    bool
+	}{
+		{"security protocol plaintext", validateParsableValue(parseClickHouseKafkaSecurityProtocol), "SECURITY_PROTOCOL_PLAINTEXT", true},
+		{"security protocol sasl ssl", validateParsableValue(parseClickHouseKafkaSecurityProtocol), "SECURITY_PROTOCOL_SASL_SSL", true},
+		{"security protocol unspecified", validateParsableValue(parseClickHouseKafkaSecurityProtocol), "SECURITY_PROTOCOL_UNSPECIFIED", false},
+		{"security protocol without prefix", validateParsableValue(parseClickHouseKafkaSecurityProtocol), "SSL", false},
+		{"sasl mechanism scram", validateParsableValue(parseClickHouseKafkaSaslMechanism), "SASL_MECHANISM_SCRAM_SHA_512", true},
+		{"sasl mechanism gssapi", validateParsableValue(parseClickHouseKafkaSaslMechanism), "SASL_MECHANISM_GSSAPI", true},
+		{"sasl mechanism unspecified", validateParsableValue(parseClickHouseKafkaSaslMechanism), "SASL_MECHANISM_UNSPECIFIED", false},
+		{"sasl mechanism lowercase", validateParsableValue(parseClickHouseKafkaSaslMechanism), "sasl_mechanism_plain", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, es := tt.validate(tt.value, "kafka.0.field")
+			assert.Equal(t, tt.valid, len(es) == 0)
+		})
+	}
+}
+
+func TestClickHouseGraphiteRollupSettings_MultiplePatterns(t *testing.T) {
+	raw := map[string]interface{}{
+		"clickhouse": []interface{}{map[string]interface{}{
+			"config": []interface{}{map[string]interface{}{
+				"graphite_rollup": []interface{}{map[string]interface{}{
+					"name": "rollup1",
+					"pattern": []interface{}{
+						map[string]interface{}{
+							"regexp":   "abc",
+							"function": "func1",
+							"retention": []interface{}{
+								map[string]interface{}{"age": 1000, "precision": 3},
+								map[string]interface{}{"age": 2000, "precision": 6},
+							},
+						},
+						map[string]interface{}{
+							"regexp":   "def",
+							"function": "func2",
+							"retention": []interface{}{
+								map[string]interface{}{"age": 3000, "precision": 9},
+								map[string]interface{}{"age": 4000, "precision": 12},
+							},
+						},
+					},
+				}},
+			}},
+		}},
+	}
+
+	expected := []*cfg.ClickhouseConfig_GraphiteRollup{
+		{
+			Name: "rollup1",
+			Patterns: []*cfg.ClickhouseConfig_GraphiteRollup_Pattern{
+				{
+					Regexp:   "abc",
+					Function: "func1",
+					Retention: []*cfg.ClickhouseConfig_GraphiteRollup_Pattern_Retention{
+						{Age: 1000, Precision: 3},
+						{Age: 2000, Precision: 6},
+					},
+				},
+				{
+					Regexp:   "def",
+					Function: "func2",
+					Retention: []*cfg.ClickhouseConfig_GraphiteRollup_Pattern_Retention{
+						{Age: 3000, Precision: 9},
+						{Age: 4000, Precision: 12},
+					},
+				},
+			},
+		},
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceYandexMDBClickHouseCluster().Schema, raw)
+	actual, err := expandClickhouseGraphiteRollupSettings(d, "clickhouse.0.config.0.graphite_rollup")
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+
+	flattened, err := flattenClickhouseGraphiteRollupSettings(expected)
+	require.NoError(t, err)
+	require.NoError(t, d.Set("clickhouse", []map[string]interface{}{
+		{"config": []map[string]interface{}{{"graphite_rollup": flattened}}},
+	}))
+
+	roundTrip, err := expandClickhouseGraphiteRollupSettings(d, "clickhouse.0.config.0.graphite_rollup")
+	require.NoError(t, err)
+	require.Equal(t, expected, roundTrip)
+}
+
+func TestFlattenClickHousePlannedOperation(t *testing.T) {
+	require.Equal(t, []map[string]interface{}{}, flattenClickHousePlannedOperation(nil))
+
+	delayedUntil := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	op := &clickhouse.MaintenanceOperation{
+		Info:         "Upgrade ClickHouse version",
+		DelayedUntil: timestamppb.New(delayedUntil),
+	}
+	expected := []map[string]interface{}{
+		{
+			"info":          "Upgrade ClickHouse version",
+			"delayed_until": delayedUntil.Format(defaultTimeFormat),
+		},
+	}
+	require.Equal(t, expected, flattenClickHousePlannedOperation(op))
+
+	d := schema.TestResourceDataRaw(t, resourceYandexMDBClickHouseCluster().Schema, map[string]interface{}{})
+	require.NoError(t, d.Set("planned_operation", flattenClickHousePlannedOperation(op)))
+	require.Equal(t, "Upgrade ClickHouse version", d.Get("planned_operation.0.info"))
+}
+
+func TestExpandClickHouseRescheduleMaintenance(t *testing.T) {
+	expand := func(raw map[string]interface{}) (*clickhouse.RescheduleMaintenanceRequest, error) {
+		d := schema.TestResourceDataRaw(t, resourceYandexMDBClickHouseCluster().Schema, raw)
+		d.SetId("cid")
+		return expandClickHouseRescheduleMaintenance(d)
+	}
+
+	req, err := expand(map[string]interface{}{})
+	require.NoError(t, err)
+	require.Nil(t, req)
+
+	req, err = expand(map[string]interface{}{
+		"reschedule_maintenance": []interface{}{
+			map[string]interface{}{"reschedule_type": "NEXT_AVAILABLE_WINDOW"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &clickhouse.RescheduleMaintenanceRequest{
+		ClusterId:      "cid",
+		RescheduleType: clickhouse.RescheduleMaintenanceRequest_NEXT_AVAILABLE_WINDOW,
+	}, req)
+
+	delayedUntil := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	req, err = expand(map[string]interface{}{
+		"reschedule_maintenance": []interface{}{
+			map[string]interface{}{
+				"reschedule_type": "SPECIFIC_TIME",
+				"delayed_until":   delayedUntil.Format(defaultTimeFormat),
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, clickhouse.RescheduleMaintenanceRequest_SPECIFIC_TIME, req.RescheduleType)
+	require.True(t, delayedUntil.Equal(req.DelayedUntil.AsTime()))
+
+	_, err = parseClickHouseRescheduleType("RESCHEDULE_TYPE_UNSPECIFIED")
+	require.Error(t, err)
+}
+
+func TestClickHouseShardConfig(t *testing.T) {
+	shard := map[string]interface{}{
+		"name": "shard1",
+		"config": []interface{}{
+			map[string]interface{}{
+				"mark_cache_size":        10737418240,
+				"max_concurrent_queries": 200,
+				"max_connections":        0,
+			},
+		},
+	}
+	shardSpec := expandClickhouseShard(shard, nil, 0, nil)
+	require.Equal(t, &cfg.ClickhouseConfig{
+		MarkCacheSize:        &wrapperspb.Int64Value{Value: 10737418240},
+		MaxConcurrentQueries: &wrapperspb.Int64Value{Value: 200},
+	}, shardSpec.Clickhouse.Config)
+	require.Nil(t, expandClickhouseShardConfig(map[string]interface{}{"name": "shard2"}, nil))
+
+	// Explicitly configured zero values are sent.
+	require.Equal(t, &cfg.ClickhouseConfig{
+		MaxConnections: &wrapperspb.Int64Value{Value: 0},
+	}, expandClickhouseShardConfig(shard, []string{"max_connections"}))
+	require.Nil(t, expandClickhouseShardConfig(shard, []string{}))
+
+	clusterConfig := &cfg.ClickhouseConfig{
+		MarkCacheSize:        &wrapperspb.Int64Value{Value: 5368709120},
+		MaxConcurrentQueries: &wrapperspb.Int64Value{Value: 200},
+		MaxConnections:       &wrapperspb.Int64Value{Value: 4096},
+	}
+	shardConfig := &cfg.ClickhouseConfig{
+		MarkCacheSize:        &wrapperspb.Int64Value{Value: 10737418240},
+		MaxConcurrentQueries: &wrapperspb.Int64Value{Value: 200},
+		MaxConnections:       &wrapperspb.Int64Value{Value: 4096},
+	}
+	shards := []*clickhouse.Shard{
+		{
+			Name: "shard1",
+			Config: &clickhouse.ShardConfig{Clickhouse: &clickhouse.ShardConfig_Clickhouse{
+				Config: &cfg.ClickhouseConfigSet{EffectiveConfig: shardConfig},
+			}},
+		},
+		{
+			Name: "shard2",
+			Config: &clickhouse.ShardConfig{Clickhouse: &clickhouse.ShardConfig_Clickhouse{
+				Config: &cfg.ClickhouseConfigSet{EffectiveConfig: clusterConfig},
+			}},
+		},
+	}
+
+	// Settings equal to the cluster ones are kept only if they are configured for the shard.
+	flattened, err := flattenClickHouseShards(shards, clusterConfig, map[string]*clickhouse.ShardConfigSpec{"shard1": shardSpec})
+	require.NoError(t, err)
+	require.Equal(t, []map[string]interface{}{{
+		"mark_cache_size":        10737418240,
+		"max_concurrent_queries": 200,
+	}}, flattened[0]["config"])
+	require.NotContains(t, flattened[1], "config")
+
+	// Settings removed from the shard are reverted to the cluster ones.
+	emptyShardSpec := expandClickhouseShard(map[string]interface{}{"name": "shard1"}, nil, 0, nil)
+	require.Equal(t, []string{"config_spec.clickhouse.config.mark_cache_size"}, clickHouseShardConfigUpdatePaths(shardConfig, emptyShardSpec, clusterConfig))
+
+	require.Equal(t, []string{"config_spec.clickhouse.config.mark_cache_size"}, clickHouseShardConfigUpdatePaths(clusterConfig, shardSpec, clusterConfig))
+	require.Empty(t, clickHouseShardConfigUpdatePaths(shardConfig, shardSpec, clusterConfig))
+}
+
+func TestClickHouseShardConfigFields(t *testing.T) {
+	names := clickHouseShardConfigFieldNames()
+	require.Len(t, names, len(clickHouseShardConfigFields))
+
+	// Every setting must be backed by its own field of the config.
+	c := &cfg.ClickhouseConfig{}
+	for i, name := range names {
+		*clickHouseShardConfigField(c, name) = &wrapperspb.Int64Value{Value: int64(i + 1)}
+	}
+	for i, name := range names {
+		require.Equal(t, int64(i+1), getClickHouseShardConfigField(c, name).GetValue(), name)
+		require.Nil(t, getClickHouseShardConfigField(nil, name), name)
+	}
+}
+
+func TestClickHouseBackupWindowStart_RoundTrip(t *testing.T) {
+	require.Equal(t, []map[string]interface{}{{"hours": 0, "minutes": 0}}, flattenClickHouseBackupWindowStart(nil))
+
+	expected := &timeofday.TimeOfDay{Hours: 3, Minutes: 15}
+
+	d := schema.TestResourceDataRaw(t, resourceYandexMDBClickHouseCluster().Schema, map[string]interface{}{})
+	require.NoError(t, d.Set("backup_window_start", flattenClickHouseBackupWindowStart(expected)))
+
+	actual := expandClickHouseBackupWindowStart(d)
+	require.True(t, proto.Equal(expected, actual), "expected %v, got %v", expected, actual)
+}
+
+func TestFlattenClickHouseShardHosts(t *testing.T) {
+	hosts := []*clickhouse.Host{
+		{Name: "zk1.db.yandex.net", Type: clickhouse.Host_ZOOKEEPER},
+		{Name: "ch3.db.yandex.net", Type: clickhouse.Host_CLICKHOUSE, ShardName: "shard2"},
+		{Name: "ch2.db.yandex.net", Type: clickhouse.Host_CLICKHOUSE, ShardName: "shard1"},
+		{Name: "ch1.db.yandex.net", Type: clickhouse.Host_CLICKHOUSE, ShardName: "shard1"},
+	}
+
+	require.Equal(t, []map[string]interface{}{
+		{"shard_name": "shard1", "fqdns": []string{"ch1.db.yandex.net", "ch2.db.yandex.net"}},
+		{"shard_name": "shard2", "fqdns": []string{"ch3.db.yandex.net"}},
+	}, flattenClickHouseShardHosts(hosts))
+	require.Empty(t, flattenClickHouseShardHosts(nil))
+
+	d := schema.TestResourceDataRaw(t, resourceYandexMDBClickHouseCluster().Schema, map[string]interface{}{})
+	require.NoError(t, d.Set("shard_hosts", flattenClickHouseShardHosts(hosts)))
+	require.Equal(t, "ch2.db.yandex.net", d.Get("shard_hosts.0.fqdns.1"))
+}
+
+func TestFlattenClickHouseKeeperHosts(t *testing.T) {
+	keeperService := []*clickhouse.Service{
+		{Type: clickhouse.Service_CLICKHOUSE},
+		{Type: clickhouse.Service_ZOOKEEPER},
+	}
+	embedded := []*clickhouse.Host{
+		{Name: "ch3.db.yandex.net", Type: clickhouse.Host_CLICKHOUSE, Services: keeperService},
+		{Name: "ch4.db.yandex.net", Type: clickhouse.Host_CLICKHOUSE, Services: []*clickhouse.Service{{Type: clickhouse.Service_CLICKHOUSE}}},
+		{Name: "ch1.db.yandex.net", Type: clickhouse.Host_CLICKHOUSE, Services: keeperService},
+		{Name: "ch2.db.yandex.net", Type: clickhouse.Host_CLICKHOUSE, Services: keeperService},
+	}
+	require.Equal(t, []string{"ch1.db.yandex.net", "ch2.db.yandex.net", "ch3.db.yandex.net"}, flattenClickHouseKeeperHosts(embedded))
+
+	zookeeper := []*clickhouse.Host{
+		{Name: "ch1.db.yandex.net", Type: clickhouse.Host_CLICKHOUSE},
+		{Name: "zk1.db.yandex.net", Type: clickhouse.Host_ZOOKEEPER},
+	}
+	require.Equal(t, []string{"zk1.db.yandex.net"}, flattenClickHouseKeeperHosts(zookeeper))
+	require.Empty(t, flattenClickHouseKeeperHosts(nil))
+
+	d := schema.TestResourceDataRaw(t, resourceYandexMDBClickHouseCluster().Schema, map[string]interface{}{})
+	require.NoError(t, d.Set("keeper_hosts", flattenClickHouseKeeperHosts(embedded)))
+	require.Equal(t, "ch2.db.yandex.net", d.Get("keeper_hosts.1"))
+}
+
+func TestFlattenClickHouseHosts(t *testing.T) {
+	hosts := []*clickhouse.Host{
+		{
+			Name:      "rc1a-1.mdb.yandexcloud.net",
+			Type:      clickhouse.Host_CLICKHOUSE,
+			ZoneId:    "ru-central1-a",
+			SubnetId:  "subnet-a",
+			ShardName: "shard1",
+			Health:    clickhouse.Host_ALIVE,
+		},
+		{
+			Name:   "rc1b-1.mdb.yandexcloud.net",
+			Type:   clickhouse.Host_ZOOKEEPER,
+			ZoneId: "ru-central1-b",
+			Health: clickhouse.Host_DEGRADED,
+		},
+	}
+	expected := []map[string]interface{}{
+		{
+			"type":             "CLICKHOUSE",
+			"zone":             "ru-central1-a",
+			"subnet_id":        "subnet-a",
+			"shard_name":       "shard1",
+			"assign_public_ip": false,
+			"fqdn":             "rc1a-1.mdb.yandexcloud.net",
+			"health":           "ALIVE",
+		},
+		{
+			"type":             "ZOOKEEPER",
+			"zone":             "ru-central1-b",
+			"subnet_id":        "",
+			"shard_name":       "",
+			"assign_public_ip": false,
+			"fqdn":             "rc1b-1.mdb.yandexcloud.net",
+			"health":           "DEGRADED",
+		},
+	}
+
+	actual, err := flattenClickHouseHosts(hosts)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+
+	d := schema.TestResourceDataRaw(t, resourceYandexMDBClickHouseCluster().Schema, map[string]interface{}{})
+	require.NoError(t, d.Set("host", actual))
+	require.Equal(t, "DEGRADED", d.Get("host.1.health"))
+}
@@ -1,6 +1,7 @@
 package yandex
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -18,6 +19,26 @@ var weeklyMaintenanceWindow_WeekDay_value = map[string]int32{
 	"SUN":                  7,
 }
 
+// listMDBPages collects the items of every page returned by a paginated MDB List call.
+// fetch requests the page identified by pageToken and returns its items along with
+// the token of the next page, which is empty on the last one.
+func listMDBPages[T any](ctx context.Context, fetch func(ctx context.Context, pageToken string) ([]T, string, error)) ([]T, error) {
+	items := []T{}
+	pageToken := ""
+	for {
+		page, nextPageToken, err := fetch(ctx, pageToken)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+		if nextPageToken == "" {
+			break
+		}
+		pageToken = nextPageToken
+	}
+	return items, nil
+}
+
 func mdbMaintenanceWindowSchemaValidateFunc(v interface{}, k string) (s []string, es []error) {
 	dayString := v.(string)
 	day, ok := weeklyMaintenanceWindow_WeekDay_value[dayString]
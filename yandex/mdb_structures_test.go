@@ -0,0 +1,71 @@
+package yandex
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/mdb/clickhouse/v1"
+)
+
+type fakeClickHouseUserPager struct {
+	pages    map[string]*clickhouse.ListUsersResponse
+	requests []string
+}
+
+func (p *fakeClickHouseUserPager) List(_ context.Context, req *clickhouse.ListUsersRequest) (*clickhouse.ListUsersResponse, error) {
+	p.requests = append(p.requests, req.PageToken)
+	resp, ok := p.pages[req.PageToken]
+	if !ok {
+		return nil, fmt.Errorf("unexpected page token %q", req.PageToken)
+	}
+	return resp, nil
+}
+
+func TestListMDBPages(t *testing.T) {
+	pager := &fakeClickHouseUserPager{
+		pages: map[string]*clickhouse.ListUsersResponse{
+			"": {
+				Users:         []*clickhouse.User{{Name: "alice"}, {Name: "bob"}},
+				NextPageToken: "page-2",
+			},
+			"page-2": {
+				Users: []*clickhouse.User{{Name: "carol"}},
+			},
+		},
+	}
+
+	users, err := listMDBPages(context.Background(), func(ctx context.Context, pageToken string) ([]*clickhouse.User, string, error) {
+		resp, err := pager.List(ctx, &clickhouse.ListUsersRequest{
+			ClusterId: "cluster",
+			PageSize:  defaultMDBPageSize,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Users, resp.NextPageToken, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"", "page-2"}, pager.requests)
+
+	names := []string{}
+	for _, u := range users {
+		names = append(names, u.Name)
+	}
+	require.Equal(t, []string{"alice", "bob", "carol"}, names)
+}
+
+func TestListMDBPagesError(t *testing.T) {
+	calls := 0
+	_, err := listMDBPages(context.Background(), func(ctx context.Context, pageToken string) ([]string, string, error) {
+		calls++
+		if pageToken == "" {
+			return []string{"first"}, "page-2", nil
+		}
+		return nil, "", fmt.Errorf("boom")
+	})
+	require.EqualError(t, err, "boom")
+	require.Equal(t, 2, calls)
+}
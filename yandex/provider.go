@@ -181,7 +181,9 @@ func sdkProvider(emptyFolder bool) *schema.Provider {
 			"yandex_kms_asymmetric_encryption_key":                    dataSourceYandexKMSAsymmetricEncryptionKey(),
 			"yandex_kms_asymmetric_signature_key":                     dataSourceYandexKMSAsymmetricSignatureKey(),
 			"yandex_logging_group":                                    dataSourceYandexLoggingGroup(),
+			"yandex_mdb_clickhouse_backups":                           dataSourceYandexMDBClickHouseBackups(),
 			"yandex_mdb_clickhouse_cluster":                           dataSourceYandexMDBClickHouseCluster(),
+			"yandex_mdb_clickhouse_user":                              dataSourceYandexMDBClickHouseUser(),
 			"yandex_mdb_elasticsearch_cluster":                        dataSourceYandexMDBElasticsearchCluster(),
 			"yandex_mdb_greenplum_cluster":                            dataSourceYandexMDBGreenplumCluster(),
 			"yandex_mdb_kafka_cluster":                                dataSourceYandexMDBKafkaCluster(),
@@ -205,6 +207,8 @@ func sdkProvider(emptyFolder bool) *schema.Provider {
 			"yandex_resourcemanager_cloud":                            dataSourceYandexResourceManagerCloud(),
 			"yandex_resourcemanager_folder":                           dataSourceYandexResourceManagerFolder(),
 			"yandex_serverless_container":                             dataSourceYandexServerlessContainer(),
+			"yandex_storage_bucket_policy_document":                   dataSourceYandexStorageBucketPolicyDocument(),
+			"yandex_storage_object_presign":                           dataSourceYandexStorageObjectPresign(),
 			"yandex_vpc_address":                                      dataSourceYandexVPCAddress(),
 			"yandex_vpc_gateway":                                      dataSourceYandexVPCGateway(),
 			"yandex_vpc_network":                                      dataSourceYandexVPCNetwork(),
@@ -277,6 +281,7 @@ func sdkProvider(emptyFolder bool) *schema.Provider {
 			"yandex_lockbox_secret_iam_binding":                       resourceYandexLockboxSecretIAMBinding(),
 			"yandex_logging_group":                                    resourceYandexLoggingGroup(),
 			"yandex_mdb_clickhouse_cluster":                           resourceYandexMDBClickHouseCluster(),
+			"yandex_mdb_clickhouse_cluster_backup":                    resourceYandexMDBClickHouseClusterBackup(),
 			"yandex_mdb_elasticsearch_cluster":                        resourceYandexMDBElasticsearchCluster(),
 			"yandex_mdb_greenplum_cluster":                            resourceYandexMDBGreenplumCluster(),
 			"yandex_mdb_kafka_cluster":                                resourceYandexMDBKafkaCluster(),
@@ -312,6 +317,7 @@ func sdkProvider(emptyFolder bool) *schema.Provider {
 			"yandex_serverless_container_iam_binding":                 resourceYandexServerlessContainerIAMBinding(),
 			"yandex_storage_bucket":                                   resourceYandexStorageBucket(),
 			"yandex_storage_object":                                   resourceYandexStorageObject(),
+			"yandex_storage_object_copy":                              resourceYandexStorageObjectCopy(),
 			"yandex_vpc_address":                                      resourceYandexVPCAddress(),
 			"yandex_vpc_default_security_group":                       resourceYandexVPCDefaultSecurityGroup(),
 			"yandex_vpc_gateway":                                      resourceYandexVPCGateway(),
@@ -134,6 +134,13 @@ func resourceYandexComputeDisk() *schema.Resource {
 				Computed: true,
 			},
 
+			"snapshot_schedule_ids": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+
 			"created_at": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -200,6 +207,7 @@ func resourceYandexComputeDiskCreate(d *schema.ResourceData, meta interface{}) e
 		Size:                toBytes(d.Get("size").(int)),
 		BlockSize:           int64(d.Get("block_size").(int)),
 		DiskPlacementPolicy: diskPlacementPolicy,
+		SnapshotScheduleIds: expandStringSet(d.Get("snapshot_schedule_ids")),
 	}
 
 	if v, ok := d.GetOk("image_id"); ok {
@@ -260,6 +268,11 @@ func resourceYandexComputeDiskRead(d *schema.ResourceData, meta interface{}) err
 		return err
 	}
 
+	snapshotScheduleIDs, err := listDiskSnapshotScheduleIDs(config.Context(), config, d.Id())
+	if err != nil {
+		return err
+	}
+
 	d.Set("created_at", getTimestamp(disk.CreatedAt))
 	d.Set("name", disk.Name)
 	d.Set("folder_id", disk.FolderId)
@@ -277,6 +290,10 @@ func resourceYandexComputeDiskRead(d *schema.ResourceData, meta interface{}) err
 		return err
 	}
 
+	if err := d.Set("snapshot_schedule_ids", snapshotScheduleIDs); err != nil {
+		return err
+	}
+
 	return d.Set("labels", disk.Labels)
 }
 
@@ -396,6 +413,13 @@ func resourceYandexComputeDiskUpdate(d *schema.ResourceData, meta interface{}) e
 
 	}
 
+	snapshotSchedulesPropName := "snapshot_schedule_ids"
+	if d.HasChange(snapshotSchedulesPropName) {
+		if err := updateDiskSnapshotSchedules(d, meta); err != nil {
+			return err
+		}
+	}
+
 	d.Partial(false)
 
 	return resourceYandexComputeDiskRead(d, meta)
@@ -471,6 +495,71 @@ func makeDiskUpdateRequest(req *compute.UpdateDiskRequest, d *schema.ResourceDat
 	return nil
 }
 
+func listDiskSnapshotScheduleIDs(ctx context.Context, config *Config, diskID string) ([]string, error) {
+	var ids []string
+	pageToken := ""
+	for {
+		resp, err := config.sdk.Compute().Disk().ListSnapshotSchedules(ctx, &compute.ListDiskSnapshotSchedulesRequest{
+			DiskId:    diskID,
+			PageSize:  defaultListSize,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error while requesting API to list snapshot schedules of disk %q: %s", diskID, err)
+		}
+
+		for _, schedule := range resp.SnapshotSchedules {
+			ids = append(ids, schedule.Id)
+		}
+
+		pageToken = resp.NextPageToken
+		if pageToken == "" {
+			break
+		}
+	}
+	return ids, nil
+}
+
+func updateDiskSnapshotSchedules(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	ctx, cancel := context.WithTimeout(config.Context(), d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
+
+	o, n := d.GetChange("snapshot_schedule_ids")
+	oldSchedules, newSchedules := o.(*schema.Set), n.(*schema.Set)
+
+	var requests []*compute.UpdateSnapshotScheduleDisksRequest
+	for _, id := range convertStringSet(oldSchedules.Difference(newSchedules)) {
+		requests = append(requests, &compute.UpdateSnapshotScheduleDisksRequest{
+			SnapshotScheduleId: id,
+			Remove:             []string{d.Id()},
+		})
+	}
+	for _, id := range convertStringSet(newSchedules.Difference(oldSchedules)) {
+		requests = append(requests, &compute.UpdateSnapshotScheduleDisksRequest{
+			SnapshotScheduleId: id,
+			Add:                []string{d.Id()},
+		})
+	}
+
+	for _, req := range requests {
+		log.Printf("[DEBUG] Updating disks of SnapshotSchedule %q: add %v, remove %v", req.SnapshotScheduleId, req.Add, req.Remove)
+
+		op, err := config.sdk.WrapOperation(config.sdk.Compute().SnapshotSchedule().UpdateDisks(ctx, req))
+		if err != nil {
+			return fmt.Errorf("Error while requesting API to update disks of SnapshotSchedule %q: %s", req.SnapshotScheduleId, err)
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			return fmt.Errorf("Error updating disks of SnapshotSchedule %q: %s", req.SnapshotScheduleId, err)
+		}
+	}
+
+	return nil
+}
+
 func makeDiskMoveRequest(req *compute.MoveDiskRequest, d *schema.ResourceData, meta interface{}) error {
 	config := meta.(*Config)
 
@@ -142,6 +142,44 @@ func TestAccComputeDisk_update(t *testing.T) {
 	})
 }
 
+func TestAccComputeDisk_snapshotSchedules(t *testing.T) {
+	t.Parallel()
+
+	diskName := acctest.RandomWithPrefix("tf-test")
+	scheduleName := acctest.RandomWithPrefix("tf-test")
+	var disk compute.Disk
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeDiskDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccComputeDisk_snapshotSchedules(diskName, scheduleName, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeDiskExists("yandex_compute_disk.foobar", &disk),
+					resource.TestCheckResourceAttr("yandex_compute_disk.foobar", "snapshot_schedule_ids.#", "1"),
+					resource.TestCheckTypeSetElemAttrPair("yandex_compute_disk.foobar", "snapshot_schedule_ids.*", "yandex_compute_snapshot_schedule.foobar", "id"),
+					testAccCheckComputeDiskSnapshotSchedulesCount(&disk, 1),
+				),
+			},
+			{
+				Config: testAccComputeDisk_snapshotSchedules(diskName, scheduleName, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeDiskExists("yandex_compute_disk.foobar", &disk),
+					resource.TestCheckResourceAttr("yandex_compute_disk.foobar", "snapshot_schedule_ids.#", "0"),
+					testAccCheckComputeDiskSnapshotSchedulesCount(&disk, 0),
+				),
+			},
+			{
+				ResourceName:      "yandex_compute_disk.foobar",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func TestAccComputeDisk_fromSnapshot(t *testing.T) {
 	t.Parallel()
 
@@ -336,6 +374,32 @@ resource "yandex_compute_disk" "foobar" {
 `, diskName)
 }
 
+func testAccComputeDisk_snapshotSchedules(diskName, scheduleName string, attached bool) string {
+	snapshotScheduleIDs := ""
+	if attached {
+		snapshotScheduleIDs = "snapshot_schedule_ids = [yandex_compute_snapshot_schedule.foobar.id]"
+	}
+	return fmt.Sprintf(`
+resource "yandex_compute_snapshot_schedule" "foobar" {
+  name = "%s"
+
+  schedule_policy {
+    expression = "0 0 1 1 *"
+  }
+
+  snapshot_count = 1
+}
+
+resource "yandex_compute_disk" "foobar" {
+  name = "%s"
+  size = 4
+  type = "network-hdd"
+
+  %s
+}
+`, scheduleName, diskName, snapshotScheduleIDs)
+}
+
 func testAccComputeDisk_with_folder(diskName string, folderId string, allowRecreate bool) string {
 	return fmt.Sprintf(`
 data "yandex_compute_image" "ubuntu" {
@@ -472,3 +536,19 @@ resource "yandex_vpc_subnet" "bar-subnet" {
 }
 `, diskName, instanceName)
 }
+
+func testAccCheckComputeDiskSnapshotSchedulesCount(disk *compute.Disk, expected int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		config := testAccProvider.Meta().(*Config)
+
+		ids, err := listDiskSnapshotScheduleIDs(context.Background(), config, disk.Id)
+		if err != nil {
+			return err
+		}
+
+		if len(ids) != expected {
+			return fmt.Errorf("Disk %s is attached to %d snapshot schedules, expected %d", disk.Id, len(ids), expected)
+		}
+		return nil
+	}
+}
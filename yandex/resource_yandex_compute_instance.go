@@ -8,10 +8,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/mitchellh/hashstructure"
 	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
 	"github.com/yandex-cloud/go-sdk/operation"
@@ -44,6 +47,13 @@ func resourceYandexComputeInstance() *schema.Resource {
 
 		MigrateState: resourceComputeInstanceMigrateState,
 
+		CustomizeDiff: customdiff.All(
+			computeInstanceResourcesDiffCustomize,
+			computeInstanceMetadataDiffCustomize,
+			computeInstanceSecondaryDiskDiffCustomize,
+			computeInstanceGpuSettingsDiffCustomize,
+		),
+
 		Schema: map[string]*schema.Schema{
 			"resources": {
 				Type:     schema.TypeList,
@@ -165,7 +175,14 @@ func resourceYandexComputeInstance() *schema.Resource {
 										Optional:      true,
 										Computed:      true,
 										ForceNew:      true,
-										ConflictsWith: []string{"boot_disk.initialize_params.snapshot_id"},
+										ConflictsWith: []string{"boot_disk.initialize_params.snapshot_id", "boot_disk.initialize_params.image_family"},
+									},
+
+									"image_family": {
+										Type:          schema.TypeString,
+										Optional:      true,
+										ForceNew:      true,
+										ConflictsWith: []string{"boot_disk.initialize_params.image_id", "boot_disk.initialize_params.snapshot_id"},
 									},
 
 									"snapshot_id": {
@@ -173,7 +190,7 @@ func resourceYandexComputeInstance() *schema.Resource {
 										Optional:      true,
 										Computed:      true,
 										ForceNew:      true,
-										ConflictsWith: []string{"boot_disk.initialize_params.image_id"},
+										ConflictsWith: []string{"boot_disk.initialize_params.image_id", "boot_disk.initialize_params.image_family"},
 									},
 								},
 							},
@@ -399,6 +416,12 @@ func resourceYandexComputeInstance() *schema.Resource {
 				Optional: true,
 			},
 
+			"desired_status": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"RUNNING", "STOPPED"}, false),
+			},
+
 			"secondary_disk": {
 				Type:     schema.TypeList,
 				Optional: true,
@@ -406,7 +429,61 @@ func resourceYandexComputeInstance() *schema.Resource {
 					Schema: map[string]*schema.Schema{
 						"disk_id": {
 							Type:     schema.TypeString,
-							Required: true,
+							Optional: true,
+							Computed: true,
+						},
+
+						"initialize_params": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Computed: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"name": {
+										Type:     schema.TypeString,
+										Optional: true,
+										Computed: true,
+									},
+
+									"description": {
+										Type:     schema.TypeString,
+										Optional: true,
+										Computed: true,
+									},
+
+									"size": {
+										Type:         schema.TypeInt,
+										Optional:     true,
+										Computed:     true,
+										ValidateFunc: validation.IntAtLeast(1),
+									},
+
+									"block_size": {
+										Type:     schema.TypeInt,
+										Optional: true,
+										Computed: true,
+									},
+
+									"type": {
+										Type:     schema.TypeString,
+										Optional: true,
+										Computed: true,
+									},
+
+									"image_id": {
+										Type:     schema.TypeString,
+										Optional: true,
+										Computed: true,
+									},
+
+									"snapshot_id": {
+										Type:     schema.TypeString,
+										Optional: true,
+										Computed: true,
+									},
+								},
+							},
 						},
 
 						"auto_delete": {
@@ -592,10 +669,28 @@ func resourceYandexComputeInstance() *schema.Resource {
 			},
 
 			"gpu_cluster_id": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
-				ForceNew: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"gpu_settings"},
+			},
+
+			"gpu_settings": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				Computed:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"gpu_cluster_id"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"gpu_cluster_id": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+					},
+				},
 			},
 		},
 	}
@@ -638,6 +733,12 @@ func resourceYandexComputeInstanceCreate(d *schema.ResourceData, meta interface{
 		return fmt.Errorf("Instance creation failed: %s", err)
 	}
 
+	if d.Get("desired_status").(string) == "STOPPED" {
+		if err := ensureInstanceStatus(compute.Instance_STOPPED, d, meta); err != nil {
+			return err
+		}
+	}
+
 	return resourceYandexComputeInstanceRead(d, meta)
 }
 
@@ -701,6 +802,14 @@ func resourceYandexComputeInstanceRead(d *schema.ResourceData, meta interface{})
 	d.Set("description", instance.Description)
 	d.Set("service_account_id", instance.ServiceAccountId)
 	d.Set("status", strings.ToLower(instance.Status.String()))
+	// desired_status is only tracked when configured, so that drift from the requested
+	// state is shown in the plan and corrected on the next apply
+	if _, ok := d.GetOk("desired_status"); ok {
+		switch instance.Status {
+		case compute.Instance_RUNNING, compute.Instance_STOPPED:
+			d.Set("desired_status", instance.Status.String())
+		}
+	}
 	d.Set("metadata_options", metadataOptions)
 
 	hostname, err := parseHostnameFromFQDN(instance.Fqdn)
@@ -709,11 +818,19 @@ func resourceYandexComputeInstanceRead(d *schema.ResourceData, meta interface{})
 	}
 	d.Set("hostname", hostname)
 
-	if err := d.Set("metadata", instance.Metadata); err != nil {
+	configuredMetadata, err := expandLabels(d.Get("metadata"))
+	if err != nil {
+		return err
+	}
+	if err := d.Set("metadata", flattenInstanceMetadata(instance.Metadata, configuredMetadata)); err != nil {
 		return err
 	}
 
-	if err := d.Set("labels", instance.Labels); err != nil {
+	configuredLabels, err := expandLabels(d.Get("labels"))
+	if err != nil {
+		return err
+	}
+	if err := d.Set("labels", flattenResourceLabels(instance.Labels, configuredLabels)); err != nil {
 		return err
 	}
 
@@ -721,11 +838,19 @@ func resourceYandexComputeInstanceRead(d *schema.ResourceData, meta interface{})
 		return err
 	}
 
+	// Image family is not stored on the disk, keep the configured one
+	// so the latest image of the family doesn't cause the instance recreation.
+	if family, ok := d.GetOk("boot_disk.0.initialize_params.0.image_family"); ok && len(bootDisk) > 0 {
+		if params, ok := bootDisk[0]["initialize_params"].([]map[string]interface{}); ok && len(params) > 0 {
+			params[0]["image_family"] = family
+		}
+	}
+
 	if err := d.Set("boot_disk", bootDisk); err != nil {
 		return err
 	}
 
-	if err := d.Set("secondary_disk", secondaryDisks); err != nil {
+	if err := d.Set("secondary_disk", orderInstanceSecondaryDisks(secondaryDisks, d.Get("secondary_disk").([]interface{}))); err != nil {
 		return err
 	}
 
@@ -767,6 +892,10 @@ func resourceYandexComputeInstanceRead(d *schema.ResourceData, meta interface{})
 		d.Set("gpu_cluster_id", instance.GpuSettings.GpuClusterId)
 	}
 
+	if err := d.Set("gpu_settings", flattenInstanceGpuSettings(instance.GpuSettings)); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -809,8 +938,10 @@ func resourceYandexComputeInstanceUpdate(d *schema.ResourceData, meta interface{
 				return err
 			}
 
-			if err := makeInstanceActionRequest(instanceActionStart, d, meta); err != nil {
-				return err
+			if instanceShouldBeRunning(d) {
+				if err := makeInstanceActionRequest(instanceActionStart, d, meta); err != nil {
+					return err
+				}
 			}
 
 		} else {
@@ -825,10 +956,16 @@ func resourceYandexComputeInstanceUpdate(d *schema.ResourceData, meta interface{
 
 	labelPropName := "labels"
 	if d.HasChange(labelPropName) {
-		labelsProp, err := expandLabels(d.Get(labelPropName))
+		oldLabelsProp, newLabelsProp := d.GetChange(labelPropName)
+		labelsProp, err := expandLabels(newLabelsProp)
+		if err != nil {
+			return err
+		}
+		oldLabels, err := expandLabels(oldLabelsProp)
 		if err != nil {
 			return err
 		}
+		labelsProp = mergeSystemLabels(labelsProp, oldLabels, instance.Labels)
 
 		req := &compute.UpdateInstanceRequest{
 			InstanceId: d.Id(),
@@ -847,10 +984,16 @@ func resourceYandexComputeInstanceUpdate(d *schema.ResourceData, meta interface{
 
 	metadataPropName := "metadata"
 	if d.HasChange(metadataPropName) {
-		metadataProp, err := expandLabels(d.Get(metadataPropName))
+		oldMetadataProp, newMetadataProp := d.GetChange(metadataPropName)
+		metadataProp, err := expandLabels(newMetadataProp)
+		if err != nil {
+			return err
+		}
+		oldMetadata, err := expandLabels(oldMetadataProp)
 		if err != nil {
 			return err
 		}
+		metadataProp = mergeInstanceWellKnownMetadata(metadataProp, oldMetadata, instance.Metadata)
 
 		req := &compute.UpdateInstanceRequest{
 			InstanceId: d.Id(),
@@ -1082,56 +1225,55 @@ func resourceYandexComputeInstanceUpdate(d *schema.ResourceData, meta interface{
 			currDisks[disk.DiskId] = struct{}{}
 		}
 
-		// Keep track of disks currently in state.
-		// Since changing any field within the disk needs to detach+reattach it,
-		// keep track of the hash of the disk spec.
-		oDisks := map[uint64]string{}
+		// Keep track of disks currently in state by disk_id, so that reordering or
+		// appending secondary_disk blocks doesn't detach disks that stay attached.
+		oDisks := map[string]*compute.AttachedDiskSpec{}
 		for _, disk := range o.([]interface{}) {
 			diskConfig := disk.(map[string]interface{})
-			diskSpec, err := expandSecondaryDiskSpec(diskConfig)
-			if err != nil {
-				return err
-			}
-			hash, err := hashstructure.Hash(diskSpec, nil)
+			diskSpec, err := expandSecondaryDiskSpec(diskConfig, config)
 			if err != nil {
 				return err
 			}
 			if _, ok := currDisks[diskSpec.GetDiskId()]; ok {
-				oDisks[hash] = diskSpec.GetDiskId()
+				oDisks[diskSpec.GetDiskId()] = diskSpec
 			}
 		}
 
-		// Keep track of new config's disks.
-		// Since changing any field within the disk needs to detach+reattach it,
-		// keep track of the hash of the full disk.
-		// If a disk with a certain hash is only in the new config, it should be attached.
-		nDisks := map[uint64]struct{}{}
+		// A disk only in the new config should be attached. Since changing any field
+		// within the disk needs to detach+reattach it, a changed disk is attached again.
+		nDisks := map[string]struct{}{}
 		var attach []*compute.AttachedDiskSpec
-		for _, disk := range n.([]interface{}) {
+		for i, disk := range n.([]interface{}) {
 			diskConfig := disk.(map[string]interface{})
-			diskSpec, err := expandSecondaryDiskSpec(diskConfig)
-			if err != nil {
-				return err
-			}
-			hash, err := hashstructure.Hash(diskSpec, nil)
+			diskSpec, err := expandSecondaryDiskSpec(diskConfig, config)
 			if err != nil {
 				return err
 			}
-			nDisks[hash] = struct{}{}
 
-			if _, ok := oDisks[hash]; !ok {
+			oldSpec, ok := oDisks[diskSpec.GetDiskId()]
+			if !ok {
 				attach = append(attach, diskSpec)
+				continue
+			}
+			// device_name is computed, keep the current one unless it is set in the config.
+			if !isSecondaryDiskDeviceNameConfigured(d, i) {
+				diskSpec.DeviceName = oldSpec.DeviceName
 			}
+			if proto.Equal(oldSpec, diskSpec) {
+				nDisks[diskSpec.GetDiskId()] = struct{}{}
+				continue
+			}
+			attach = append(attach, diskSpec)
 		}
 
-		// If a source is only in the old config, it should be detached.
+		// If a disk is only in the old config or has changed, it should be detached.
 		// Detach the old disks.
-		for hash, deviceID := range oDisks {
-			if _, ok := nDisks[hash]; !ok {
+		for diskID := range oDisks {
+			if _, ok := nDisks[diskID]; !ok {
 				req := &compute.DetachInstanceDiskRequest{
 					InstanceId: d.Id(),
 					Disk: &compute.DetachInstanceDiskRequest_DiskId{
-						DiskId: deviceID,
+						DiskId: diskID,
 					},
 				}
 
@@ -1139,7 +1281,7 @@ func resourceYandexComputeInstanceUpdate(d *schema.ResourceData, meta interface{
 				if err != nil {
 					return err
 				}
-				log.Printf("[DEBUG] Successfully detached disk %s", deviceID)
+				log.Printf("[DEBUG] Successfully detached disk %s", diskID)
 			}
 		}
 
@@ -1178,7 +1320,7 @@ func resourceYandexComputeInstanceUpdate(d *schema.ResourceData, meta interface{
 		if err := ensureAllowStoppingForUpdate(d, properties...); err != nil {
 			return err
 		}
-		if err := makeInstanceActionRequest(instanceActionStop, d, meta); err != nil {
+		if err := ensureInstanceStatus(compute.Instance_STOPPED, d, meta); err != nil {
 			return err
 		}
 
@@ -1348,8 +1490,23 @@ func resourceYandexComputeInstanceUpdate(d *schema.ResourceData, meta interface{
 
 		}
 
-		if err := makeInstanceActionRequest(instanceActionStart, d, meta); err != nil {
-			return err
+		if instanceShouldBeRunning(d) {
+			if err := makeInstanceActionRequest(instanceActionStart, d, meta); err != nil {
+				return err
+			}
+		}
+	}
+
+	if d.HasChange("desired_status") {
+		switch d.Get("desired_status").(string) {
+		case "STOPPED":
+			if err := ensureInstanceStatus(compute.Instance_STOPPED, d, meta); err != nil {
+				return err
+			}
+		case "RUNNING":
+			if err := ensureInstanceStatus(compute.Instance_RUNNING, d, meta); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -1420,7 +1577,7 @@ func prepareCreateInstanceRequest(d *schema.ResourceData, meta *Config) (*comput
 		return nil, fmt.Errorf("Error create 'boot_disk' object of api request: %s", err)
 	}
 
-	secondaryDiskSpecs, err := expandInstanceSecondaryDiskSpecs(d)
+	secondaryDiskSpecs, err := expandInstanceSecondaryDiskSpecs(d, meta)
 	if err != nil {
 		return nil, fmt.Errorf("Error create 'secondary_disk' object of api request: %s", err)
 	}
@@ -1454,11 +1611,6 @@ func prepareCreateInstanceRequest(d *schema.ResourceData, meta *Config) (*comput
 		return nil, fmt.Errorf("Error create 'filesystem' object of api request: %s", err)
 	}
 
-	gpuSettingsSpec, err := expandInstanceGpuSettingsSpec(d)
-	if err != nil {
-		return nil, fmt.Errorf("Error create 'gpu_settings' object of api request: %s", err)
-	}
-
 	req := &compute.CreateInstanceRequest{
 		FolderId:              folderID,
 		Hostname:              d.Get("hostname").(string),
@@ -1479,7 +1631,7 @@ func prepareCreateInstanceRequest(d *schema.ResourceData, meta *Config) (*comput
 		LocalDiskSpecs:        localDisks,
 		MetadataOptions:       metadataOptions,
 		FilesystemSpecs:       filesystemSpecs,
-		GpuSettings:           gpuSettingsSpec,
+		GpuSettings:           expandInstanceGpuSettingsSpec(d),
 	}
 
 	return req, nil
@@ -1526,6 +1678,96 @@ func wantChangeAddressSpec(old *compute.PrimaryAddressSpec, new *compute.Primary
 	return false
 }
 
+// computeInstanceResourcesDiffCustomize rejects resources that are not allowed on the instance platform at plan time.
+func computeInstanceResourcesDiffCustomize(ctx context.Context, rdiff *schema.ResourceDiff, _ interface{}) error {
+	if rdiff.Id() != "" && !rdiff.HasChange("platform_id") && !rdiff.HasChange("resources") {
+		return nil
+	}
+	if !rdiff.NewValueKnown("platform_id") || !isComputeInstanceResourcesKnown(rdiff.GetRawConfig()) {
+		return nil
+	}
+
+	return validateInstanceResources(
+		rdiff.Get("platform_id").(string),
+		rdiff.Get("resources.0.cores").(int),
+		rdiff.Get("resources.0.core_fraction").(int),
+		rdiff.Get("resources.0.memory").(float64),
+		rdiff.Get("resources.0.gpus").(int),
+	)
+}
+
+// isComputeInstanceResourcesKnown reports whether all values of the resources block in the raw configuration are known.
+// Without the raw configuration the values are treated as known.
+func isComputeInstanceResourcesKnown(rawConfig cty.Value) bool {
+	if rawConfig == cty.NilVal {
+		return true
+	}
+	if rawConfig.IsNull() || !rawConfig.IsKnown() {
+		return false
+	}
+	rawResources := rawConfig.GetAttr("resources")
+	if rawResources.IsNull() || !rawResources.IsKnown() || rawResources.LengthInt() == 0 {
+		return false
+	}
+	return rawResources.Index(cty.NumberIntVal(0)).IsWhollyKnown()
+}
+
+// computeInstanceMetadataDiffCustomize rejects metadata exceeding the API size limit at plan time.
+func computeInstanceMetadataDiffCustomize(ctx context.Context, rdiff *schema.ResourceDiff, _ interface{}) error {
+	if rdiff.Id() != "" && !rdiff.HasChange("metadata") {
+		return nil
+	}
+	if !rdiff.NewValueKnown("metadata") {
+		return nil
+	}
+
+	return validateInstanceMetadataSize(rdiff.Get("metadata").(map[string]interface{}))
+}
+
+// computeInstanceSecondaryDiskDiffCustomize rejects changes of initialize_params of attached secondary disks.
+// Such a disk is identified by its disk_id and is not recreated, so the change would never be applied.
+func computeInstanceSecondaryDiskDiffCustomize(ctx context.Context, rdiff *schema.ResourceDiff, _ interface{}) error {
+	if rdiff.Id() == "" || !rdiff.HasChange("secondary_disk") {
+		return nil
+	}
+
+	o, _ := rdiff.GetChange("secondary_disk")
+	for i, disk := range o.([]interface{}) {
+		oldDisk, ok := disk.(map[string]interface{})
+		if !ok || oldDisk["disk_id"].(string) == "" {
+			continue
+		}
+
+		key := fmt.Sprintf("secondary_disk.%d.initialize_params", i)
+		if rdiff.HasChange(key) {
+			return fmt.Errorf("%s of attached disk %q can't be changed, "+
+				"add new secondary disks to the end of the list or remove the disk to recreate it", key, oldDisk["disk_id"])
+		}
+	}
+	return nil
+}
+
+// computeInstanceGpuSettingsDiffCustomize rejects a GPU cluster for an instance without GPUs at plan time.
+func computeInstanceGpuSettingsDiffCustomize(ctx context.Context, rdiff *schema.ResourceDiff, _ interface{}) error {
+	if rdiff.Id() != "" && !rdiff.HasChanges("gpu_cluster_id", "gpu_settings", "resources") {
+		return nil
+	}
+	if !rdiff.NewValueKnown("resources.0.gpus") {
+		return nil
+	}
+
+	for _, key := range []string{"gpu_settings.0.gpu_cluster_id", "gpu_cluster_id"} {
+		if !rdiff.NewValueKnown(key) || rdiff.Get(key).(string) == "" {
+			continue
+		}
+		if rdiff.Get("resources.0.gpus").(int) == 0 {
+			return fmt.Errorf("GPU cluster can only be used by instance with GPUs, 'resources.0.gpus' should be set")
+		}
+		return nil
+	}
+	return nil
+}
+
 func wantChangeNatSpec(old *compute.OneToOneNatSpec, new *compute.OneToOneNatSpec) bool {
 	if old == nil && new == nil {
 		return false
@@ -1673,6 +1915,35 @@ func makeInstanceActionRequest(action instanceAction, d *schema.ResourceData, me
 	return nil
 }
 
+// ensureInstanceStatus stops or starts the instance unless it is already in the target status.
+func ensureInstanceStatus(target compute.Instance_Status, d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	instance, err := config.sdk.Compute().Instance().Get(config.Context(), &compute.GetInstanceRequest{
+		InstanceId: d.Id(),
+	})
+	if err != nil {
+		return fmt.Errorf("Error while requesting API to get instance %s: %s", d.Id(), err)
+	}
+
+	if instance.Status == target {
+		return nil
+	}
+
+	switch target {
+	case compute.Instance_STOPPED:
+		return makeInstanceActionRequest(instanceActionStop, d, meta)
+	case compute.Instance_RUNNING:
+		return makeInstanceActionRequest(instanceActionStart, d, meta)
+	default:
+		return fmt.Errorf("Instance status %s is not supported as a target", target)
+	}
+}
+
+func instanceShouldBeRunning(d *schema.ResourceData) bool {
+	return d.Get("desired_status").(string) != "STOPPED"
+}
+
 func makeDetachDiskRequest(req *compute.DetachInstanceDiskRequest, meta interface{}) error {
 	config := meta.(*Config)
 
@@ -1814,3 +2085,17 @@ func ensureAllowStoppingForUpdate(d *schema.ResourceData, propNames ...string) e
 func hostnameDiffSuppressFunc(_, oldValue, newValue string, _ *schema.ResourceData) bool {
 	return strings.TrimRight(oldValue, ".") == strings.TrimRight(newValue, ".")
 }
+
+// isSecondaryDiskDeviceNameConfigured reports whether device_name of the i-th secondary_disk block
+// is set in the configuration. If the configuration is not available, it is assumed to be set.
+func isSecondaryDiskDeviceNameConfigured(d *schema.ResourceData, i int) bool {
+	rawConfig := d.GetRawConfig()
+	if rawConfig.IsNull() || !rawConfig.IsKnown() {
+		return true
+	}
+	disks := rawConfig.GetAttr("secondary_disk")
+	if disks.IsNull() || !disks.IsKnown() || i >= disks.LengthInt() {
+		return true
+	}
+	return !disks.Index(cty.NumberIntVal(int64(i))).GetAttr("device_name").IsNull()
+}
@@ -35,6 +35,8 @@ func resourceYandexComputeInstanceGroup() *schema.Resource {
 
 		SchemaVersion: 0,
 
+		CustomizeDiff: instanceGroupScalePolicyDiffCustomize,
+
 		Schema: map[string]*schema.Schema{
 			"service_account_id": {
 				Type:     schema.TypeString,
@@ -1275,6 +1277,11 @@ func prepareUpdateInstanceGroupRequest(d *schema.ResourceData, meta *Config) (*i
 		return nil, fmt.Errorf("Error creating 'load_balancer_spec' object of api request: %s", err)
 	}
 
+	applicationLoadBalancerSpec, err := expandInstanceGroupApplicationLoadBalancerSpec(d)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating 'application_load_balancer' object of api request: %s", err)
+	}
+
 	variables, err := expandInstanceGroupVariables(d.Get("variables"))
 	if err != nil {
 		return nil, fmt.Errorf("Error creating 'variables' object of api request: %s", err)
@@ -1296,20 +1303,21 @@ func prepareUpdateInstanceGroupRequest(d *schema.ResourceData, meta *Config) (*i
 	}
 
 	req := &instancegroup.UpdateInstanceGroupRequest{
-		InstanceGroupId:    d.Id(),
-		Name:               d.Get("name").(string),
-		Description:        d.Get("description").(string),
-		Labels:             labels,
-		InstanceTemplate:   instanceTemplate,
-		ScalePolicy:        scalePolicy,
-		DeployPolicy:       deployPolicy,
-		AllocationPolicy:   allocationPolicy,
-		LoadBalancerSpec:   loadBalancerSpec,
-		HealthChecksSpec:   healthChecksSpec,
-		ServiceAccountId:   d.Get("service_account_id").(string),
-		UpdateMask:         &field_mask.FieldMask{Paths: updatePath},
-		Variables:          variables,
-		DeletionProtection: deletionProtection.(bool),
+		InstanceGroupId:             d.Id(),
+		Name:                        d.Get("name").(string),
+		Description:                 d.Get("description").(string),
+		Labels:                      labels,
+		InstanceTemplate:            instanceTemplate,
+		ScalePolicy:                 scalePolicy,
+		DeployPolicy:                deployPolicy,
+		AllocationPolicy:            allocationPolicy,
+		LoadBalancerSpec:            loadBalancerSpec,
+		ApplicationLoadBalancerSpec: applicationLoadBalancerSpec,
+		HealthChecksSpec:            healthChecksSpec,
+		ServiceAccountId:            d.Get("service_account_id").(string),
+		UpdateMask:                  &field_mask.FieldMask{Paths: updatePath},
+		Variables:                   variables,
+		DeletionProtection:          deletionProtection.(bool),
 	}
 
 	return req, nil
@@ -1337,6 +1345,7 @@ func getStaticUpdatePath() []string {
 		"deploy_policy",
 		"allocation_policy",
 		"load_balancer_spec",
+		"application_load_balancer_spec",
 		"health_checks_spec",
 		"service_account_id",
 		"deletion_protection",
@@ -1361,3 +1370,18 @@ func makeInstanceGroupUpdateRequest(req *instancegroup.UpdateInstanceGroupReques
 
 	return nil
 }
+
+// The API requires exactly one scale type, test_auto_scale may only accompany it.
+func instanceGroupScalePolicyDiffCustomize(ctx context.Context, rdiff *schema.ResourceDiff, _ interface{}) error {
+	fixedScale := len(rdiff.Get("scale_policy.0.fixed_scale").([]interface{})) > 0
+	autoScale := len(rdiff.Get("scale_policy.0.auto_scale").([]interface{})) > 0
+
+	switch {
+	case fixedScale && autoScale:
+		return fmt.Errorf("scale_policy: only one of fixed_scale or auto_scale can be specified")
+	case !fixedScale && !autoScale:
+		return fmt.Errorf("scale_policy: one of fixed_scale or auto_scale must be specified, " +
+			"test_auto_scale can only be used in addition to one of them")
+	}
+	return nil
+}
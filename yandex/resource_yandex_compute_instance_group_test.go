@@ -3,11 +3,13 @@ package yandex
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"google.golang.org/genproto/protobuf/field_mask"
 
@@ -24,6 +26,70 @@ func init() {
 	})
 }
 
+func TestInstanceGroupScalePolicyDiffCustomize(t *testing.T) {
+	fixedScale := []interface{}{map[string]interface{}{"size": 2}}
+	autoScale := []interface{}{map[string]interface{}{
+		"initial_size":         2,
+		"measurement_duration": 60,
+	}}
+
+	tests := []struct {
+		name        string
+		scalePolicy map[string]interface{}
+		wantErr     string
+	}{
+		{
+			name:        "none",
+			scalePolicy: map[string]interface{}{},
+			wantErr:     "one of fixed_scale or auto_scale must be specified",
+		},
+		{
+			name:        "test auto scale only",
+			scalePolicy: map[string]interface{}{"test_auto_scale": autoScale},
+			wantErr:     "one of fixed_scale or auto_scale must be specified",
+		},
+		{
+			name:        "both",
+			scalePolicy: map[string]interface{}{"fixed_scale": fixedScale, "auto_scale": autoScale},
+			wantErr:     "only one of fixed_scale or auto_scale can be specified",
+		},
+		{
+			name:        "fixed scale",
+			scalePolicy: map[string]interface{}{"fixed_scale": fixedScale},
+		},
+		{
+			name:        "auto scale",
+			scalePolicy: map[string]interface{}{"auto_scale": autoScale},
+		},
+		{
+			name:        "fixed scale with test auto scale",
+			scalePolicy: map[string]interface{}{"fixed_scale": fixedScale, "test_auto_scale": autoScale},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resourceYandexComputeInstanceGroup()
+			raw := map[string]interface{}{
+				"service_account_id": "sa",
+				"scale_policy":       []interface{}{tt.scalePolicy},
+			}
+			state := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{}).State()
+
+			_, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(raw), nil)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func testSweepComputeInstanceGroups(_ string) error {
 	conf, err := configForSweepers()
 	if err != nil {
@@ -178,6 +244,11 @@ func TestAccComputeInstanceGroup_basic(t *testing.T) {
 				Config: testAccComputeInstanceGroupConfigMain(name, saName),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckComputeInstanceGroupExists("yandex_compute_instance_group.group1", &ig),
+					resource.TestCheckResourceAttr("yandex_compute_instance_group.group1", "instances.#", "2"),
+					resource.TestCheckResourceAttrSet("yandex_compute_instance_group.group1", "instances.0.instance_id"),
+					resource.TestCheckResourceAttrSet("yandex_compute_instance_group.group1", "instances.0.fqdn"),
+					resource.TestCheckResourceAttrSet("yandex_compute_instance_group.group1", "instances.0.status"),
+					resource.TestCheckResourceAttrSet("yandex_compute_instance_group.group1", "instances.0.network_interface.0.ip_address"),
 				),
 			},
 			computeInstanceGroupImportStep(),
@@ -186,6 +257,68 @@ func TestAccComputeInstanceGroup_basic(t *testing.T) {
 
 }
 
+func TestAccComputeInstanceGroup_BootDiskFromSnapshot(t *testing.T) {
+	t.Parallel()
+
+	var ig instancegroup.InstanceGroup
+
+	name := acctest.RandomWithPrefix("tf-test")
+	saName := acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeInstanceGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccComputeInstanceGroupConfigBootDiskFromSnapshot(name, saName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceGroupExists("yandex_compute_instance_group.group1", &ig),
+					resource.TestCheckResourceAttr("yandex_compute_instance_group.group1", "instances.#", "1"),
+					resource.TestCheckResourceAttrPair("yandex_compute_instance_group.group1", "instance_template.0.boot_disk.0.initialize_params.0.snapshot_id",
+						"yandex_compute_snapshot.golden", "id"),
+					resource.TestCheckResourceAttr("yandex_compute_instance_group.group1", "instance_template.0.boot_disk.0.initialize_params.0.image_id", ""),
+				),
+			},
+			computeInstanceGroupImportStep(),
+		},
+	})
+}
+
+func TestAccComputeInstanceGroup_BothLoadBalancers(t *testing.T) {
+	t.Parallel()
+
+	var ig instancegroup.InstanceGroup
+
+	name := acctest.RandomWithPrefix("tf-test")
+	saName := acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeInstanceGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccComputeInstanceGroupConfigBothLoadBalancers(name, saName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceGroupExists("yandex_compute_instance_group.group1", &ig),
+					resource.TestCheckResourceAttrSet("yandex_compute_instance_group.group1", "load_balancer.0.target_group_id"),
+					resource.TestCheckResourceAttrSet("yandex_compute_instance_group.group1", "application_load_balancer.0.target_group_id"),
+					resource.TestCheckResourceAttrPair(
+						"yandex_lb_network_load_balancer.nlb", "attached_target_group.0.target_group_id",
+						"yandex_compute_instance_group.group1", "load_balancer.0.target_group_id",
+					),
+					resource.TestCheckResourceAttrPair(
+						"yandex_alb_backend_group.alb-bg", "http_backend.0.target_group_ids.0",
+						"yandex_compute_instance_group.group1", "application_load_balancer.0.target_group_id",
+					),
+				),
+			},
+			computeInstanceGroupImportStep(),
+		},
+	})
+}
+
 func TestAccComputeInstanceGroup_Gpus(t *testing.T) {
 	var ig instancegroup.InstanceGroup
 
@@ -363,6 +496,36 @@ func TestAccComputeInstanceGroup_DeployPolicyStrategy(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckComputeInstanceGroupExists("yandex_compute_instance_group.group1", &ig),
 					testAccCheckComputeInstanceGroupStrategy(&ig),
+					resource.TestCheckResourceAttr("yandex_compute_instance_group.group1", "deploy_policy.0.strategy", "opportunistic"),
+				),
+			},
+			computeInstanceGroupImportStep(),
+		},
+	})
+}
+
+func TestAccComputeInstanceGroup_MultipleHealthChecks(t *testing.T) {
+	t.Parallel()
+
+	var ig instancegroup.InstanceGroup
+
+	name := acctest.RandomWithPrefix("tf-test")
+	saName := acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeInstanceGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccComputeInstanceGroupConfigHealthChecks(name, saName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceGroupExists("yandex_compute_instance_group.group1", &ig),
+					testAccCheckComputeInstanceGroupHealthChecks(&ig),
+					resource.TestCheckResourceAttr("yandex_compute_instance_group.group1", "health_check.#", "2"),
+					resource.TestCheckResourceAttr("yandex_compute_instance_group.group1", "health_check.0.tcp_options.0.port", "22"),
+					resource.TestCheckResourceAttr("yandex_compute_instance_group.group1", "health_check.1.http_options.0.port", "80"),
+					resource.TestCheckResourceAttr("yandex_compute_instance_group.group1", "health_check.1.http_options.0.path", "/"),
 				),
 			},
 			computeInstanceGroupImportStep(),
@@ -697,6 +860,215 @@ resource "yandex_resourcemanager_folder_iam_member" "test_account" {
 `, getExampleFolderID(), igName, saName)
 }
 
+func testAccComputeInstanceGroupConfigBootDiskFromSnapshot(igName string, saName string) string {
+	return fmt.Sprintf(`
+data "yandex_compute_image" "ubuntu" {
+  family = "ubuntu-1604-lts"
+}
+
+data "yandex_resourcemanager_folder" "test_folder" {
+  folder_id = "%[1]s"
+}
+
+resource "yandex_compute_disk" "golden" {
+  zone     = "ru-central1-a"
+  image_id = "${data.yandex_compute_image.ubuntu.id}"
+  size     = 4
+}
+
+resource "yandex_compute_snapshot" "golden" {
+  source_disk_id = "${yandex_compute_disk.golden.id}"
+}
+
+resource "yandex_compute_instance_group" "group1" {
+  depends_on         = ["yandex_iam_service_account.test_account", "yandex_resourcemanager_folder_iam_member.test_account"]
+  name               = "%[2]s"
+  folder_id          = "${data.yandex_resourcemanager_folder.test_folder.id}"
+  service_account_id = "${yandex_iam_service_account.test_account.id}"
+  instance_template {
+    platform_id = "standard-v2"
+    description = "template_description"
+
+    resources {
+      memory = 2
+      cores  = 2
+    }
+
+    boot_disk {
+      initialize_params {
+        snapshot_id = "${yandex_compute_snapshot.golden.id}"
+        size        = 4
+      }
+    }
+
+    network_interface {
+      network_id = "${yandex_vpc_network.inst-group-test-network.id}"
+      subnet_ids = ["${yandex_vpc_subnet.inst-group-test-subnet.id}"]
+    }
+  }
+
+  scale_policy {
+    fixed_scale {
+      size = 1
+    }
+  }
+
+  allocation_policy {
+    zones = ["ru-central1-a"]
+  }
+
+  deploy_policy {
+    max_unavailable = 1
+    max_creating    = 1
+    max_expansion   = 1
+    max_deleting    = 1
+  }
+}
+
+resource "yandex_vpc_network" "inst-group-test-network" {
+  description = "tf-test"
+}
+
+resource "yandex_vpc_subnet" "inst-group-test-subnet" {
+  description    = "tf-test"
+  zone           = "ru-central1-a"
+  network_id     = "${yandex_vpc_network.inst-group-test-network.id}"
+  v4_cidr_blocks = ["192.168.0.0/24"]
+}
+
+resource "yandex_iam_service_account" "test_account" {
+  name        = "%[3]s"
+  description = "tf-test"
+}
+
+resource "yandex_resourcemanager_folder_iam_member" "test_account" {
+  folder_id   = "${data.yandex_resourcemanager_folder.test_folder.id}"
+  member      = "serviceAccount:${yandex_iam_service_account.test_account.id}"
+  role        = "editor"
+  sleep_after = 30
+}
+`, getExampleFolderID(), igName, saName)
+}
+
+func testAccComputeInstanceGroupConfigBothLoadBalancers(igName string, saName string) string {
+	return fmt.Sprintf(`
+data "yandex_compute_image" "ubuntu" {
+  family = "ubuntu-1604-lts"
+}
+
+data "yandex_resourcemanager_folder" "test_folder" {
+  folder_id = "%[1]s"
+}
+
+resource "yandex_compute_instance_group" "group1" {
+  depends_on         = ["yandex_iam_service_account.test_account", "yandex_resourcemanager_folder_iam_member.test_account"]
+  name               = "%[2]s"
+  folder_id          = "${data.yandex_resourcemanager_folder.test_folder.id}"
+  service_account_id = "${yandex_iam_service_account.test_account.id}"
+  instance_template {
+    platform_id = "standard-v2"
+    description = "template_description"
+
+    resources {
+      memory = 2
+      cores  = 2
+    }
+
+    boot_disk {
+      initialize_params {
+        image_id = "${data.yandex_compute_image.ubuntu.id}"
+        size     = 4
+      }
+    }
+
+    network_interface {
+      network_id = "${yandex_vpc_network.inst-group-test-network.id}"
+      subnet_ids = ["${yandex_vpc_subnet.inst-group-test-subnet.id}"]
+    }
+  }
+
+  scale_policy {
+    fixed_scale {
+      size = 1
+    }
+  }
+
+  allocation_policy {
+    zones = ["ru-central1-a"]
+  }
+
+  deploy_policy {
+    max_unavailable = 3
+    max_creating    = 3
+    max_expansion   = 3
+    max_deleting    = 3
+  }
+
+  load_balancer {
+    target_group_name = "%[2]s-nlb"
+  }
+
+  application_load_balancer {
+    target_group_name = "%[2]s-alb"
+  }
+}
+
+resource "yandex_lb_network_load_balancer" "nlb" {
+  name = "%[2]s-nlb"
+
+  listener {
+    name = "http"
+    port = 80
+  }
+
+  attached_target_group {
+    target_group_id = "${yandex_compute_instance_group.group1.load_balancer.0.target_group_id}"
+
+    healthcheck {
+      name = "http"
+      http_options {
+        port = 80
+        path = "/"
+      }
+    }
+  }
+}
+
+resource "yandex_alb_backend_group" "alb-bg" {
+  name = "%[2]s-alb"
+
+  http_backend {
+    name             = "http"
+    port             = 80
+    target_group_ids = ["${yandex_compute_instance_group.group1.application_load_balancer.0.target_group_id}"]
+  }
+}
+
+resource "yandex_vpc_network" "inst-group-test-network" {
+  description = "tf-test"
+}
+
+resource "yandex_vpc_subnet" "inst-group-test-subnet" {
+  description    = "tf-test"
+  zone           = "ru-central1-a"
+  network_id     = "${yandex_vpc_network.inst-group-test-network.id}"
+  v4_cidr_blocks = ["192.168.0.0/24"]
+}
+
+resource "yandex_iam_service_account" "test_account" {
+  name        = "%[3]s"
+  description = "tf-test"
+}
+
+resource "yandex_resourcemanager_folder_iam_member" "test_account" {
+  folder_id   = "${data.yandex_resourcemanager_folder.test_folder.id}"
+  member      = "serviceAccount:${yandex_iam_service_account.test_account.id}"
+  role        = "editor"
+  sleep_after = 30
+}
+`, getExampleFolderID(), igName, saName)
+}
+
 func testAccComputeInstanceGroupConfigDeletionProtection(igName string, saName string, deletionProtection bool) string {
 	return fmt.Sprintf(`
 data "yandex_compute_image" "ubuntu" {
@@ -1882,6 +2254,105 @@ resource "yandex_resourcemanager_folder_iam_member" "test_account" {
 `, getExampleFolderID(), igName, saName)
 }
 
+func testAccComputeInstanceGroupConfigHealthChecks(igName string, saName string) string {
+	return fmt.Sprintf(`
+data "yandex_compute_image" "ubuntu" {
+  family = "ubuntu-1604-lts"
+}
+
+data "yandex_resourcemanager_folder" "test_folder" {
+  folder_id = "%[1]s"
+}
+
+resource "yandex_compute_instance_group" "group1" {
+  depends_on         = ["yandex_iam_service_account.test_account", "yandex_resourcemanager_folder_iam_member.test_account"]
+  name               = "%[2]s"
+  folder_id          = "${data.yandex_resourcemanager_folder.test_folder.id}"
+  service_account_id = "${yandex_iam_service_account.test_account.id}"
+  instance_template {
+    platform_id = "standard-v2"
+    description = "template_description"
+
+    resources {
+      memory = 2
+      cores  = 2
+    }
+
+    boot_disk {
+      initialize_params {
+        image_id = "${data.yandex_compute_image.ubuntu.id}"
+        size     = 4
+      }
+    }
+
+    network_interface {
+      network_id = "${yandex_vpc_network.inst-group-test-network.id}"
+      subnet_ids = ["${yandex_vpc_subnet.inst-group-test-subnet.id}"]
+    }
+  }
+
+  scale_policy {
+    fixed_scale {
+      size = 2
+    }
+  }
+
+  allocation_policy {
+    zones = ["ru-central1-a"]
+  }
+
+  deploy_policy {
+    max_unavailable = 3
+    max_creating    = 3
+    max_expansion   = 3
+    max_deleting    = 3
+  }
+
+  health_check {
+    interval = 10
+    timeout  = 5
+    tcp_options {
+      port = 22
+    }
+  }
+
+  health_check {
+    interval = 15
+    timeout  = 10
+    http_options {
+      port = 80
+      path = "/"
+    }
+  }
+
+  max_checking_health_duration = 300
+}
+
+resource "yandex_vpc_network" "inst-group-test-network" {
+  description = "tf-test"
+}
+
+resource "yandex_vpc_subnet" "inst-group-test-subnet" {
+  description    = "tf-test"
+  zone           = "ru-central1-a"
+  network_id     = "${yandex_vpc_network.inst-group-test-network.id}"
+  v4_cidr_blocks = ["192.168.0.0/24"]
+}
+
+resource "yandex_iam_service_account" "test_account" {
+  name        = "%[3]s"
+  description = "tf-test"
+}
+
+resource "yandex_resourcemanager_folder_iam_member" "test_account" {
+  folder_id   = "${data.yandex_resourcemanager_folder.test_folder.id}"
+  member      = "serviceAccount:${yandex_iam_service_account.test_account.id}"
+  role        = "editor"
+  sleep_after = 30
+}
+`, getExampleFolderID(), igName, saName)
+}
+
 func testAccComputeInstanceGroupPlacementGroup(igName, saName, pgName string) string {
 	// language=tf
 	return fmt.Sprintf(`
@@ -2468,6 +2939,25 @@ func testAccCheckComputeInstanceGroupStrategy(ig *instancegroup.InstanceGroup) r
 	}
 }
 
+func testAccCheckComputeInstanceGroupHealthChecks(ig *instancegroup.InstanceGroup) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		specs := ig.GetHealthChecksSpec().GetHealthCheckSpecs()
+		if len(specs) != 2 {
+			return fmt.Errorf("expected 2 health checks on instance group %s, got %d", ig.Name, len(specs))
+		}
+
+		if specs[0].GetTcpOptions().GetPort() != 22 {
+			return fmt.Errorf("wrong tcp health check on instance group %s", ig.Name)
+		}
+
+		if specs[1].GetHttpOptions().GetPort() != 80 || specs[1].GetHttpOptions().GetPath() != "/" {
+			return fmt.Errorf("wrong http health check on instance group %s", ig.Name)
+		}
+
+		return nil
+	}
+}
+
 func checkDisk(name string, a *instancegroup.AttachedDiskSpec, d *Disk) error {
 	if d.Mode != "" && a.Mode.String() != d.Mode {
 		return fmt.Errorf("invalid Mode value in %s", name)
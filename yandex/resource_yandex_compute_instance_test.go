@@ -7,8 +7,10 @@ import (
 	"os"
 	"regexp"
 	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -111,6 +113,93 @@ func TestAccComputeInstance_basic1(t *testing.T) {
 	})
 }
 
+func TestAccComputeInstance_bootDiskFromImageFamily(t *testing.T) {
+	t.Parallel()
+
+	var instance compute.Instance
+	var instanceName = fmt.Sprintf("instance-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccComputeInstance_bootDiskFromImageFamily(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceExists(instanceResource, &instance),
+					resource.TestCheckResourceAttr(instanceResource, "boot_disk.0.initialize_params.0.image_family", "ubuntu-1804-lts"),
+					resource.TestCheckResourceAttr(instanceResource, "boot_disk.0.initialize_params.0.block_size", "8192"),
+					resource.TestCheckResourceAttrPair(instanceResource, "boot_disk.0.initialize_params.0.image_id", "data.yandex_compute_image.ubuntu", "id"),
+				),
+			},
+			{
+				Config:             testAccComputeInstance_bootDiskFromImageFamily(instanceName),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+			{
+				ResourceName:            instanceResource,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"allow_stopping_for_update", "boot_disk.0.initialize_params.0.image_family"},
+			},
+		},
+	})
+}
+
+func TestAccComputeInstance_desiredStatus(t *testing.T) {
+	t.Parallel()
+
+	var instance compute.Instance
+	var instanceID string
+	var instanceName = fmt.Sprintf("instance-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccComputeInstance_desiredStatus(instanceName, "RUNNING"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceExists(instanceResource, &instance),
+					func(s *terraform.State) error {
+						instanceID = instance.Id
+						return nil
+					},
+					resource.TestCheckResourceAttr(instanceResource, "desired_status", "RUNNING"),
+					resource.TestCheckResourceAttr(instanceResource, "status", "running"),
+				),
+			},
+			{
+				Config: testAccComputeInstance_desiredStatus(instanceName, "STOPPED"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceExists(instanceResource, &instance),
+					resource.TestCheckResourceAttrPtr(instanceResource, "id", &instanceID),
+					resource.TestCheckResourceAttr(instanceResource, "desired_status", "STOPPED"),
+					resource.TestCheckResourceAttr(instanceResource, "status", "stopped"),
+				),
+			},
+			{
+				Config: testAccComputeInstance_desiredStatus(instanceName, "RUNNING"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceExists(instanceResource, &instance),
+					resource.TestCheckResourceAttrPtr(instanceResource, "id", &instanceID),
+					resource.TestCheckResourceAttr(instanceResource, "desired_status", "RUNNING"),
+					resource.TestCheckResourceAttr(instanceResource, "status", "running"),
+				),
+			},
+			{
+				ResourceName:            instanceResource,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"allow_stopping_for_update", "desired_status"},
+			},
+		},
+	})
+}
+
 func TestAccComputeInstance_Gpus(t *testing.T) {
 	var instance compute.Instance
 	var instanceName = fmt.Sprintf("instance-test-gpus-%s", acctest.RandString(10))
@@ -281,6 +370,46 @@ func TestAccComputeInstance_SecurityGroups(t *testing.T) {
 	})
 }
 
+func TestAccComputeInstance_SecurityGroupsReorder(t *testing.T) {
+	t.Parallel()
+
+	var instance compute.Instance
+	var instanceID string
+	var instanceName = fmt.Sprintf("instance-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccComputeInstance_SecurityGroupsOrdered(instanceName, "sg1", "sg2"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceExists(
+						instanceResource, &instance),
+					testAccCheckComputeInstanceHasSG(&instance),
+					func(s *terraform.State) error {
+						instanceID = instance.Id
+						return nil
+					},
+					resource.TestCheckResourceAttr(instanceResource, "network_interface.0.security_group_ids.#", "2"),
+				),
+			},
+			{
+				Config:   testAccComputeInstance_SecurityGroupsOrdered(instanceName, "sg2", "sg1"),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccComputeInstance_SecurityGroupsOrdered(instanceName, "sg2", "sg1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr(instanceResource, "id", &instanceID),
+					resource.TestCheckResourceAttr(instanceResource, "network_interface.0.security_group_ids.#", "2"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccComputeInstance_NatIP(t *testing.T) {
 	var instance compute.Instance
 	var instanceName = fmt.Sprintf("instance-test-%s", acctest.RandString(10))
@@ -327,6 +456,39 @@ func TestAccComputeInstance_attachedDisk(t *testing.T) {
 	})
 }
 
+func TestAccComputeInstance_secondaryDiskFromSnapshot(t *testing.T) {
+	t.Parallel()
+
+	var instance compute.Instance
+	var instanceName = fmt.Sprintf("instance-test-%s", acctest.RandString(10))
+	var diskName = fmt.Sprintf("disk-test-%s", acctest.RandString(10))
+	var snapshotName = fmt.Sprintf("snapshot-test-%s", acctest.RandString(10))
+	var secondaryDiskName = fmt.Sprintf("disk-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccComputeInstance_secondaryDiskFromSnapshot(diskName, snapshotName, secondaryDiskName, instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceExists(
+						instanceResource, &instance),
+					testAccCheckComputeInstanceDisk(&instance, secondaryDiskName, true, false),
+					resource.TestCheckResourceAttrSet(instanceResource, "secondary_disk.0.disk_id"),
+					resource.TestCheckResourceAttr(instanceResource, "secondary_disk.0.initialize_params.0.name", secondaryDiskName),
+					resource.TestCheckResourceAttr(instanceResource, "secondary_disk.0.initialize_params.0.size", "20"),
+					resource.TestCheckResourceAttr(instanceResource, "secondary_disk.0.initialize_params.0.type", "network-ssd"),
+					resource.TestCheckResourceAttrPair(instanceResource, "secondary_disk.0.initialize_params.0.snapshot_id",
+						"yandex_compute_snapshot.foobar", "id"),
+				),
+			},
+			computeInstanceImportStep(),
+		},
+	})
+}
+
 func TestAccComputeInstance_attachedDisk_sourceUrl(t *testing.T) {
 	t.Parallel()
 
@@ -422,6 +584,54 @@ func TestAccComputeInstance_attachedDiskUpdate(t *testing.T) {
 	})
 }
 
+func TestAccComputeInstance_attachedDiskAppend(t *testing.T) {
+	t.Parallel()
+
+	var instance compute.Instance
+	var instanceName = fmt.Sprintf("instance-test-%s", acctest.RandString(10))
+	var diskName = fmt.Sprintf("disk-test-%s", acctest.RandString(10))
+	var diskName2 = fmt.Sprintf("disk-test-%s", acctest.RandString(10))
+	var deviceName string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccComputeInstance_attachedDisk(diskName, instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceExists(
+						instanceResource, &instance),
+					resource.TestCheckResourceAttrWith(instanceResource, "secondary_disk.0.device_name", func(value string) error {
+						deviceName = value
+						return nil
+					}),
+				),
+			},
+			// the first disk keeps its position and device name after a disk is appended
+			{
+				Config: testAccComputeInstance_addAttachedDisk(diskName, diskName2, instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceExists(
+						instanceResource, &instance),
+					testAccCheckComputeInstanceDisk(&instance, diskName, false, false),
+					testAccCheckComputeInstanceDisk(&instance, diskName2, false, false),
+					resource.TestCheckResourceAttrPair(instanceResource, "secondary_disk.0.disk_id",
+						"yandex_compute_disk.foobar", "id"),
+					resource.TestCheckResourceAttrPair(instanceResource, "secondary_disk.1.disk_id",
+						"yandex_compute_disk.foobar2", "id"),
+					resource.TestCheckResourceAttrPtr(instanceResource, "secondary_disk.0.device_name", &deviceName),
+				),
+			},
+			{
+				Config:   testAccComputeInstance_addAttachedDisk(diskName, diskName2, instanceName),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
 func TestAccComputeInstance_attachedDiskDelete(t *testing.T) {
 	t.Parallel()
 
@@ -559,6 +769,47 @@ func TestAccComputeInstance_forceNewAndChangeMetadata(t *testing.T) {
 	})
 }
 
+func TestAccComputeInstance_metadataWithSerialPort(t *testing.T) {
+	t.Parallel()
+
+	var instance compute.Instance
+	var instanceName = fmt.Sprintf("instance-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccComputeInstance_metadata(instanceName, "qux"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceExists(
+						instanceResource, &instance),
+					// Enable serial console outside of terraform, like the cloud console does.
+					testAccEnableComputeInstanceSerialPort(&instance),
+				),
+			},
+			{
+				Config:             testAccComputeInstance_metadata(instanceName, "qux"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+			{
+				Config: testAccComputeInstance_metadata(instanceName, "quux"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceExists(
+						instanceResource, &instance),
+					testAccCheckComputeInstanceMetadata(
+						&instance, "baz", "quux"),
+					testAccCheckComputeInstanceMetadata(
+						&instance, "serial-port-enable", "1"),
+					resource.TestCheckNoResourceAttr(instanceResource, "metadata.serial-port-enable"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccComputeInstance_update(t *testing.T) {
 	t.Parallel()
 
@@ -821,6 +1072,35 @@ func TestAccComputeInstance_address_custom(t *testing.T) {
 	})
 }
 
+func TestAccComputeInstance_address_customWithNat(t *testing.T) {
+	t.Parallel()
+
+	var instance compute.Instance
+	var instanceName = fmt.Sprintf("instance-test-%s", acctest.RandString(10))
+	var address = "192.168.19.16"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccComputeInstance_address_customWithNat(instanceName, address),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceExists(
+						instanceResource, &instance),
+					testAccCheckComputeInstanceHasAddress(&instance, address),
+					testAccCheckComputeInstanceHasNatAddress(&instance),
+					resource.TestCheckResourceAttr(instanceResource, "network_interface.0.ip_address", address),
+					resource.TestCheckResourceAttr(instanceResource, "network_interface.0.nat", "true"),
+					resource.TestCheckResourceAttrSet(instanceResource, "network_interface.0.nat_ip_address"),
+				),
+			},
+			computeInstanceImportStep(),
+		},
+	})
+}
+
 func TestAccComputeInstance_multiNic(t *testing.T) {
 	t.Skip("Currently only one network interface is supported per instance")
 	t.Parallel()
@@ -876,6 +1156,7 @@ func TestAccComputeInstance_update_scheduling_policy(t *testing.T) {
 	t.Parallel()
 
 	var instance compute.Instance
+	var instanceID string
 	var instanceName = fmt.Sprintf("instance-test-scheduling-policy-update-%s", acctest.RandString(10))
 
 	resource.Test(t, resource.TestCase{
@@ -888,16 +1169,29 @@ func TestAccComputeInstance_update_scheduling_policy(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckComputeInstanceExists(instanceResource, &instance),
 					testAccCheckComputeInstanceIsPreemptible(&instance, false),
+					func(s *terraform.State) error {
+						instanceID = instance.Id
+						return nil
+					},
 				),
 			},
 			{
 				Config: testAccComputeInstance_preemptible(instanceName, true),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckComputeInstanceExists(instanceResource, &instance),
-					resource.TestCheckResourceAttrPtr(instanceResource, "id", &instance.Id),
+					resource.TestCheckResourceAttrPtr(instanceResource, "id", &instanceID),
 					testAccCheckComputeInstanceIsPreemptible(&instance, true),
 				),
 			},
+			{
+				Config: testAccComputeInstance_preemptible(instanceName, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceExists(instanceResource, &instance),
+					resource.TestCheckResourceAttrPtr(instanceResource, "id", &instanceID),
+					testAccCheckComputeInstanceIsPreemptible(&instance, false),
+					resource.TestCheckResourceAttr(instanceResource, "scheduling_policy.0.preemptible", "false"),
+				),
+			},
 			computeInstanceImportStep(),
 		},
 	})
@@ -1174,28 +1468,375 @@ func TestComputeInstancePlacementPolicyRequest(t *testing.T) {
 	}
 }
 
-func TestAccComputeInstance_placement_host_rules(t *testing.T) {
-	t.Parallel()
+// computeInstanceDiffTestConfig returns a raw config of an instance with an existing boot disk
+// for tests running r.Diff, top-level attributes of the config are replaced by overrides.
+func computeInstanceDiffTestConfig(overrides map[string]interface{}) map[string]interface{} {
+	raw := map[string]interface{}{
+		"name":        "test-instance",
+		"zone":        "ru-central1-a",
+		"platform_id": "standard-v2",
+		"resources": []interface{}{
+			map[string]interface{}{
+				"cores":  2,
+				"memory": 2,
+			},
+		},
+		"boot_disk": []interface{}{
+			map[string]interface{}{
+				"disk_id": "test-disk-id",
+			},
+		},
+		"network_interface": []interface{}{
+			map[string]interface{}{
+				"subnet_id": "test-subnet-id",
+			},
+		},
+	}
+	for k, v := range overrides {
+		raw[k] = v
+	}
+	return raw
+}
 
-	var instance compute.Instance
-	var instanceName = fmt.Sprintf("instance-test-%s", acctest.RandString(10))
+func TestComputeInstanceResourcesDiff(t *testing.T) {
+	instanceWithResources := func(platformID string, cores, memory, gpus int) map[string]interface{} {
+		return computeInstanceDiffTestConfig(map[string]interface{}{
+			"platform_id": platformID,
+			"resources": []interface{}{
+				map[string]interface{}{
+					"cores":  cores,
+					"memory": memory,
+					"gpus":   gpus,
+				},
+			},
+		})
+	}
 
-	var hostID = os.Getenv("COMPUTE_HOST_ID")
-	var hostGroupID = os.Getenv("COMPUTE_HOST_GROUP_ID")
-	var placementGroupID = os.Getenv("COMPUTE_PLACEMENT_GROUP_ID")
-	if hostID == "" || hostGroupID == "" || placementGroupID == "" {
-		t.Skip("Required vars COMPUTE_HOST_ID, COMPUTE_PLACEMENT_GROUP_ID and COMPUTE_HOST_GROUP_ID are not set.")
+	r := resourceYandexComputeInstance()
+
+	cc := []struct {
+		name          string
+		config        map[string]interface{}
+		expectedError string
+	}{
+		{
+			name:   "valid standard platform resources",
+			config: instanceWithResources("standard-v2", 2, 4, 0),
+		},
+		{
+			name:   "valid gpu platform resources",
+			config: instanceWithResources("gpu-standard-v2", 16, 96, 2),
+		},
+		{
+			name:          "gpu platform with resources of another gpu count",
+			config:        instanceWithResources("gpu-standard-v2", 8, 48, 2),
+			expectedError: `not allowed on platform "gpu-standard-v2"`,
+		},
+		{
+			name:          "standard platform with odd number of cores",
+			config:        instanceWithResources("standard-v3", 3, 6, 0),
+			expectedError: `not allowed on platform "standard-v3"`,
+		},
 	}
 
-	resource.Test(t, resource.TestCase{
-		PreCheck:     func() { testAccPreCheck(t) },
-		Providers:    testAccProviders,
-		CheckDestroy: testAccCheckComputeInstanceDestroy,
-		Steps: []resource.TestStep{
-			{
-				Config: testAccComputeInstance_basic(instanceName),
-				Check: resource.ComposeTestCheckFunc(
-					testAccCheckComputeInstanceExists(
+	for _, c := range cc {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(c.config), nil)
+			if c.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), c.expectedError) {
+				t.Fatalf("expected error containing %q, got: %v", c.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestComputeInstanceGpuSettingsDiff(t *testing.T) {
+	instanceWithGpus := func(gpus int, overrides map[string]interface{}) map[string]interface{} {
+		overrides["platform_id"] = "gpu-standard-v3"
+		overrides["resources"] = []interface{}{
+			map[string]interface{}{
+				"cores":  28 * gpus,
+				"memory": 119 * gpus,
+				"gpus":   gpus,
+			},
+		}
+		return computeInstanceDiffTestConfig(overrides)
+	}
+	gpuSettings := []interface{}{map[string]interface{}{"gpu_cluster_id": "gpu-cluster-id"}}
+
+	r := resourceYandexComputeInstance()
+
+	cc := []struct {
+		name          string
+		config        map[string]interface{}
+		expectedError string
+	}{
+		{
+			name:   "gpu_settings with gpus",
+			config: instanceWithGpus(1, map[string]interface{}{"gpu_settings": gpuSettings}),
+		},
+		{
+			name:   "no gpu cluster without gpus",
+			config: computeInstanceDiffTestConfig(map[string]interface{}{}),
+		},
+		{
+			name:          "gpu_settings without gpus",
+			config:        computeInstanceDiffTestConfig(map[string]interface{}{"gpu_settings": gpuSettings}),
+			expectedError: "'resources.0.gpus' should be set",
+		},
+		{
+			name:          "gpu_cluster_id without gpus",
+			config:        computeInstanceDiffTestConfig(map[string]interface{}{"gpu_cluster_id": "gpu-cluster-id"}),
+			expectedError: "'resources.0.gpus' should be set",
+		},
+	}
+
+	for _, c := range cc {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(c.config), nil)
+			if c.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), c.expectedError) {
+				t.Fatalf("expected error containing %q, got: %v", c.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestIsComputeInstanceResourcesKnown(t *testing.T) {
+	rawConfig := func(cores cty.Value) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{
+			"resources": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{
+				"cores":         cores,
+				"memory":        cty.NumberIntVal(2),
+				"core_fraction": cty.NullVal(cty.Number),
+				"gpus":          cty.NullVal(cty.Number),
+			})}),
+		})
+	}
+
+	if !isComputeInstanceResourcesKnown(rawConfig(cty.NumberIntVal(2))) {
+		t.Fatalf("expected configured resources to be known")
+	}
+	if isComputeInstanceResourcesKnown(rawConfig(cty.UnknownVal(cty.Number))) {
+		t.Fatalf("expected resources with unknown cores to be skipped")
+	}
+	if !isComputeInstanceResourcesKnown(cty.NilVal) {
+		t.Fatalf("expected resources without raw config to be known")
+	}
+}
+
+func TestComputeInstanceMetadataSizeDiff(t *testing.T) {
+	instanceWithUserData := func(userData string) map[string]interface{} {
+		return computeInstanceDiffTestConfig(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"ssh-keys":  "ubuntu:ssh-rsa AAAA",
+				"user-data": userData,
+			},
+		})
+	}
+
+	r := resourceYandexComputeInstance()
+
+	_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(instanceWithUserData("#cloud-config\n")), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, err = r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(instanceWithUserData(strings.Repeat("a", computeInstanceMetadataMaxSize))), nil)
+	if err == nil {
+		t.Fatalf("expected error for oversized metadata, got nil")
+	}
+	if !strings.Contains(err.Error(), `"user-data"`) {
+		t.Fatalf("expected error to name the user-data key, got: %s", err)
+	}
+}
+
+func TestComputeInstanceSchedulingPolicyDiff(t *testing.T) {
+	instanceWithPreemptible := func(preemptible bool) map[string]interface{} {
+		return computeInstanceDiffTestConfig(map[string]interface{}{
+			"allow_stopping_for_update": true,
+			"scheduling_policy": []interface{}{
+				map[string]interface{}{
+					"preemptible": preemptible,
+				},
+			},
+		})
+	}
+
+	r := resourceYandexComputeInstance()
+
+	for _, from := range []bool{true, false} {
+		t.Run(fmt.Sprintf("preemptible %t to %t", from, !from), func(t *testing.T) {
+			initial := schema.TestResourceDataRaw(t, r.Schema, instanceWithPreemptible(from))
+			initial.SetId("test-instance-id")
+
+			diff, err := r.Diff(context.Background(), initial.State(), terraform.NewResourceConfigRaw(instanceWithPreemptible(!from)), nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff == nil || diff.Attributes["scheduling_policy.0.preemptible"] == nil {
+				t.Fatalf("expected scheduling_policy.0.preemptible change, got %v", diff)
+			}
+			if diff.RequiresNew() {
+				t.Fatalf("changing preemptible must not require instance recreation")
+			}
+		})
+	}
+}
+
+func TestComputeInstanceSecondaryDiskDiff(t *testing.T) {
+	instanceWithSecondaryDisks := func(disks ...interface{}) map[string]interface{} {
+		return computeInstanceDiffTestConfig(map[string]interface{}{"secondary_disk": disks})
+	}
+	inlineDisk := func(name string, size int) interface{} {
+		return map[string]interface{}{
+			"initialize_params": []interface{}{
+				map[string]interface{}{
+					"name": name,
+					"size": size,
+				},
+			},
+		}
+	}
+
+	r := resourceYandexComputeInstance()
+
+	initial := schema.TestResourceDataRaw(t, r.Schema, instanceWithSecondaryDisks(inlineDisk("first", 4)))
+	initial.SetId("test-instance-id")
+	attachedDisk := inlineDisk("first", 4).(map[string]interface{})
+	attachedDisk["disk_id"] = "test-secondary-disk-id"
+	if err := initial.Set("secondary_disk", []interface{}{attachedDisk}); err != nil {
+		t.Fatalf("bad: %#v", err)
+	}
+
+	cc := []struct {
+		name          string
+		config        map[string]interface{}
+		expectedError string
+	}{
+		{
+			name:   "unchanged",
+			config: instanceWithSecondaryDisks(inlineDisk("first", 4)),
+		},
+		{
+			name:   "disk appended",
+			config: instanceWithSecondaryDisks(inlineDisk("first", 4), inlineDisk("second", 8)),
+		},
+		{
+			name:          "initialize_params changed",
+			config:        instanceWithSecondaryDisks(inlineDisk("first", 8)),
+			expectedError: "secondary_disk.0.initialize_params",
+		},
+		{
+			name:          "disk inserted before an attached one",
+			config:        instanceWithSecondaryDisks(inlineDisk("second", 8), inlineDisk("first", 4)),
+			expectedError: "secondary_disk.0.initialize_params",
+		},
+	}
+
+	for _, c := range cc {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := r.Diff(context.Background(), initial.State(), terraform.NewResourceConfigRaw(c.config), nil)
+			if c.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), c.expectedError) {
+				t.Fatalf("expected error containing %q, got: %v", c.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestComputeInstancePlacementPolicyDiff(t *testing.T) {
+	instanceWithPlacement := func(placementGroupID, hostGroupID string) map[string]interface{} {
+		return computeInstanceDiffTestConfig(map[string]interface{}{
+			"placement_policy": []interface{}{
+				map[string]interface{}{
+					"placement_group_id": placementGroupID,
+					"host_affinity_rules": []interface{}{
+						map[string]interface{}{
+							"key":    "yc.hostGroupId",
+							"op":     "IN",
+							"values": []interface{}{hostGroupID},
+						},
+					},
+				},
+			},
+		})
+	}
+
+	r := resourceYandexComputeInstance()
+	initial := schema.TestResourceDataRaw(t, r.Schema, instanceWithPlacement("pg-1", "hg-1"))
+	initial.SetId("test-instance-id")
+
+	cc := []struct {
+		name           string
+		config         map[string]interface{}
+		expectedChange string
+	}{
+		{
+			name:           "change affinity rule value",
+			config:         instanceWithPlacement("pg-1", "hg-2"),
+			expectedChange: "placement_policy.0.host_affinity_rules.0.values.0",
+		},
+		{
+			name:           "change placement group",
+			config:         instanceWithPlacement("pg-2", "hg-1"),
+			expectedChange: "placement_policy.0.placement_group_id",
+		},
+	}
+
+	for _, c := range cc {
+		t.Run(c.name, func(t *testing.T) {
+			diff, err := r.Diff(context.Background(), initial.State(), terraform.NewResourceConfigRaw(c.config), nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff == nil || diff.Attributes[c.expectedChange] == nil {
+				t.Fatalf("expected %s to be changed, got diff %v", c.expectedChange, diff)
+			}
+			if diff.RequiresNew() {
+				t.Fatalf("changing placement policy must not recreate the instance, got diff %v", diff)
+			}
+		})
+	}
+}
+
+func TestAccComputeInstance_placement_host_rules(t *testing.T) {
+	t.Parallel()
+
+	var instance compute.Instance
+	var instanceName = fmt.Sprintf("instance-test-%s", acctest.RandString(10))
+
+	var hostID = os.Getenv("COMPUTE_HOST_ID")
+	var hostGroupID = os.Getenv("COMPUTE_HOST_GROUP_ID")
+	var placementGroupID = os.Getenv("COMPUTE_PLACEMENT_GROUP_ID")
+	if hostID == "" || hostGroupID == "" || placementGroupID == "" {
+		t.Skip("Required vars COMPUTE_HOST_ID, COMPUTE_PLACEMENT_GROUP_ID and COMPUTE_HOST_GROUP_ID are not set.")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccComputeInstance_basic(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceExists(
 						instanceResource, &instance),
 				),
 			},
@@ -1257,6 +1898,49 @@ func TestAccComputeInstance_placement_host_rules(t *testing.T) {
 	})
 }
 
+func TestAccComputeInstance_placement_host_rules_update(t *testing.T) {
+	t.Parallel()
+
+	var instance compute.Instance
+	var instanceID string
+	var instanceName = fmt.Sprintf("instance-test-%s", acctest.RandString(10))
+
+	var hostGroupID = os.Getenv("COMPUTE_HOST_GROUP_ID")
+	var secondHostGroupID = os.Getenv("COMPUTE_SECOND_HOST_GROUP_ID")
+	if hostGroupID == "" || secondHostGroupID == "" {
+		t.Skip("Required vars COMPUTE_HOST_GROUP_ID and COMPUTE_SECOND_HOST_GROUP_ID are not set.")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccComputeInstance_with_placement_hostgroup(instanceName, "", hostGroupID),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceExists(
+						instanceResource, &instance),
+					func(s *terraform.State) error {
+						instanceID = instance.Id
+						return nil
+					},
+					testAccCheckComputeInstanceHasAffinityRules(&instance, map[string]string{"yc.hostGroupId": hostGroupID}),
+				),
+			},
+			{
+				Config: testAccComputeInstance_with_placement_hostgroup(instanceName, "", secondHostGroupID),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceExists(
+						instanceResource, &instance),
+					resource.TestCheckResourceAttrPtr(instanceResource, "id", &instanceID),
+					testAccCheckComputeInstanceHasAffinityRules(&instance, map[string]string{"yc.hostGroupId": secondHostGroupID}),
+				),
+			},
+		},
+	})
+}
+
 func TestAccComputeInstance_move(t *testing.T) {
 	t.Parallel()
 
@@ -1402,6 +2086,44 @@ func TestAccComputeInstance_local_disks(t *testing.T) {
 	})
 }
 
+func TestAccComputeInstance_localDiskAndFilesystemImport(t *testing.T) {
+	t.Parallel()
+
+	var instance compute.Instance
+	var fs compute.Filesystem
+	var instanceName = acctest.RandomWithPrefix("tf-test")
+	var fsName = acctest.RandomWithPrefix("tf-test")
+
+	var diskSize = os.Getenv("COMPUTE_LOCAL_DISK_SIZE")
+	if diskSize == "" {
+		t.Skip("Required var COMPUTE_LOCAL_DISK_SIZE is not set.")
+	}
+
+	diskSizeBytes, err := strconv.Atoi(diskSize)
+	if err != nil {
+		t.Errorf("parse disk size: %v", err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeInstanceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccComputeInstance_localDiskAndFilesystem(fsName, instanceName, diskSizeBytes),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceExists(instanceResource, &instance),
+					testAccCheckComputeFilesystemExists("yandex_compute_filesystem.foobar", &fs),
+					testAccCheckComputeInstanceHasLocalDisk(&instance, int64(diskSizeBytes)),
+					testAccCheckComputeInstanceFilesystem(&instance, []string{fsName}),
+					resource.TestCheckResourceAttrSet(instanceResource, "local_disk.0.device_name"),
+				),
+			},
+			computeInstanceImportStep(),
+		},
+	})
+}
+
 func TestAccComputeInstance_filesystem(t *testing.T) {
 	t.Parallel()
 
@@ -1481,6 +2203,39 @@ func TestAccComputeInstance_GpuCluster(t *testing.T) {
 	})
 }
 
+func TestAccComputeInstance_GpuSettings(t *testing.T) {
+	t.Parallel()
+
+	var instance compute.Instance
+	var gpuCluster compute.GpuCluster
+
+	var instanceName = acctest.RandomWithPrefix("tf-test")
+	var gpuClusterName = acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckComputeInstanceDestroy,
+		Steps: []resource.TestStep{
+			// Create instance within a GPU cluster set with gpu_settings block
+			{
+				Config: testAccComputeInstance_GpuSettings(gpuClusterName, instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckComputeInstanceExists(
+						"yandex_compute_instance.foobar", &instance),
+					testAccCheckComputeGpuClusterExists("yandex_compute_gpu_cluster.foobar", &gpuCluster),
+					testAccCheckComputeInstanceGpuCluster(&instance, &gpuCluster.Id),
+					resource.TestCheckResourceAttrPair("yandex_compute_instance.foobar", "gpu_settings.0.gpu_cluster_id",
+						"yandex_compute_gpu_cluster.foobar", "id"),
+					resource.TestCheckResourceAttrPair("yandex_compute_instance.foobar", "gpu_cluster_id",
+						"yandex_compute_gpu_cluster.foobar", "id"),
+				),
+			},
+			computeInstanceImportStep(),
+		},
+	})
+}
+
 func TestAccComputeInstance_Nat(t *testing.T) {
 	t.Parallel()
 
@@ -1564,6 +2319,22 @@ func testAccCheckComputeInstanceExists(n string, instance *compute.Instance) res
 	}
 }
 
+func testAccEnableComputeInstanceSerialPort(instance *compute.Instance) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		config := testAccProvider.Meta().(*Config)
+
+		op, err := config.sdk.WrapOperation(config.sdk.Compute().Instance().UpdateMetadata(context.Background(), &compute.UpdateInstanceMetadataRequest{
+			InstanceId: instance.Id,
+			Upsert:     map[string]string{"serial-port-enable": "1"},
+		}))
+		if err != nil {
+			return fmt.Errorf("Error while requesting API to update Instance %q metadata: %s", instance.Id, err)
+		}
+
+		return op.Wait(context.Background())
+	}
+}
+
 func testAccCheckComputeInstanceMetadata(
 	instance *compute.Instance,
 	k string, v string) resource.TestCheckFunc {
@@ -1923,8 +2694,8 @@ func testAccCheckComputeInstanceHasMultiNic(instance *compute.Instance) resource
 
 func testAccCheckComputeInstanceIsPreemptible(instance *compute.Instance, expect bool) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		if instance.SchedulingPolicy.Preemptible != expect {
-			return fmt.Errorf("instance preemptible attr wrong: expected %v, got %v", expect, instance.SchedulingPolicy.Preemptible)
+		if instance.GetSchedulingPolicy().GetPreemptible() != expect {
+			return fmt.Errorf("instance preemptible attr wrong: expected %v, got %v", expect, instance.GetSchedulingPolicy().GetPreemptible())
 		}
 		return nil
 	}
@@ -2055,21 +2826,157 @@ func testAccCheckComputeInstanceFilesystem(instance *compute.Instance, fsNames [
 	}
 }
 
-func testAccCheckComputeInstanceGpuCluster(instance *compute.Instance, expectedID *string) resource.TestCheckFunc {
-	return func(s *terraform.State) error {
-		var gpuClusterID string
-		if settings := instance.GpuSettings; settings != nil {
-			gpuClusterID = settings.GpuClusterId
-		}
-		if gpuClusterID != *expectedID {
-			return fmt.Errorf("Unexpected GPU cluster ID, actual = %v, expected = %v", gpuClusterID, *expectedID)
-		}
-		return nil
-	}
+func testAccCheckComputeInstanceGpuCluster(instance *compute.Instance, expectedID *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		var gpuClusterID string
+		if settings := instance.GpuSettings; settings != nil {
+			gpuClusterID = settings.GpuClusterId
+		}
+		if gpuClusterID != *expectedID {
+			return fmt.Errorf("Unexpected GPU cluster ID, actual = %v, expected = %v", gpuClusterID, *expectedID)
+		}
+		return nil
+	}
+}
+
+//revive:disable:var-naming
+func testAccComputeInstance_basic(instance string) string {
+	return fmt.Sprintf(`
+data "yandex_compute_image" "ubuntu" {
+  family = "ubuntu-1804-lts"
+}
+
+resource "yandex_compute_instance" "foobar" {
+  name        = "%s"
+  description = "testAccComputeInstance_basic"
+  platform_id = "standard-v2"
+  zone        = "ru-central1-a"
+
+  resources {
+    cores  = 2
+    memory = 2
+  }
+
+  boot_disk {
+    initialize_params {
+      size     = 4
+      image_id = "${data.yandex_compute_image.ubuntu.id}"
+    }
+  }
+
+  network_interface {
+    subnet_id = "${yandex_vpc_subnet.inst-test-subnet.id}"
+  }
+
+  metadata = {
+    foo = "bar"
+    baz = "qux"
+  }
+
+  metadata_options {
+	gce_http_endpoint = 1
+	aws_v1_http_endpoint = 1
+	gce_http_token = 1
+	aws_v1_http_token = 2
+  }
+
+  labels = {
+    my_key       = "my_value"
+    my_other_key = "my_other_value"
+  }
+}
+
+resource "yandex_vpc_network" "inst-test-network" {}
+
+resource "yandex_vpc_subnet" "inst-test-subnet" {
+  zone           = "ru-central1-a"
+  network_id     = "${yandex_vpc_network.inst-test-network.id}"
+  v4_cidr_blocks = ["192.168.0.0/24"]
+}
+`, instance)
+}
+
+func testAccComputeInstance_bootDiskFromImageFamily(instance string) string {
+	return fmt.Sprintf(`
+data "yandex_compute_image" "ubuntu" {
+  family = "ubuntu-1804-lts"
+}
+
+resource "yandex_compute_instance" "foobar" {
+  name        = "%s"
+  description = "testAccComputeInstance_bootDiskFromImageFamily"
+  platform_id = "standard-v2"
+  zone        = "ru-central1-a"
+
+  resources {
+    cores  = 2
+    memory = 2
+  }
+
+  boot_disk {
+    initialize_params {
+      size         = 8
+      block_size   = 8192
+      image_family = "ubuntu-1804-lts"
+    }
+  }
+
+  network_interface {
+    subnet_id = "${yandex_vpc_subnet.inst-test-subnet.id}"
+  }
+}
+
+resource "yandex_vpc_network" "inst-test-network" {}
+
+resource "yandex_vpc_subnet" "inst-test-subnet" {
+  zone           = "ru-central1-a"
+  network_id     = "${yandex_vpc_network.inst-test-network.id}"
+  v4_cidr_blocks = ["192.168.0.0/24"]
+}
+`, instance)
+}
+
+func testAccComputeInstance_desiredStatus(instance, desiredStatus string) string {
+	return fmt.Sprintf(`
+data "yandex_compute_image" "ubuntu" {
+  family = "ubuntu-1804-lts"
+}
+
+resource "yandex_compute_instance" "foobar" {
+  name           = "%s"
+  description    = "testAccComputeInstance_desiredStatus"
+  platform_id    = "standard-v2"
+  zone           = "ru-central1-a"
+  desired_status = "%s"
+
+  resources {
+    cores  = 2
+    memory = 2
+  }
+
+  boot_disk {
+    initialize_params {
+      size     = 4
+      image_id = "${data.yandex_compute_image.ubuntu.id}"
+    }
+  }
+
+  network_interface {
+    subnet_id = "${yandex_vpc_subnet.inst-test-subnet.id}"
+  }
+}
+
+resource "yandex_vpc_network" "inst-test-network" {}
+
+resource "yandex_vpc_subnet" "inst-test-subnet" {
+  zone           = "ru-central1-a"
+  network_id     = "${yandex_vpc_network.inst-test-network.id}"
+  v4_cidr_blocks = ["192.168.0.0/24"]
+}
+`, instance, desiredStatus)
 }
 
-//revive:disable:var-naming
-func testAccComputeInstance_basic(instance string) string {
+func testAccComputeInstance_metadata(instance, baz string) string {
 	return fmt.Sprintf(`
 data "yandex_compute_image" "ubuntu" {
   family = "ubuntu-1804-lts"
@@ -2077,7 +2984,7 @@ data "yandex_compute_image" "ubuntu" {
 
 resource "yandex_compute_instance" "foobar" {
   name        = "%s"
-  description = "testAccComputeInstance_basic"
+  description = "testAccComputeInstance_metadata"
   platform_id = "standard-v2"
   zone        = "ru-central1-a"
 
@@ -2099,19 +3006,7 @@ resource "yandex_compute_instance" "foobar" {
 
   metadata = {
     foo = "bar"
-    baz = "qux"
-  }
-
-  metadata_options {
-	gce_http_endpoint = 1
-	aws_v1_http_endpoint = 1
-	gce_http_token = 1
-	aws_v1_http_token = 2
-  }
-
-  labels = {
-    my_key       = "my_value"
-    my_other_key = "my_other_value"
+    baz = "%s"
   }
 }
 
@@ -2122,7 +3017,7 @@ resource "yandex_vpc_subnet" "inst-test-subnet" {
   network_id     = "${yandex_vpc_network.inst-test-network.id}"
   v4_cidr_blocks = ["192.168.0.0/24"]
 }
-`, instance)
+`, instance, baz)
 }
 
 func testAccComputeInstance_gpus(instance string) string {
@@ -2573,6 +3468,57 @@ resource "yandex_vpc_subnet" "inst-update-test-subnet" {
 `, instance)
 }
 
+func testAccComputeInstance_SecurityGroupsOrdered(instance, firstSG, secondSG string) string {
+	return fmt.Sprintf(`
+data "yandex_compute_image" "ubuntu" {
+  family = "ubuntu-1804-lts"
+}
+
+resource "yandex_compute_instance" "foobar" {
+  name        = "%s"
+  description = "testAccComputeInstance_SecurityGroupsOrdered"
+  zone        = "ru-central1-b"
+  platform_id = "standard-v2"
+
+  resources {
+    cores         = 2
+    core_fraction = 5
+    memory        = 0.5
+  }
+
+  boot_disk {
+    initialize_params {
+      size     = 4
+      image_id = "${data.yandex_compute_image.ubuntu.id}"
+    }
+  }
+
+  network_interface {
+    subnet_id          = "${yandex_vpc_subnet.inst-test-subnet.id}"
+    security_group_ids = ["${yandex_vpc_security_group.%s.id}", "${yandex_vpc_security_group.%s.id}"]
+  }
+}
+
+resource "yandex_vpc_network" "inst-test-network" {}
+
+resource "yandex_vpc_security_group" "sg1" {
+  name       = "tf-test-sg-1"
+  network_id = "${yandex_vpc_network.inst-test-network.id}"
+}
+
+resource "yandex_vpc_security_group" "sg2" {
+  name       = "tf-test-sg-2"
+  network_id = "${yandex_vpc_network.inst-test-network.id}"
+}
+
+resource "yandex_vpc_subnet" "inst-test-subnet" {
+  zone           = "ru-central1-b"
+  network_id     = "${yandex_vpc_network.inst-test-network.id}"
+  v4_cidr_blocks = ["192.168.0.0/24"]
+}
+`, instance, firstSG, secondSG)
+}
+
 // Update network_interface
 func testAccComputeInstance_update_add_SecurityGroups(instance string) string {
 	// language=tf
@@ -3026,6 +3972,66 @@ resource "yandex_vpc_subnet" "inst-test-subnet" {
 `, disk, instance)
 }
 
+func testAccComputeInstance_secondaryDiskFromSnapshot(disk, snapshot, secondaryDisk, instance string) string {
+	return fmt.Sprintf(`
+data "yandex_compute_image" "ubuntu" {
+  family = "ubuntu-1804-lts"
+}
+
+resource "yandex_compute_disk" "foobar" {
+  name     = "%s"
+  size     = 10
+  zone     = "ru-central1-a"
+  image_id = "${data.yandex_compute_image.ubuntu.id}"
+}
+
+resource "yandex_compute_snapshot" "foobar" {
+  name           = "%s"
+  source_disk_id = "${yandex_compute_disk.foobar.id}"
+}
+
+resource "yandex_compute_instance" "foobar" {
+  name = "%s"
+  zone = "ru-central1-a"
+  platform_id = "standard-v2"
+
+  resources {
+    cores  = 2
+    memory = 2
+  }
+
+  boot_disk {
+    initialize_params {
+      image_id = "${data.yandex_compute_image.ubuntu.id}"
+    }
+  }
+
+  secondary_disk {
+    auto_delete = true
+
+    initialize_params {
+      name        = "%s"
+      size        = 20
+      type        = "network-ssd"
+      snapshot_id = "${yandex_compute_snapshot.foobar.id}"
+    }
+  }
+
+  network_interface {
+    subnet_id = "${yandex_vpc_subnet.inst-test-subnet.id}"
+  }
+}
+
+resource "yandex_vpc_network" "inst-test-network" {}
+
+resource "yandex_vpc_subnet" "inst-test-subnet" {
+  zone           = "ru-central1-a"
+  network_id     = "${yandex_vpc_network.inst-test-network.id}"
+  v4_cidr_blocks = ["192.168.0.0/24"]
+}
+`, disk, snapshot, instance, secondaryDisk)
+}
+
 func testAccComputeInstance_attachedDisk_sourceUrl(disk, instance string) string {
 	return fmt.Sprintf(`
 data "yandex_compute_image" "ubuntu" {
@@ -3568,6 +4574,48 @@ resource "yandex_compute_instance" "foobar" {
 `, acctest.RandString(10), acctest.RandString(10), instance, address)
 }
 
+func testAccComputeInstance_address_customWithNat(instance, address string) string {
+	return fmt.Sprintf(`
+data "yandex_compute_image" "ubuntu" {
+  family = "ubuntu-1804-lts"
+}
+
+resource "yandex_vpc_network" "inst-test-network" {
+  name = "inst-test-network-%s"
+}
+
+resource "yandex_vpc_subnet" "inst-test-subnet" {
+  name           = "inst-test-subnet-%s"
+  zone           = "ru-central1-a"
+  network_id     = "${yandex_vpc_network.inst-test-network.id}"
+  v4_cidr_blocks = ["192.168.19.0/24"]
+}
+
+resource "yandex_compute_instance" "foobar" {
+  name = "%s"
+  zone = "ru-central1-a"
+  platform_id = "standard-v2"
+
+  resources {
+    cores  = 2
+    memory = 2
+  }
+
+  boot_disk {
+    initialize_params {
+      image_id = "${data.yandex_compute_image.ubuntu.id}"
+    }
+  }
+
+  network_interface {
+    subnet_id  = "${yandex_vpc_subnet.inst-test-subnet.id}"
+    ip_address = "%s"
+    nat        = true
+  }
+}
+`, acctest.RandString(10), acctest.RandString(10), instance, address)
+}
+
 //nolint:unused
 func testAccComputeInstance_multiNic(instance, network, subnetwork string) string {
 	return fmt.Sprintf(`
@@ -4320,6 +5368,59 @@ func testAccComputeInstance_filesystem(fs, instance string) string {
 `, fs, instance)
 }
 
+func testAccComputeInstance_localDiskAndFilesystem(fs, instance string, diskSize int) string {
+	return fmt.Sprintf(`
+data "yandex_compute_image" "ubuntu" {
+  family = "ubuntu-1804-lts"
+}
+
+resource "yandex_compute_filesystem" "foobar" {
+  name = "%s"
+  size = 15
+  type = "network-hdd"
+}
+
+resource "yandex_compute_instance" "foobar" {
+  name        = "%s"
+  zone        = "ru-central1-a"
+  platform_id = "standard-v2"
+  allow_stopping_for_update = true
+
+  resources {
+    cores  = 2
+    memory = 2
+  }
+
+  boot_disk {
+    initialize_params {
+      size     = 4
+      image_id = "${data.yandex_compute_image.ubuntu.id}"
+    }
+  }
+
+  network_interface {
+    subnet_id = "${yandex_vpc_subnet.inst-test-subnet.id}"
+  }
+
+  local_disk {
+    size_bytes = %d
+  }
+
+  filesystem {
+    filesystem_id = "${yandex_compute_filesystem.foobar.id}"
+  }
+}
+
+resource "yandex_vpc_network" "inst-test-network" {}
+
+resource "yandex_vpc_subnet" "inst-test-subnet" {
+  zone           = "ru-central1-a"
+  network_id     = "${yandex_vpc_network.inst-test-network.id}"
+  v4_cidr_blocks = ["192.168.0.0/24"]
+}
+`, fs, instance, diskSize)
+}
+
 func testAccComputeInstance_GpuCluster(gpuCluster, instance string) string {
 	return fmt.Sprintf(`
 	data "yandex_compute_image" "ubuntu" {
@@ -4366,6 +5467,54 @@ func testAccComputeInstance_GpuCluster(gpuCluster, instance string) string {
 `, gpuCluster, instance)
 }
 
+func testAccComputeInstance_GpuSettings(gpuCluster, instance string) string {
+	return fmt.Sprintf(`
+	data "yandex_compute_image" "ubuntu" {
+		family = "ubuntu-2004-lts"
+	}
+
+	resource "yandex_compute_gpu_cluster" "foobar" {
+		name              = "%s"
+		interconnect_type = "infiniband"
+		zone              = "ru-central1-a"
+	}
+
+	resource "yandex_compute_instance" "foobar" {
+		name = "%s"
+		zone = "ru-central1-a"
+		platform_id = "gpu-standard-v3"
+
+		resources {
+			gpus   = 8
+			cores  = 224
+			memory = 952
+		}
+
+		boot_disk {
+			initialize_params {
+				image_id = data.yandex_compute_image.ubuntu.id
+			}
+		}
+
+		network_interface {
+			subnet_id = yandex_vpc_subnet.inst-test-subnet.id
+		}
+
+		gpu_settings {
+			gpu_cluster_id = yandex_compute_gpu_cluster.foobar.id
+		}
+	}
+
+	resource "yandex_vpc_network" "inst-test-network" {}
+
+	resource "yandex_vpc_subnet" "inst-test-subnet" {
+		zone           = "ru-central1-a"
+		network_id     = yandex_vpc_network.inst-test-network.id
+		v4_cidr_blocks = ["192.168.0.0/24"]
+}
+`, gpuCluster, instance)
+}
+
 func testAccComputeInstance_attachFilesystem(fs, newFs, instance string) string {
 	return fmt.Sprintf(`
 	data "yandex_compute_image" "ubuntu" {
@@ -608,3 +608,54 @@ func testAccDataTransferConfigYdbTarget(name, description string) string {
     }
 }`, name, description)
 }
+
+func TestAccDataTransferPostgresSourceEndpointMDBCluster(t *testing.T) {
+	t.Parallel()
+	const pgSourceEndpointResourceName = "pg-mdb-source"
+	const fullResourceName = "yandex_datatransfer_endpoint.pg_mdb_source"
+	clusterName := "pg-mdb-source-cluster" + randomPostfix
+	version := postgresql_versions[rand.Intn(len(postgresql_versions))]
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDataTransferDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataTransferConfigPostgresSourceMDBCluster(pgSourceEndpointResourceName+randomPostfix, clusterName, version),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(fullResourceName, "name", pgSourceEndpointResourceName+randomPostfix),
+					resource.TestCheckResourceAttrPair(fullResourceName, "settings.0.postgres_source.0.connection.0.mdb_cluster_id", "yandex_mdb_postgresql_cluster.foo", "id"),
+					resource.TestCheckResourceAttr(fullResourceName, "settings.0.postgres_source.0.connection.0.on_premise.#", "0"),
+					resource.TestCheckResourceAttr(fullResourceName, "settings.0.postgres_source.0.database", "testdb"),
+					resource.TestCheckResourceAttr(fullResourceName, "settings.0.postgres_source.0.user", "alice"),
+				),
+			},
+			{
+				ResourceName:            fullResourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"settings.0.postgres_source.0.password"},
+			},
+		},
+	})
+}
+
+func testAccDataTransferConfigPostgresSourceMDBCluster(name, clusterName, version string) string {
+	return testAccMDBPGClusterConfigMain(clusterName, "TestAccDataTransfer", "PRESTABLE", version, false) + fmt.Sprintf(`
+resource "yandex_datatransfer_endpoint" "pg_mdb_source" {
+  name = "%s"
+  settings {
+    postgres_source {
+      connection {
+        mdb_cluster_id = yandex_mdb_postgresql_cluster.foo.id
+      }
+      database = "testdb"
+      user     = "alice"
+      password {
+        raw = "mysecurepassword"
+      }
+    }
+  }
+}
+`, name)
+}
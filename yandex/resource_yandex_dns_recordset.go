@@ -30,6 +30,8 @@ func resourceYandexDnsRecordSet() *schema.Resource {
 
 		SchemaVersion: 0,
 
+		CustomizeDiff: dnsRecordSetSyncPtrDiffCustomize,
+
 		Schema: map[string]*schema.Schema{
 			"zone_id": {
 				Type:     schema.TypeString,
@@ -69,6 +71,12 @@ func resourceYandexDnsRecordSet() *schema.Resource {
 				Set:              schema.HashString,
 				DiffSuppressFunc: dataDiffSuppressFunc,
 			},
+
+			"sync_ptr": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 		},
 	}
 }
@@ -108,6 +116,13 @@ func resourceYandexDnsRecordSetCreate(d *schema.ResourceData, meta interface{})
 
 	d.SetId(fmt.Sprintf("%s/%s/%s", d.Get("zone_id"), d.Get("name"), d.Get("type")))
 
+	if d.Get("sync_ptr").(bool) {
+		err = syncDnsRecordSetPtr(ctx, config, d, nil, rs.Data)
+		if err != nil {
+			return err
+		}
+	}
+
 	return resourceYandexDnsRecordSetRead(d, meta)
 }
 
@@ -134,14 +149,39 @@ func resourceYandexDnsRecordSetRead(d *schema.ResourceData, meta interface{}) er
 }
 
 func resourceYandexDnsRecordSetUpdate(d *schema.ResourceData, meta interface{}) error {
-	req, err := prepareDnsRecordSetUpdateRequest(d)
-	if err != nil {
-		return err
+	if d.HasChanges("ttl", "data") {
+		req, err := prepareDnsRecordSetUpdateRequest(d)
+		if err != nil {
+			return err
+		}
+
+		err = makeDnsRecordSetUpdateRequest(req, d, meta)
+		if err != nil {
+			return err
+		}
 	}
 
-	err = makeDnsRecordSetUpdateRequest(req, d, meta)
-	if err != nil {
-		return err
+	if d.HasChanges("sync_ptr", "ttl", "data") {
+		config := meta.(*Config)
+		ctx, cancel := context.WithTimeout(config.Context(), d.Timeout(schema.TimeoutUpdate))
+		defer cancel()
+
+		var oldAddresses, newAddresses []string
+		oldSync, newSync := d.GetChange("sync_ptr")
+		oldData, newData := d.GetChange("data")
+		if oldSync.(bool) {
+			oldAddresses = convertStringSet(oldData.(*schema.Set))
+		}
+		if newSync.(bool) {
+			newAddresses = convertStringSet(newData.(*schema.Set))
+		}
+
+		if len(oldAddresses) > 0 || len(newAddresses) > 0 {
+			err := syncDnsRecordSetPtr(ctx, config, d, oldAddresses, newAddresses)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	return resourceYandexDnsRecordSetRead(d, meta)
@@ -180,6 +220,13 @@ func resourceYandexDnsRecordSetDelete(d *schema.ResourceData, meta interface{})
 		return fmt.Errorf("DnsRecordSet creation failed: %s", err)
 	}
 
+	if d.Get("sync_ptr").(bool) {
+		err = syncDnsRecordSetPtr(ctx, config, d, rs.Data, nil)
+		if err != nil {
+			return err
+		}
+	}
+
 	log.Printf("[DEBUG] Finished deleting DnsRecordSet %s", rsId(d))
 	return nil
 }
@@ -235,6 +282,172 @@ func makeDnsRecordSetUpdateRequest(req *dns.UpdateRecordSetsRequest, d *schema.R
 	return nil
 }
 
+// dnsRecordSetSyncPtrDiffCustomize rejects sync_ptr for record set types without addresses.
+func dnsRecordSetSyncPtrDiffCustomize(ctx context.Context, rdiff *schema.ResourceDiff, _ interface{}) error {
+	if !rdiff.Get("sync_ptr").(bool) || !rdiff.NewValueKnown("type") {
+		return nil
+	}
+
+	rsType := strings.ToUpper(rdiff.Get("type").(string))
+	if rsType != "A" && rsType != "AAAA" {
+		return fmt.Errorf("sync_ptr is supported only for A and AAAA record sets, got %s", rsType)
+	}
+	return nil
+}
+
+// syncDnsRecordSetPtr removes PTR records of the removed addresses and creates or replaces
+// PTR records of the current addresses, in the reverse zones of the record set's folder.
+func syncDnsRecordSetPtr(ctx context.Context, config *Config, d *schema.ResourceData, oldAddresses, newAddresses []string) error {
+	sdk := getSDK(config)
+	zone, err := sdk.DNS().DnsZone().Get(ctx, &dns.GetDnsZoneRequest{
+		DnsZoneId: d.Get("zone_id").(string),
+	})
+	if err != nil {
+		return fmt.Errorf("Error while getting DnsZone %q to sync PTR records: %s", d.Get("zone_id"), err)
+	}
+
+	zones, err := listDnsZones(ctx, config, zone.FolderId)
+	if err != nil {
+		return err
+	}
+
+	target := dnsRecordSetFQDN(d.Get("name").(string), zone.Zone)
+	ttl := int64(d.Get("ttl").(int))
+
+	requests := map[string]*dns.UpsertRecordSetsRequest{}
+	requestForAddress := func(address string) (*dns.UpsertRecordSetsRequest, string, error) {
+		reverseName, err := dnsReverseName(address)
+		if err != nil {
+			return nil, "", err
+		}
+		reverseZone := findDnsReverseZone(zones, reverseName)
+		if reverseZone == nil {
+			return nil, "", fmt.Errorf("no reverse DNS zone for %s found in folder %s", reverseName, zone.FolderId)
+		}
+		req, ok := requests[reverseZone.Id]
+		if !ok {
+			req = &dns.UpsertRecordSetsRequest{DnsZoneId: reverseZone.Id}
+			requests[reverseZone.Id] = req
+		}
+		return req, reverseName, nil
+	}
+
+	current := map[string]bool{}
+	for _, address := range newAddresses {
+		current[address] = true
+	}
+
+	for _, address := range oldAddresses {
+		if current[address] {
+			continue
+		}
+		req, reverseName, err := requestForAddress(address)
+		if err != nil {
+			return err
+		}
+		req.Deletions = append(req.Deletions, &dns.RecordSet{
+			Name: reverseName,
+			Type: "PTR",
+			Data: []string{target},
+		})
+	}
+
+	for _, address := range newAddresses {
+		req, reverseName, err := requestForAddress(address)
+		if err != nil {
+			return err
+		}
+		req.Replacements = append(req.Replacements, &dns.RecordSet{
+			Name: reverseName,
+			Type: "PTR",
+			Ttl:  ttl,
+			Data: []string{target},
+		})
+	}
+
+	for _, req := range requests {
+		log.Printf("[DEBUG] Syncing PTR records of DnsRecordSet %s in DnsZone %q", rsId(d), req.DnsZoneId)
+
+		op, err := sdk.WrapOperation(sdk.DNS().DnsZone().UpsertRecordSets(ctx, req))
+		if err != nil {
+			return fmt.Errorf("Error while requesting API to sync PTR records in DnsZone %q: %s", req.DnsZoneId, err)
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			return fmt.Errorf("Error while waiting operation to sync PTR records in DnsZone %q: %s", req.DnsZoneId, err)
+		}
+	}
+
+	return nil
+}
+
+func listDnsZones(ctx context.Context, config *Config, folderID string) ([]*dns.DnsZone, error) {
+	var zones []*dns.DnsZone
+	pageToken := ""
+	for {
+		resp, err := getSDK(config).DNS().DnsZone().List(ctx, &dns.ListDnsZonesRequest{
+			FolderId:  folderID,
+			PageSize:  defaultListSize,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error while listing DnsZones in folder %q: %s", folderID, err)
+		}
+		zones = append(zones, resp.DnsZones...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+	return zones, nil
+}
+
+// dnsReverseName returns the in-addr.arpa or ip6.arpa name of the address.
+func dnsReverseName(address string) (string, error) {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return "", fmt.Errorf("invalid IP address %q", address)
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		return fmt.Sprintf("%d.%d.%d.%d.in-addr.arpa.", ip4[3], ip4[2], ip4[1], ip4[0]), nil
+	}
+
+	var buf strings.Builder
+	for i := len(ip) - 1; i >= 0; i-- {
+		fmt.Fprintf(&buf, "%x.%x.", ip[i]&0x0f, ip[i]>>4)
+	}
+	buf.WriteString("ip6.arpa.")
+	return buf.String(), nil
+}
+
+// dnsRecordSetFQDN returns the fully qualified name of the record set in the zone.
+func dnsRecordSetFQDN(name, zone string) string {
+	switch {
+	case name == "@":
+		return zone
+	case strings.HasSuffix(name, "."):
+		return name
+	default:
+		return name + "." + zone
+	}
+}
+
+// findDnsReverseZone returns the most specific zone containing the reverse name.
+func findDnsReverseZone(zones []*dns.DnsZone, reverseName string) *dns.DnsZone {
+	var result *dns.DnsZone
+	for _, zone := range zones {
+		if reverseName != zone.Zone && !strings.HasSuffix(reverseName, "."+zone.Zone) {
+			continue
+		}
+		if result == nil || len(zone.Zone) > len(result.Zone) {
+			result = zone
+		}
+	}
+	return result
+}
+
 func resourceDnsRecordSetImportState(d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
 	parts := strings.Split(d.Id(), "/")
 	if len(parts) == 3 {
@@ -247,6 +460,9 @@ func resourceDnsRecordSetImportState(d *schema.ResourceData, _ interface{}) ([]*
 		if err := d.Set("type", parts[2]); err != nil {
 			return nil, fmt.Errorf("Error setting type: %s", err)
 		}
+		if err := d.Set("sync_ptr", false); err != nil {
+			return nil, fmt.Errorf("Error setting sync_ptr: %s", err)
+		}
 	} else {
 		return nil, fmt.Errorf("Invalid dns recordset specifier. Expecting {zone-id}/{record-name}/{record-type}.")
 	}
@@ -7,8 +7,10 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/dns/v1"
+	"google.golang.org/grpc/codes"
 )
 
 func TestIpv6AddressDiffSuppress(t *testing.T) {
@@ -41,6 +43,108 @@ func TestIpv6AddressDiffSuppress(t *testing.T) {
 	}
 }
 
+func TestDnsReverseName(t *testing.T) {
+	cases := map[string]string{
+		"192.168.0.1":         "1.0.168.192.in-addr.arpa.",
+		"10.1.2.3":            "3.2.1.10.in-addr.arpa.",
+		"2001:db8::567:89ab":  "b.a.9.8.7.6.5.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa.",
+		"fd12:3456:789a:1::1": "1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.1.0.0.0.a.9.8.7.6.5.4.3.2.1.d.f.ip6.arpa.",
+	}
+
+	for address, expected := range cases {
+		actual, err := dnsReverseName(address)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", address, err)
+			continue
+		}
+		if actual != expected {
+			t.Errorf("%s: expected %s, got %s", address, expected, actual)
+		}
+	}
+
+	if _, err := dnsReverseName("not-an-ip"); err == nil {
+		t.Errorf("expected error for invalid address")
+	}
+}
+
+func TestDnsRecordSetFQDN(t *testing.T) {
+	cases := []struct {
+		name, zone, expected string
+	}{
+		{"srv", "example.com.", "srv.example.com."},
+		{"srv.example.com.", "example.com.", "srv.example.com."},
+		{"@", "example.com.", "example.com."},
+	}
+
+	for _, tc := range cases {
+		if actual := dnsRecordSetFQDN(tc.name, tc.zone); actual != tc.expected {
+			t.Errorf("%s in %s: expected %s, got %s", tc.name, tc.zone, tc.expected, actual)
+		}
+	}
+}
+
+func TestFindDnsReverseZone(t *testing.T) {
+	zones := []*dns.DnsZone{
+		{Id: "forward", Zone: "example.com."},
+		{Id: "wide", Zone: "168.192.in-addr.arpa."},
+		{Id: "narrow", Zone: "0.168.192.in-addr.arpa."},
+		{Id: "other", Zone: "10.192.in-addr.arpa."},
+	}
+
+	cases := map[string]string{
+		"1.0.168.192.in-addr.arpa.": "narrow",
+		"1.1.168.192.in-addr.arpa.": "wide",
+		"1.0.0.10.in-addr.arpa.":    "",
+	}
+
+	for reverseName, expected := range cases {
+		zone := findDnsReverseZone(zones, reverseName)
+		var actual string
+		if zone != nil {
+			actual = zone.Id
+		}
+		if actual != expected {
+			t.Errorf("%s: expected zone %q, got %q", reverseName, expected, actual)
+		}
+	}
+}
+
+func TestDnsRecordSetSyncPtrDiff(t *testing.T) {
+	cases := map[string]struct {
+		rsType  string
+		syncPtr bool
+		wantErr bool
+	}{
+		"A with sync_ptr":        {rsType: "A", syncPtr: true},
+		"AAAA with sync_ptr":     {rsType: "aaaa", syncPtr: true},
+		"CNAME without sync_ptr": {rsType: "CNAME"},
+		"CNAME with sync_ptr":    {rsType: "CNAME", syncPtr: true, wantErr: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := resourceYandexDnsRecordSet()
+			raw := map[string]interface{}{
+				"zone_id":  "zone",
+				"name":     "srv",
+				"type":     tc.rsType,
+				"ttl":      200,
+				"data":     []interface{}{"192.168.0.1"},
+				"sync_ptr": tc.syncPtr,
+			}
+			state := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{}).State()
+
+			_, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(raw), nil)
+			if tc.wantErr && err == nil {
+				t.Fatalf("expected sync_ptr error for %s record set", tc.rsType)
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
 func TestAccDNSRecordSet_basic(t *testing.T) {
 	t.Parallel()
 
@@ -199,6 +303,62 @@ func TestAccDNSRecordSet_update(t *testing.T) {
 	})
 }
 
+func TestAccDNSRecordSet_syncPtr(t *testing.T) {
+	t.Parallel()
+
+	var rs dns.RecordSet
+	zoneName := acctest.RandomWithPrefix("tf-dns-zone")
+	fqdn := acctest.RandomWithPrefix("tf-test") + ".dnstest.test."
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVPCAddressDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDNSRecordSetSyncPtr(zoneName, fqdn, "192.168.0.1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDNSRecordSetExists("yandex_dns_recordset.rs1", &rs),
+					resource.TestCheckResourceAttr("yandex_dns_recordset.rs1", "sync_ptr", "true"),
+					testAccCheckDNSRecordSetPtr("yandex_dns_zone.reverse", "1.0.168.192.in-addr.arpa.", "srv."+fqdn, true),
+				),
+			},
+			{
+				Config: testAccDNSRecordSetSyncPtr(zoneName, fqdn, "192.168.0.2"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDNSRecordSetPtr("yandex_dns_zone.reverse", "1.0.168.192.in-addr.arpa.", "srv."+fqdn, false),
+					testAccCheckDNSRecordSetPtr("yandex_dns_zone.reverse", "2.0.168.192.in-addr.arpa.", "srv."+fqdn, true),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDNSRecordSetPtr(zoneResource, reverseName, target string, isPresent bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		zone, ok := s.RootModule().Resources[zoneResource]
+		if !ok {
+			return fmt.Errorf("not found: %s", zoneResource)
+		}
+
+		sdk := getSDK(testAccProvider.Meta().(*Config))
+
+		found, err := sdk.DNS().DnsZone().GetRecordSet(context.Background(), &dns.GetDnsZoneRecordSetRequest{
+			DnsZoneId: zone.Primary.ID,
+			Name:      reverseName,
+			Type:      "PTR",
+		})
+		if err != nil {
+			if isStatusWithCode(err, codes.NotFound) && !isPresent {
+				return nil
+			}
+			return err
+		}
+
+		return testAccCheckDnsRecordsetData(found, target, isPresent)(s)
+	}
+}
+
 func testAccCheckDNSRecordSetExists(name string, rst *dns.RecordSet) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[name]
@@ -343,3 +503,30 @@ resource "yandex_dns_recordset" "rs2" {
 }
 `, name, fqdn)
 }
+
+func testAccDNSRecordSetSyncPtr(name, fqdn, address string) string {
+	return fmt.Sprintf(`
+resource "yandex_dns_zone" "zone1" {
+  name        = "%[1]s"
+  description = "desc"
+  zone        = "%[2]s"
+}
+
+resource "yandex_dns_zone" "reverse" {
+  name        = "%[1]s-reverse"
+  description = "desc"
+  zone        = "0.168.192.in-addr.arpa."
+}
+
+resource "yandex_dns_recordset" "rs1" {
+  zone_id  = yandex_dns_zone.zone1.id
+  name     = "srv.%[2]s"
+  type     = "A"
+  ttl      = 200
+  data     = ["%[3]s"]
+  sync_ptr = true
+
+  depends_on = [yandex_dns_zone.reverse]
+}
+`, name, fqdn, address)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/dns/v1"
 	"github.com/yandex-cloud/go-sdk/operation"
+	"google.golang.org/genproto/protobuf/field_mask"
 	"google.golang.org/grpc/status"
 )
 
@@ -203,25 +204,37 @@ func resourceYandexDnsZoneDelete(d *schema.ResourceData, meta interface{}) error
 }
 
 func prepareDnsZoneUpdateRequest(d *schema.ResourceData) (*dns.UpdateDnsZoneRequest, error) {
-	labels, err := expandLabels(d.Get("labels"))
-	if err != nil {
-		return nil, fmt.Errorf("Error expanding labels while creating instance: %s", err)
+	req := &dns.UpdateDnsZoneRequest{
+		DnsZoneId:  d.Id(),
+		UpdateMask: &field_mask.FieldMask{},
 	}
 
-	req := &dns.UpdateDnsZoneRequest{
-		DnsZoneId:         d.Id(),
-		Name:              d.Get("name").(string),
-		Description:       d.Get("description").(string),
-		Labels:            labels,
-		PrivateVisibility: &dns.PrivateVisibility{},
+	if d.HasChange("labels") {
+		labels, err := expandLabels(d.Get("labels"))
+		if err != nil {
+			return nil, fmt.Errorf("Error expanding labels while updating DnsZone: %s", err)
+		}
+
+		req.Labels = labels
+		req.UpdateMask.Paths = append(req.UpdateMask.Paths, "labels")
 	}
 
-	if d.Get("public").(bool) {
-		req.PublicVisibility = &dns.PublicVisibility{}
+	if d.HasChange("name") {
+		req.Name = d.Get("name").(string)
+		req.UpdateMask.Paths = append(req.UpdateMask.Paths, "name")
 	}
 
-	if n, ok := d.GetOk("private_networks"); ok {
-		req.PrivateVisibility.NetworkIds = convertStringSet(n.(*schema.Set))
+	if d.HasChange("description") {
+		req.Description = d.Get("description").(string)
+		req.UpdateMask.Paths = append(req.UpdateMask.Paths, "description")
+	}
+
+	if d.HasChange("private_networks") {
+		req.PrivateVisibility = &dns.PrivateVisibility{}
+		if n, ok := d.GetOk("private_networks"); ok {
+			req.PrivateVisibility.NetworkIds = convertStringSet(n.(*schema.Set))
+		}
+		req.UpdateMask.Paths = append(req.UpdateMask.Paths, "private_visibility")
 	}
 
 	return req, nil
@@ -3,15 +3,54 @@ package yandex
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/dns/v1"
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/vpc/v1"
 )
 
+func TestPrepareDnsZoneUpdateRequest(t *testing.T) {
+	schemaObject := resourceYandexDnsZone().Schema
+	initial := schema.TestResourceDataRaw(t, schemaObject, map[string]interface{}{
+		"name":        "zone",
+		"description": "desc",
+		"zone":        "example.com.",
+		"labels": map[string]interface{}{
+			"tf-label": "tf-label-value",
+		},
+	})
+	initial.SetId("zone-id")
+
+	d, err := schema.InternalMap(schemaObject).Data(initial.State(), &terraform.InstanceDiff{
+		Attributes: map[string]*terraform.ResourceAttrDiff{
+			"labels.tf-label": {Old: "tf-label-value", New: "tf-label-value1"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	req, err := prepareDnsZoneUpdateRequest(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if !reflect.DeepEqual(req.UpdateMask.Paths, []string{"labels"}) {
+		t.Fatalf("unexpected update mask paths: %v", req.UpdateMask.Paths)
+	}
+	if req.Labels["tf-label"] != "tf-label-value1" {
+		t.Fatalf("unexpected labels: %v", req.Labels)
+	}
+	if req.PrivateVisibility != nil {
+		t.Fatalf("private visibility should not be sent when private_networks are unchanged")
+	}
+}
+
 func TestAccDNSZone_basic(t *testing.T) {
 	t.Parallel()
 
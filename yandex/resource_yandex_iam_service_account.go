@@ -43,6 +43,13 @@ func resourceYandexIAMServiceAccount() *schema.Resource {
 				Optional: true,
 			},
 
+			"labels": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+
 			"folder_id": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -74,10 +81,16 @@ func resourceYandexIAMServiceAccountCreate(d *schema.ResourceData, meta interfac
 	}
 	defer unlock()
 
+	labels, err := expandLabels(d.Get("labels"))
+	if err != nil {
+		return fmt.Errorf("Error expanding labels while creating service account: %s", err)
+	}
+
 	req := iam.CreateServiceAccountRequest{
 		FolderId:    folderID,
 		Name:        d.Get("name").(string),
 		Description: d.Get("description").(string),
+		Labels:      labels,
 	}
 
 	ctx, cancel := context.WithTimeout(config.Context(), d.Timeout(schema.TimeoutCreate))
@@ -128,7 +141,7 @@ func resourceYandexIAMServiceAccountRead(d *schema.ResourceData, meta interface{
 	d.Set("folder_id", sa.FolderId)
 	d.Set("description", sa.Description)
 
-	return nil
+	return d.Set("labels", sa.Labels)
 }
 
 func resourceYandexIAMServiceAccountUpdate(d *schema.ResourceData, meta interface{}) error {
@@ -151,6 +164,16 @@ func resourceYandexIAMServiceAccountUpdate(d *schema.ResourceData, meta interfac
 		req.UpdateMask.Paths = append(req.UpdateMask.Paths, "description")
 	}
 
+	if d.HasChange("labels") {
+		labels, err := expandLabels(d.Get("labels"))
+		if err != nil {
+			return fmt.Errorf("Error expanding labels while updating Service Account %q: %s", d.Id(), err)
+		}
+
+		req.Labels = labels
+		req.UpdateMask.Paths = append(req.UpdateMask.Paths, "labels")
+	}
+
 	ctx, cancel := context.WithTimeout(config.Context(), d.Timeout(schema.TimeoutUpdate))
 	defer cancel()
 
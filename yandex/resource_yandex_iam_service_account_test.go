@@ -196,6 +196,58 @@ func TestAccServiceAccount_basic(t *testing.T) {
 	})
 }
 
+// Test that description, labels and name of a service account are updated without recreation
+func TestAccServiceAccount_updateInPlace(t *testing.T) {
+	t.Parallel()
+
+	accountName := "a" + acctest.RandString(10)
+	accountName2 := "a" + acctest.RandString(10)
+	uniqueID := ""
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckIAMServiceAccountDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccServiceAccountWithLabels(accountName, "Terraform Test", "one"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckYandexIAMServiceAccountExists("yandex_iam_service_account.acceptance"),
+					resource.TestCheckResourceAttr(
+						"yandex_iam_service_account.acceptance", "labels.%", "1"),
+					resource.TestCheckResourceAttr(
+						"yandex_iam_service_account.acceptance", "labels.key", "one"),
+					testAccStoreServiceAccountUniqueID(&uniqueID),
+				),
+			},
+			{
+				Config: testAccServiceAccountWithLabels(accountName, "Terraform Test Update", "one"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"yandex_iam_service_account.acceptance", "description", "Terraform Test Update"),
+					resource.TestCheckResourceAttrPtr(
+						"yandex_iam_service_account.acceptance", "id", &uniqueID),
+				),
+			},
+			{
+				Config: testAccServiceAccountWithLabels(accountName2, "Terraform Test Update", "two"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"yandex_iam_service_account.acceptance", "name", accountName2),
+					resource.TestCheckResourceAttr(
+						"yandex_iam_service_account.acceptance", "labels.key", "two"),
+					resource.TestCheckResourceAttrPtr(
+						"yandex_iam_service_account.acceptance", "id", &uniqueID),
+				),
+			},
+			{
+				ResourceName:      "yandex_iam_service_account.acceptance",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func testAccStoreServiceAccountUniqueID(uniqueID *string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		*uniqueID = s.RootModule().Resources["yandex_iam_service_account.acceptance"].Primary.ID
@@ -300,3 +352,16 @@ resource "yandex_iam_service_account" "acceptance" {
 }
 `, folderID, name, desc)
 }
+
+func testAccServiceAccountWithLabels(name, desc, label string) string {
+	return fmt.Sprintf(`
+resource "yandex_iam_service_account" "acceptance" {
+  name        = "%v"
+  description = "%v"
+
+  labels = {
+    key = "%v"
+  }
+}
+`, name, desc, label)
+}
@@ -4,16 +4,23 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
 
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/mdb/clickhouse/v1"
+	clickhouseConfig "github.com/yandex-cloud/go-genproto/yandex/cloud/mdb/clickhouse/v1/config"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/vpc/v1"
+	sdkoperation "github.com/yandex-cloud/go-sdk/operation"
 )
 
 const (
@@ -29,9 +36,10 @@ var schemaResources = map[string]*schema.Schema{
 		Computed: true,
 	},
 	"disk_size": {
-		Type:     schema.TypeInt,
-		Optional: true,
-		Computed: true,
+		Type:         schema.TypeInt,
+		Optional:     true,
+		Computed:     true,
+		ValidateFunc: validation.IntAtLeast(1),
 	},
 	"disk_type_id": {
 		Type:     schema.TypeString,
@@ -39,6 +47,18 @@ var schemaResources = map[string]*schema.Schema{
 		Computed: true,
 	},
 }
+var schemaShardConfig = map[string]*schema.Schema{
+	"max_connections":               {Type: schema.TypeInt, Optional: true},
+	"max_concurrent_queries":        {Type: schema.TypeInt, Optional: true},
+	"keep_alive_timeout":            {Type: schema.TypeInt, Optional: true},
+	"uncompressed_cache_size":       {Type: schema.TypeInt, Optional: true},
+	"mark_cache_size":               {Type: schema.TypeInt, Optional: true},
+	"max_table_size_to_drop":        {Type: schema.TypeInt, Optional: true},
+	"max_partition_size_to_drop":    {Type: schema.TypeInt, Optional: true},
+	"background_pool_size":          {Type: schema.TypeInt, Optional: true},
+	"background_schedule_pool_size": {Type: schema.TypeInt, Optional: true},
+	"background_fetches_pool_size":  {Type: schema.TypeInt, Optional: true},
+}
 var schemaConfig = map[string]*schema.Schema{
 	"log_level":                       {Type: schema.TypeString, Optional: true, Computed: true},
 	"max_connections":                 {Type: schema.TypeInt, Optional: true, Computed: true},
@@ -72,6 +92,12 @@ var schemaConfig = map[string]*schema.Schema{
 	"background_fetches_pool_size":    {Type: schema.TypeInt, Optional: true, Computed: true},
 	"default_database":                {Type: schema.TypeString, Optional: true, Computed: true},
 	"total_memory_profiler_step":      {Type: schema.TypeInt, Optional: true, Computed: true},
+	"total_memory_tracker_sample_probability": {
+		Type:         schema.TypeFloat,
+		Optional:     true,
+		Computed:     true,
+		ValidateFunc: validation.FloatBetween(0, 1),
+	},
 	"merge_tree": {
 		Type:     schema.TypeList,
 		MaxItems: 1,
@@ -89,6 +115,16 @@ var schemaConfig = map[string]*schema.Schema{
 				"min_bytes_for_wide_part":                                   {Type: schema.TypeInt, Optional: true, Computed: true},
 				"min_rows_for_wide_part":                                    {Type: schema.TypeInt, Optional: true, Computed: true},
 				"ttl_only_drop_parts":                                       {Type: schema.TypeBool, Optional: true, Computed: true},
+				"inactive_parts_to_delay_insert":                            {Type: schema.TypeInt, Optional: true, Computed: true},
+				"inactive_parts_to_throw_insert":                            {Type: schema.TypeInt, Optional: true, Computed: true},
+				"max_bytes_to_merge_at_max_space_in_pool":                   {Type: schema.TypeInt, Optional: true, Computed: true},
+				"allow_remote_fs_zero_copy_replication":                     {Type: schema.TypeBool, Optional: true, Computed: true},
+				"merge_with_ttl_timeout":                                    {Type: schema.TypeInt, Optional: true, Computed: true},
+				"merge_with_recompression_ttl_timeout":                      {Type: schema.TypeInt, Optional: true, Computed: true},
+				"max_parts_in_total":                                        {Type: schema.TypeInt, Optional: true, Computed: true},
+				"max_number_of_merges_with_ttl_in_pool":                     {Type: schema.TypeInt, Optional: true, Computed: true},
+				"cleanup_delay_period":                                      {Type: schema.TypeInt, Optional: true, Computed: true},
+				"number_of_free_entries_in_pool_to_execute_mutation":        {Type: schema.TypeInt, Optional: true, Computed: true},
 			},
 		},
 	},
@@ -99,8 +135,8 @@ var schemaConfig = map[string]*schema.Schema{
 		Computed: true,
 		Elem: &schema.Resource{
 			Schema: map[string]*schema.Schema{
-				"security_protocol": {Type: schema.TypeString, Optional: true, Computed: true},
-				"sasl_mechanism":    {Type: schema.TypeString, Optional: true, Computed: true},
+				"security_protocol": {Type: schema.TypeString, Optional: true, Computed: true, ValidateFunc: validateParsableValue(parseClickHouseKafkaSecurityProtocol)},
+				"sasl_mechanism":    {Type: schema.TypeString, Optional: true, Computed: true, ValidateFunc: validateParsableValue(parseClickHouseKafkaSaslMechanism)},
 				"sasl_username":     {Type: schema.TypeString, Optional: true, Computed: true},
 				"sasl_password":     {Type: schema.TypeString, Optional: true, Sensitive: true, Computed: true},
 			},
@@ -120,8 +156,8 @@ var schemaConfig = map[string]*schema.Schema{
 					Optional: true,
 					Elem: &schema.Resource{
 						Schema: map[string]*schema.Schema{
-							"security_protocol": {Type: schema.TypeString, Optional: true},
-							"sasl_mechanism":    {Type: schema.TypeString, Optional: true},
+							"security_protocol": {Type: schema.TypeString, Optional: true, ValidateFunc: validateParsableValue(parseClickHouseKafkaSecurityProtocol)},
+							"sasl_mechanism":    {Type: schema.TypeString, Optional: true, ValidateFunc: validateParsableValue(parseClickHouseKafkaSaslMechanism)},
 							"sasl_username":     {Type: schema.TypeString, Optional: true},
 							"sasl_password":     {Type: schema.TypeString, Optional: true, Sensitive: true},
 						},
@@ -144,12 +180,13 @@ var schemaConfig = map[string]*schema.Schema{
 		},
 	},
 	"compression": {
-		Type:     schema.TypeList,
+		Type:     schema.TypeSet,
 		MinItems: 0,
 		Optional: true,
+		Set:      clickHouseCompressionHash,
 		Elem: &schema.Resource{
 			Schema: map[string]*schema.Schema{
-				"method":              {Type: schema.TypeString, Required: true},
+				"method":              {Type: schema.TypeString, Required: true, ValidateFunc: validateParsableValue(parseClickHouseCompressionMethod)},
 				"min_part_size":       {Type: schema.TypeInt, Required: true},
 				"min_part_size_ratio": {Type: schema.TypeFloat, Required: true},
 			},
@@ -196,7 +233,7 @@ func resourceYandexMDBClickHouseCluster() *schema.Resource {
 		Update: resourceYandexMDBClickHouseClusterUpdate,
 		Delete: resourceYandexMDBClickHouseClusterDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceYandexMDBClickHouseClusterImportState,
 		},
 
 		Timeouts: &schema.ResourceTimeout{
@@ -207,6 +244,17 @@ func resourceYandexMDBClickHouseCluster() *schema.Resource {
 
 		SchemaVersion: 0,
 
+		CustomizeDiff: customdiff.All(
+			clickHouseDiskTypeDiffCustomize,
+			clickHouseEnvironmentDiffCustomize,
+			clickHouseSqlManagementDiffCustomize,
+			clickHouseServiceAccountDiffCustomize,
+			clickHouseHostSubnetsDiffCustomize(getClickHouseSubnetNetworkID),
+			clickHouseZooKeeperHostsDiffCustomize,
+			clickHouseCloudStorageDiffCustomize,
+			clickHouseRescheduleMaintenanceDiffCustomize,
+		),
+
 		Schema: map[string]*schema.Schema{
 			"cluster_id": {
 				Type:     schema.TypeString,
@@ -451,6 +499,14 @@ func resourceYandexMDBClickHouseCluster() *schema.Resource {
 								Schema: schemaResources,
 							},
 						},
+						"config": {
+							Type:     schema.TypeList,
+							MaxItems: 1,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: schemaShardConfig,
+							},
+						},
 					},
 				},
 			},
@@ -507,6 +563,10 @@ func resourceYandexMDBClickHouseCluster() *schema.Resource {
 							Type:     schema.TypeString,
 							Computed: true,
 						},
+						"health": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
 					},
 				},
 			},
@@ -694,7 +754,6 @@ func resourceYandexMDBClickHouseCluster() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 				Optional: true,
-				ForceNew: true,
 			},
 			"created_at": {
 				Type:     schema.TypeString,
@@ -709,8 +768,11 @@ func resourceYandexMDBClickHouseCluster() *schema.Resource {
 				Computed: true,
 			},
 			"security_group_ids": {
-				Type:     schema.TypeSet,
-				Elem:     &schema.Schema{Type: schema.TypeString},
+				Type: schema.TypeSet,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
 				Set:      schema.HashString,
 				Optional: true,
 				Computed: true,
@@ -755,9 +817,10 @@ func resourceYandexMDBClickHouseCluster() *schema.Resource {
 							Required: true,
 						},
 						"move_factor": {
-							Type:     schema.TypeFloat,
-							Optional: true,
-							Computed: true,
+							Type:         schema.TypeFloat,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validation.FloatBetween(0, 1),
 						},
 						"data_cache_enabled": {
 							Type:     schema.TypeBool,
@@ -765,9 +828,10 @@ func resourceYandexMDBClickHouseCluster() *schema.Resource {
 							Computed: true,
 						},
 						"data_cache_max_size": {
-							Type:     schema.TypeInt,
-							Optional: true,
-							Computed: true,
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validation.IntAtLeast(0),
 						},
 					},
 				},
@@ -797,11 +861,89 @@ func resourceYandexMDBClickHouseCluster() *schema.Resource {
 					},
 				},
 			},
+			"reschedule_maintenance": {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"reschedule_type": {
+							Type:         schema.TypeString,
+							ValidateFunc: validateParsableValue(parseClickHouseRescheduleType),
+							Required:     true,
+						},
+						"delayed_until": {
+							Type:         schema.TypeString,
+							ValidateFunc: validation.IsRFC3339Time,
+							Optional:     true,
+						},
+					},
+				},
+			},
+			"shard_hosts": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"shard_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"fqdns": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			"keeper_hosts": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"planned_operation": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"info": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"delayed_until": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
 			"deletion_protection": {
 				Type:     schema.TypeBool,
 				Optional: true,
 				Computed: true,
 			},
+			"force_destroy": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"restore": {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"backup_id": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ForceNew:     true,
+							ValidateFunc: validation.StringIsNotEmpty,
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -820,9 +962,19 @@ func resourceYandexMDBClickHouseClusterCreate(d *schema.ResourceData, meta inter
 	ctx, cancel := config.ContextWithTimeout(d.Timeout(schema.TimeoutCreate))
 	defer cancel()
 
-	op, err := config.sdk.WrapOperation(config.sdk.MDB().Clickhouse().Cluster().Create(ctx, req))
-	if err != nil {
-		return fmt.Errorf("error while requesting API to create ClickHouse Cluster: %s", err)
+	backupID, restore := d.GetOk("restore.0.backup_id")
+
+	var op *sdkoperation.Operation
+	if restore {
+		op, err = config.sdk.WrapOperation(config.sdk.MDB().Clickhouse().Cluster().Restore(ctx, prepareRestoreClickHouseClusterRequest(req, backupID.(string))))
+		if err != nil {
+			return fmt.Errorf("error while requesting API to restore ClickHouse Cluster from backup %q: %s", backupID, err)
+		}
+	} else {
+		op, err = config.sdk.WrapOperation(config.sdk.MDB().Clickhouse().Cluster().Create(ctx, req))
+		if err != nil {
+			return fmt.Errorf("error while requesting API to create ClickHouse Cluster: %s", err)
+		}
 	}
 
 	protoMetadata, err := op.Metadata()
@@ -830,13 +982,15 @@ func resourceYandexMDBClickHouseClusterCreate(d *schema.ResourceData, meta inter
 		return fmt.Errorf("error while getting ClickHouse create operation metadata: %s", err)
 	}
 
-	md, ok := protoMetadata.(*clickhouse.CreateClusterMetadata)
-	if !ok {
+	switch md := protoMetadata.(type) {
+	case *clickhouse.CreateClusterMetadata:
+		d.SetId(md.ClusterId)
+	case *clickhouse.RestoreClusterMetadata:
+		d.SetId(md.ClusterId)
+	default:
 		return fmt.Errorf("could not get Cluster ID from create operation metadata")
 	}
 
-	d.SetId(md.ClusterId)
-
 	err = op.Wait(ctx)
 	if err != nil {
 		return fmt.Errorf("error while waiting for operation to create ClickHouse Cluster: %s", err)
@@ -846,6 +1000,12 @@ func resourceYandexMDBClickHouseClusterCreate(d *schema.ResourceData, meta inter
 		return fmt.Errorf("ClickHouse Cluster creation failed: %s", err)
 	}
 
+	if restore {
+		if err := applyClickHouseClusterRestoreSpec(ctx, config, d, req); err != nil {
+			return err
+		}
+	}
+
 	for shardName, shardHosts := range shardsToAdd {
 		var shardSpec *clickhouse.ShardConfigSpec
 		if v, ok := shardsFromSpec[shardName]; ok {
@@ -864,7 +1024,7 @@ func resourceYandexMDBClickHouseClusterCreate(d *schema.ResourceData, meta inter
 			continue
 		}
 		log.Printf("[DEBUG] update exists shard=%s\n", shardNameFromSpec)
-		if err := updateClickHouseShard(ctx, config, d, shardNameFromSpec, shardConfigFromSpec); err != nil {
+		if err := updateClickHouseShard(ctx, config, d, shardNameFromSpec, shardConfigFromSpec, nil); err != nil {
 			return err
 		}
 	}
@@ -1022,6 +1182,88 @@ func prepareCreateClickHouseCreateRequest(d *schema.ResourceData, meta *Config)
 	return &req, toAdd, shardsFromSpec, nil
 }
 
+// prepareRestoreClickHouseClusterRequest builds restore request from the create one.
+// Databases, users, deletion protection and maintenance window are not part of the restore request
+// and are applied by applyClickHouseClusterRestoreSpec after the cluster is restored.
+func prepareRestoreClickHouseClusterRequest(req *clickhouse.CreateClusterRequest, backupID string) *clickhouse.RestoreClusterRequest {
+	return &clickhouse.RestoreClusterRequest{
+		BackupId:         backupID,
+		Name:             req.Name,
+		Description:      req.Description,
+		Labels:           req.Labels,
+		Environment:      req.Environment,
+		ConfigSpec:       req.ConfigSpec,
+		HostSpecs:        req.HostSpecs,
+		NetworkId:        req.NetworkId,
+		FolderId:         req.FolderId,
+		ServiceAccountId: req.ServiceAccountId,
+		SecurityGroupIds: req.SecurityGroupIds,
+	}
+}
+
+func applyClickHouseClusterRestoreSpec(ctx context.Context, config *Config, d *schema.ResourceData, req *clickhouse.CreateClusterRequest) error {
+	databases, err := listClickHouseDatabases(ctx, config, d.Id())
+	if err != nil {
+		return err
+	}
+	existingDatabases := map[string]bool{}
+	for _, db := range databases {
+		existingDatabases[db.Name] = true
+	}
+	for _, db := range req.DatabaseSpecs {
+		if existingDatabases[db.Name] {
+			continue
+		}
+		if err := createClickHouseDatabase(ctx, config, d, db.Name); err != nil {
+			return err
+		}
+	}
+
+	users, err := listClickHouseUsers(ctx, config, d.Id())
+	if err != nil {
+		return err
+	}
+	existingUsers := map[string]bool{}
+	for _, u := range users {
+		existingUsers[u.Name] = true
+	}
+	for _, u := range req.UserSpecs {
+		if existingUsers[u.Name] {
+			continue
+		}
+		if err := createClickHouseUser(ctx, config, d, u); err != nil {
+			return err
+		}
+	}
+
+	updateReq := &clickhouse.UpdateClusterRequest{
+		ClusterId:          d.Id(),
+		DeletionProtection: req.DeletionProtection,
+		MaintenanceWindow:  req.MaintenanceWindow,
+		UpdateMask:         &field_mask.FieldMask{},
+	}
+	if req.DeletionProtection {
+		updateReq.UpdateMask.Paths = append(updateReq.UpdateMask.Paths, "deletion_protection")
+	}
+	if req.MaintenanceWindow != nil {
+		updateReq.UpdateMask.Paths = append(updateReq.UpdateMask.Paths, "maintenance_window")
+	}
+	if len(updateReq.UpdateMask.Paths) == 0 {
+		return nil
+	}
+
+	op, err := config.sdk.WrapOperation(config.sdk.MDB().Clickhouse().Cluster().Update(ctx, updateReq))
+	if err != nil {
+		return fmt.Errorf("error while requesting API to update ClickHouse Cluster %q after restore: %s", d.Id(), err)
+	}
+
+	err = op.Wait(ctx)
+	if err != nil {
+		return fmt.Errorf("error while updating ClickHouse Cluster %q after restore: %s", d.Id(), err)
+	}
+	return nil
+}
+
 func resourceYandexMDBClickHouseClusterRead(d *schema.ResourceData, meta interface{}) error {
 	log.Println("[DEBUG] cluster read started")
 	config := meta.(*Config)
@@ -1069,7 +1311,7 @@ func resourceYandexMDBClickHouseClusterRead(d *schema.ResourceData, meta interfa
 		return err
 	}
 
-	bws := flattenClickHouseBackupWindowStart(cluster.Config.BackupWindowStart)
+	bws := flattenClickHouseBackupWindowStart(cluster.GetConfig().GetBackupWindowStart())
 	if err := d.Set("backup_window_start", bws); err != nil {
 		return err
 	}
@@ -1084,6 +1326,10 @@ func resourceYandexMDBClickHouseClusterRead(d *schema.ResourceData, meta interfa
 		return err
 	}
 
+	if err := d.Set("planned_operation", flattenClickHousePlannedOperation(cluster.GetPlannedOperation())); err != nil {
+		return err
+	}
+
 	hosts, err := listClickHouseHosts(ctx, config, d.Id())
 	if err != nil {
 		return err
@@ -1104,7 +1350,15 @@ func resourceYandexMDBClickHouseClusterRead(d *schema.ResourceData, meta interfa
 		return err
 	}
 
-	if err := setShardsToSchema(ctx, config, d); err != nil {
+	if err := d.Set("shard_hosts", flattenClickHouseShardHosts(hosts)); err != nil {
+		return err
+	}
+
+	if err := d.Set("keeper_hosts", flattenClickHouseKeeperHosts(hosts)); err != nil {
+		return err
+	}
+
+	if err := setShardsToSchema(ctx, config, d, cluster.GetConfig().GetClickhouse().GetConfig().GetEffectiveConfig()); err != nil {
 		return err
 	}
 
@@ -1194,8 +1448,20 @@ func resourceYandexMDBClickHouseClusterRead(d *schema.ResourceData, meta interfa
 		return err
 	}
 
+	configuredLabels, err := expandLabels(d.Get("labels"))
+	if err != nil {
+		return err
+	}
+
 	log.Printf("[DEBUG] cluster read finished: schema after read=%+v\n", d)
-	return d.Set("labels", cluster.Labels)
+	return d.Set("labels", flattenResourceLabels(cluster.Labels, configuredLabels))
+}
+
+func resourceYandexMDBClickHouseClusterImportState(d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	if err := d.Set("force_destroy", false); err != nil {
+		return nil, fmt.Errorf("Error setting force_destroy: %s", err)
+	}
+	return []*schema.ResourceData{d}, nil
 }
 
 func resourceYandexMDBClickHouseClusterUpdate(d *schema.ResourceData, meta interface{}) error {
@@ -1204,10 +1470,22 @@ func resourceYandexMDBClickHouseClusterUpdate(d *schema.ResourceData, meta inter
 
 	d.Partial(true)
 
+	if d.HasChange("folder_id") {
+		if err := updateClickHouseClusterFolder(d, meta); err != nil {
+			return err
+		}
+	}
+
 	if err := updateClickHouseClusterParams(d, meta); err != nil {
 		return err
 	}
 
+	if d.HasChange("reschedule_maintenance") {
+		if err := rescheduleClickHouseClusterMaintenance(d, meta); err != nil {
+			return err
+		}
+	}
+
 	if d.HasChange("database") {
 		if err := updateClickHouseClusterDatabases(d, meta); err != nil {
 			return err
@@ -1276,52 +1554,93 @@ var mdbClickHouseUpdateFieldsMap = map[string]string{
 	"deletion_protection":     "deletion_protection",
 }
 
-func updateClickHouseClusterParams(d *schema.ResourceData, meta interface{}) error {
+func updateClickHouseClusterFolder(d *schema.ResourceData, meta interface{}) error {
 	config := meta.(*Config)
 
-	if d.HasChanges("resources", "version") {
-		oldVersion, newVersion := d.GetChange("version")
-		log.Printf("[DEBUG] Pre-updating ClickHouse Cluster %q version %q -> %q", d.Id(), oldVersion, newVersion)
+	folderID := d.Get("folder_id").(string)
+	if folderID == "" {
+		return nil
+	}
 
-		req := &clickhouse.UpdateClusterRequest{
-			ClusterId: d.Id(),
-			ConfigSpec: &clickhouse.ConfigSpec{
-				Version: d.Get("version").(string),
-			},
-			UpdateMask: &field_mask.FieldMask{
-				Paths: []string{"config_spec.version"},
-			},
-		}
+	req := &clickhouse.MoveClusterRequest{
+		ClusterId:           d.Id(),
+		DestinationFolderId: folderID,
+	}
 
-		ctx, cancel := config.ContextWithTimeout(d.Timeout(schema.TimeoutUpdate))
-		defer cancel()
+	ctx, cancel := config.ContextWithTimeout(d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
 
-		op, err := config.sdk.WrapOperation(config.sdk.MDB().Clickhouse().Cluster().Update(ctx, req))
-		if err != nil {
-			return fmt.Errorf("error while requesting API to update ClickHouse Cluster version %q: %s", d.Id(), err)
-		}
+	log.Printf("[DEBUG] Sending ClickHouse Cluster move request: %+v", req)
+	op, err := config.sdk.WrapOperation(config.sdk.MDB().Clickhouse().Cluster().Move(ctx, req))
+	if err != nil {
+		return fmt.Errorf("error while requesting API to move ClickHouse Cluster %q to folder %q: %s", d.Id(), folderID, err)
+	}
 
-		err = op.Wait(ctx)
-		if err != nil {
-			return fmt.Errorf("error while updating ClickHouse Cluster version %q: %s", d.Id(), err)
-		}
+	err = op.Wait(ctx)
+	if err != nil {
+		return fmt.Errorf("error while moving ClickHouse Cluster %q to folder %q: %s", d.Id(), folderID, err)
+	}
+
+	if _, err := op.Response(); err != nil {
+		return fmt.Errorf("moving ClickHouse Cluster %q to folder %q failed: %s", d.Id(), folderID, err)
+	}
+
+	return nil
+}
+
+func rescheduleClickHouseClusterMaintenance(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	req, err := expandClickHouseRescheduleMaintenance(d)
+	if err != nil {
+		return fmt.Errorf("error while expand clickhouse reschedule_maintenance: %s", err)
+	}
+	if req == nil {
+		return nil
+	}
+
+	ctx, cancel := config.ContextWithTimeout(d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
+
+	cluster, err := config.sdk.MDB().Clickhouse().Cluster().Get(ctx, &clickhouse.GetClusterRequest{
+		ClusterId: d.Id(),
+	})
+	if err != nil {
+		return fmt.Errorf("error while getting ClickHouse Cluster %q: %s", d.Id(), err)
+	}
+	if cluster.GetPlannedOperation() == nil {
+		log.Printf("[DEBUG] ClickHouse Cluster %q has no planned maintenance, nothing to reschedule", d.Id())
+		return nil
 	}
 
+	log.Printf("[DEBUG] Sending ClickHouse Cluster reschedule maintenance request: %+v", req)
+	op, err := config.sdk.WrapOperation(config.sdk.MDB().Clickhouse().Cluster().RescheduleMaintenance(ctx, req))
+	if err != nil {
+		return fmt.Errorf("error while requesting API to reschedule maintenance of ClickHouse Cluster %q: %s", d.Id(), err)
+	}
+
+	err = op.Wait(ctx)
+	if err != nil {
+		return fmt.Errorf("error while rescheduling maintenance of ClickHouse Cluster %q: %s", d.Id(), err)
+	}
+
+	if _, err := op.Response(); err != nil {
+		return fmt.Errorf("rescheduling maintenance of ClickHouse Cluster %q failed: %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+func updateClickHouseClusterParams(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
 	req, err := getClickHouseClusterUpdateRequest(d)
 	if err != nil {
 		return err
 	}
 
 	onDone := []func(){}
-	updatePath := []string{}
-	for field, path := range mdbClickHouseUpdateFieldsMap {
-		if d.HasChange(field) {
-			updatePath = append(updatePath, path)
-			onDone = append(onDone, func() {
-
-			})
-		}
-	}
+	updatePath := getClickHouseClusterUpdatePaths(d)
 
 	// We only can apply this if ZK subcluster already exists
 	if d.HasChange("zookeeper") {
@@ -1352,6 +1671,22 @@ func updateClickHouseClusterParams(d *schema.ResourceData, meta interface{}) err
 	ctx, cancel := config.ContextWithTimeout(d.Timeout(schema.TimeoutUpdate))
 	defer cancel()
 
+	if d.HasChange("labels") {
+		cluster, err := config.sdk.MDB().Clickhouse().Cluster().Get(ctx, &clickhouse.GetClusterRequest{
+			ClusterId: d.Id(),
+		})
+		if err != nil {
+			return fmt.Errorf("error while getting ClickHouse Cluster %q: %s", d.Id(), err)
+		}
+
+		oldLabelsProp, _ := d.GetChange("labels")
+		oldLabels, err := expandLabels(oldLabelsProp)
+		if err != nil {
+			return fmt.Errorf("error expanding labels while updating ClickHouse cluster: %s", err)
+		}
+		req.Labels = mergeSystemLabels(req.Labels, oldLabels, cluster.Labels)
+	}
+
 	op, err := config.sdk.WrapOperation(config.sdk.MDB().Clickhouse().Cluster().Update(ctx, req))
 	if err != nil {
 		return fmt.Errorf("error while requesting API to update ClickHouse Cluster %q: %s", d.Id(), err)
@@ -1368,6 +1703,38 @@ func updateClickHouseClusterParams(d *schema.ResourceData, meta interface{}) err
 	return nil
 }
 
+func getClickHouseClusterUpdatePaths(d *schema.ResourceData) []string {
+	updatePath := []string{}
+	for field, path := range mdbClickHouseUpdateFieldsMap {
+		if !d.HasChange(field) {
+			continue
+		}
+		if field == "clickhouse" && isOnlyClickHouseRabbitmqChanged(d) {
+			// Passwords are not returned by API, so the change comes from config only
+			// and must be sent explicitly.
+			path = "config_spec.clickhouse.config.rabbitmq"
+		}
+		if field == "clickhouse" && !d.HasChange("clickhouse.0.config") {
+			// Only resources are changed, the rest of the config is left as is.
+			path = "config_spec.clickhouse.resources"
+		}
+		updatePath = append(updatePath, path)
+	}
+	return updatePath
+}
+
+func isOnlyClickHouseRabbitmqChanged(d *schema.ResourceData) bool {
+	if !d.HasChange("clickhouse.0.config.0.rabbitmq") || d.HasChange("clickhouse.0.resources") {
+		return false
+	}
+	for key := range schemaConfig {
+		if key != "rabbitmq" && d.HasChange("clickhouse.0.config.0."+key) {
+			return false
+		}
+	}
+	return true
+}
+
 func getClickHouseClusterUpdateRequest(d *schema.ResourceData) (*clickhouse.UpdateClusterRequest, error) {
 	labels, err := expandLabels(d.Get("labels"))
 	if err != nil {
@@ -1428,16 +1795,17 @@ func updateClickHouseClusterDatabases(d *schema.ResourceData, meta interface{})
 		return err
 	}
 
+	// Only missing databases are created and only removed ones are deleted, the rest are left intact.
 	toDelete, toAdd := clickHouseDatabasesDiff(currDBs, targetDBs)
 
-	for _, db := range toDelete {
-		err := deleteClickHouseDatabase(ctx, config, d, db)
+	for _, db := range toAdd {
+		err := createClickHouseDatabase(ctx, config, d, db)
 		if err != nil {
 			return err
 		}
 	}
-	for _, db := range toAdd {
-		err := createClickHouseDatabase(ctx, config, d, db)
+	for _, db := range toDelete {
+		err := deleteClickHouseDatabase(ctx, config, d, db)
 		if err != nil {
 			return err
 		}
@@ -1459,13 +1827,9 @@ func updateClickHouseClusterUsers(d *schema.ResourceData, meta interface{}) erro
 		return err
 	}
 
+	// Users are matched by name, so users that are present in both lists are updated in place
+	// and never dropped. New users are created first and removed users are deleted last.
 	toDelete, toAdd := clickHouseUsersDiff(currUsers, targetUsers)
-	for _, u := range toDelete {
-		err := deleteClickHouseUser(ctx, config, d, u)
-		if err != nil {
-			return err
-		}
-	}
 	for _, u := range toAdd {
 		err := createClickHouseUser(ctx, config, d, u)
 		if err != nil {
@@ -1482,6 +1846,13 @@ func updateClickHouseClusterUsers(d *schema.ResourceData, meta interface{}) erro
 		}
 	}
 
+	for _, u := range toDelete {
+		err := deleteClickHouseUser(ctx, config, d, u)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -1631,9 +2002,17 @@ func updateClickHouseClusterShards(d *schema.ResourceData, meta interface{}) err
 
 	log.Printf("[DEBUG] before update shards got shards from schema: %+v\n", shardsFromSpec)
 
+	cluster, err := config.sdk.MDB().Clickhouse().Cluster().Get(ctx, &clickhouse.GetClusterRequest{
+		ClusterId: d.Id(),
+	})
+	if err != nil {
+		return fmt.Errorf("error while getting ClickHouse Cluster %q: %s", d.Id(), err)
+	}
+	clusterConfig := cluster.GetConfig().GetClickhouse().GetConfig().GetEffectiveConfig()
+
 	for _, shard := range shardsOnCluster {
 		if shardSpec, ok := shardsFromSpec[shard.Name]; ok {
-			if err = updateClickHouseShard(ctx, config, d, shard.Name, shardSpec); err != nil {
+			if err = updateClickHouseShard(ctx, config, d, shard.Name, shardSpec, clusterConfig); err != nil {
 				return fmt.Errorf("failed update shard from config: %s", err)
 			}
 		}
@@ -1830,16 +2209,30 @@ func deleteClickHouseUser(ctx context.Context, config *Config, d *schema.Resourc
 	return nil
 }
 
+func prepareUpdateClickHouseUserRequest(d *schema.ResourceData, user *clickhouse.UserSpec) *clickhouse.UpdateUserRequest {
+	req := &clickhouse.UpdateUserRequest{
+		ClusterId:   d.Id(),
+		UserName:    user.Name,
+		Password:    user.Password,
+		Permissions: user.Permissions,
+		Settings:    user.Settings,
+		Quotas:      user.Quotas,
+	}
+
+	if configured := clickHouseUserConfiguredSettings(d, user.Name); configured != nil {
+		updatePath := []string{"password", "permissions", "quotas"}
+		for _, setting := range configured {
+			updatePath = append(updatePath, "settings."+setting)
+		}
+		req.UpdateMask = &field_mask.FieldMask{Paths: updatePath}
+	}
+
+	return req
+}
+
 func updateClickHouseUser(ctx context.Context, config *Config, d *schema.ResourceData, user *clickhouse.UserSpec) error {
 	op, err := config.sdk.WrapOperation(
-		config.sdk.MDB().Clickhouse().User().Update(ctx, &clickhouse.UpdateUserRequest{
-			ClusterId:   d.Id(),
-			UserName:    user.Name,
-			Password:    user.Password,
-			Permissions: user.Permissions,
-			Settings:    user.Settings,
-			Quotas:      user.Quotas,
-		}),
+		config.sdk.MDB().Clickhouse().User().Update(ctx, prepareUpdateClickHouseUserRequest(d, user)),
 	)
 	if err != nil {
 		return fmt.Errorf("error while requesting API to update user in ClickHouse Cluster %q: %s", d.Id(), err)
@@ -1950,6 +2343,288 @@ func isShardResourceResourcePresetIdChanged(fromCluster, fromSpec *clickhouse.Re
 	return false
 }
 
+// Only network disks can be switched to each other in-place, local and
+// non-replicated disks require a new cluster.
+var clickHouseInPlaceDiskTypes = map[string]bool{
+	"network-hdd": true,
+	"network-ssd": true,
+}
+
+func checkClickHouseDiskTypeTransition(key, from, to string) error {
+	if from == "" || to == "" || from == to {
+		return nil
+	}
+	if clickHouseInPlaceDiskTypes[from] && clickHouseInPlaceDiskTypes[to] {
+		return nil
+	}
+	return fmt.Errorf("changing %s from %q to %q is not supported for ClickHouse cluster, "+
+		"only %s disk types can be changed in-place", key, from, to, getJoinedKeys(clickHouseInPlaceDiskTypeNames()))
+}
+
+func clickHouseInPlaceDiskTypeNames() []string {
+	names := make([]string, 0, len(clickHouseInPlaceDiskTypes))
+	for name := range clickHouseInPlaceDiskTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func clickHouseDiskTypeDiffCustomize(ctx context.Context, rdiff *schema.ResourceDiff, _ interface{}) error {
+	if rdiff.Id() == "" {
+		return nil
+	}
+
+	for _, key := range []string{"clickhouse.0.resources.0.disk_type_id", "zookeeper.0.resources.0.disk_type_id"} {
+		if !rdiff.HasChange(key) {
+			continue
+		}
+		from, to := rdiff.GetChange(key)
+		if err := checkClickHouseDiskTypeTransition(key, from.(string), to.(string)); err != nil {
+			return err
+		}
+	}
+
+	if !rdiff.HasChange("shard") {
+		return nil
+	}
+	oldShards, newShards := rdiff.GetChange("shard")
+	oldDiskTypes := clickHouseShardDiskTypes(oldShards.(*schema.Set))
+	for name, to := range clickHouseShardDiskTypes(newShards.(*schema.Set)) {
+		key := fmt.Sprintf("disk_type_id of shard %q", name)
+		if err := checkClickHouseDiskTypeTransition(key, oldDiskTypes[name], to); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Changing environment recreates the cluster, which the API rejects while deletion
+// protection is enabled, so fail at plan time instead of after the apply has started.
+// With force_destroy the protection is disabled before the cluster is deleted.
+func clickHouseEnvironmentDiffCustomize(ctx context.Context, rdiff *schema.ResourceDiff, _ interface{}) error {
+	if rdiff.Id() == "" || !rdiff.HasChange("environment") {
+		return nil
+	}
+
+	// The replaced cluster is deleted with its state, so the values before the change apply.
+	deletionProtection, _ := rdiff.GetChange("deletion_protection")
+	forceDestroy, _ := rdiff.GetChange("force_destroy")
+	if deletionProtection.(bool) && !forceDestroy.(bool) {
+		from, to := rdiff.GetChange("environment")
+		return fmt.Errorf("changing environment from %q to %q requires ClickHouse cluster recreation, "+
+			"disable deletion_protection first", from, to)
+	}
+	return nil
+}
+
+func clickHouseSqlManagementDiffCustomize(ctx context.Context, rdiff *schema.ResourceDiff, _ interface{}) error {
+	sqlUserManagement := rdiff.Get("sql_user_management").(bool)
+	sqlDatabaseManagement := rdiff.Get("sql_database_management").(bool)
+
+	if rdiff.Get("admin_password").(string) != "" && !sqlUserManagement {
+		return fmt.Errorf("admin_password can only be set when sql_user_management is enabled")
+	}
+	if sqlUserManagement && rdiff.Get("user").(*schema.Set).Len() > 0 {
+		return fmt.Errorf("user blocks can't be used when sql_user_management is enabled, manage users with SQL instead")
+	}
+	if sqlDatabaseManagement && rdiff.Get("database").(*schema.Set).Len() > 0 {
+		return fmt.Errorf("database blocks can't be used when sql_database_management is enabled, manage databases with SQL instead")
+	}
+	return nil
+}
+
+// Format schemas and ML models are downloaded from Object Storage on behalf of the cluster service account,
+// so fail on plan instead of waiting for the cluster operation to fail.
+func clickHouseServiceAccountDiffCustomize(ctx context.Context, rdiff *schema.ResourceDiff, _ interface{}) error {
+	if rdiff.Get("format_schema").(*schema.Set).Len() == 0 && rdiff.Get("ml_model").(*schema.Set).Len() == 0 {
+		return nil
+	}
+
+	if !rdiff.NewValueKnown("service_account_id") {
+		// On create an omitted service_account_id is unknown as well, tell it apart from a not yet known reference.
+		rawConfig := rdiff.GetRawConfig()
+		if rawConfig.IsNull() || !rawConfig.GetAttr("service_account_id").IsNull() {
+			return nil
+		}
+	} else if rdiff.Get("service_account_id").(string) != "" {
+		return nil
+	}
+
+	return fmt.Errorf("service_account_id must be set when format_schema or ml_model is used, " +
+		"the service account needs access to the Object Storage bucket with the referenced files")
+}
+
+func getClickHouseSubnetNetworkID(ctx context.Context, meta interface{}, subnetID string) (string, error) {
+	config := meta.(*Config)
+	subnet, err := config.sdk.VPC().Subnet().Get(ctx, &vpc.GetSubnetRequest{
+		SubnetId: subnetID,
+	})
+	if err != nil {
+		return "", err
+	}
+	return subnet.NetworkId, nil
+}
+
+// Hosts can only be placed in subnets of the cluster network, check them on plan
+// so that a network_id change gives a clear error instead of failing the recreation.
+// Only subnets set in the configuration are checked, computed ones still refer to the old network.
+func clickHouseHostSubnetsDiffCustomize(getSubnetNetworkID func(ctx context.Context, meta interface{}, subnetID string) (string, error)) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, rdiff *schema.ResourceDiff, meta interface{}) error {
+		if rdiff.Id() != "" && !rdiff.HasChange("network_id") && !rdiff.HasChange("host") {
+			return nil
+		}
+		if !rdiff.NewValueKnown("network_id") {
+			return nil
+		}
+		networkID := rdiff.Get("network_id").(string)
+
+		rawHosts := cty.NilVal
+		if rawConfig := rdiff.GetRawConfig(); !rawConfig.IsNull() && rawConfig.IsKnown() {
+			rawHosts = rawConfig.GetAttr("host")
+		}
+
+		subnetNetworks := map[string]string{}
+		for i, h := range rdiff.Get("host").([]interface{}) {
+			if !rdiff.NewValueKnown(fmt.Sprintf("host.%d.subnet_id", i)) || !isClickHouseHostSubnetConfigured(rawHosts, i) {
+				continue
+			}
+			subnetID := h.(map[string]interface{})["subnet_id"].(string)
+			if subnetID == "" {
+				continue
+			}
+
+			subnetNetworkID, ok := subnetNetworks[subnetID]
+			if !ok {
+				var err error
+				subnetNetworkID, err = getSubnetNetworkID(ctx, meta, subnetID)
+				if err != nil {
+					return fmt.Errorf("error while getting subnet %q of ClickHouse host: %s", subnetID, err)
+				}
+				subnetNetworks[subnetID] = subnetNetworkID
+			}
+
+			if subnetNetworkID != networkID {
+				return fmt.Errorf("subnet %q of ClickHouse host in zone %q belongs to network %q, not to the cluster network %q",
+					subnetID, h.(map[string]interface{})["zone"], subnetNetworkID, networkID)
+			}
+		}
+		return nil
+	}
+}
+
+// isClickHouseHostSubnetConfigured reports whether subnet_id of the i-th host is set in the raw configuration.
+// Without the raw configuration every subnet is treated as configured.
+func isClickHouseHostSubnetConfigured(rawHosts cty.Value, i int) bool {
+	if rawHosts == cty.NilVal {
+		return true
+	}
+	if rawHosts.IsNull() || !rawHosts.IsKnown() || rawHosts.LengthInt() <= i {
+		return false
+	}
+	subnetID := rawHosts.Index(cty.NumberIntVal(int64(i))).GetAttr("subnet_id")
+	return !subnetID.IsNull() && subnetID.IsKnown()
+}
+
+// The API rejects a data cache size while the data cache is disabled.
+func clickHouseCloudStorageDiffCustomize(ctx context.Context, rdiff *schema.ResourceDiff, _ interface{}) error {
+	if !rdiff.NewValueKnown("cloud_storage.0.data_cache_enabled") || !rdiff.NewValueKnown("cloud_storage.0.data_cache_max_size") {
+		return nil
+	}
+
+	cloudStorage := rdiff.Get("cloud_storage").([]interface{})
+	if len(cloudStorage) == 0 || cloudStorage[0] == nil {
+		return nil
+	}
+	spec := cloudStorage[0].(map[string]interface{})
+	if spec["data_cache_enabled"].(bool) || spec["data_cache_max_size"].(int) == 0 {
+		return nil
+	}
+
+	// data_cache_max_size is computed, so only a size set in the configuration is an error,
+	// not the one kept in the state after the data cache has been disabled.
+	if rawConfig := rdiff.GetRawConfig(); !rawConfig.IsNull() && rawConfig.IsKnown() {
+		rawCloudStorage := rawConfig.GetAttr("cloud_storage")
+		if rawCloudStorage.IsNull() || !rawCloudStorage.IsKnown() || rawCloudStorage.LengthInt() == 0 {
+			return nil
+		}
+		if rawCloudStorage.Index(cty.NumberIntVal(0)).GetAttr("data_cache_max_size").IsNull() {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("cloud_storage.0.data_cache_max_size must be 0 when cloud_storage.0.data_cache_enabled is false, got %d",
+		spec["data_cache_max_size"].(int))
+}
+
+// delayed_until is the time to reschedule maintenance to, so it is required with the SPECIFIC_TIME
+// reschedule type and meaningless with the others.
+func clickHouseRescheduleMaintenanceDiffCustomize(ctx context.Context, rdiff *schema.ResourceDiff, _ interface{}) error {
+	if !rdiff.NewValueKnown("reschedule_maintenance.0.reschedule_type") || !rdiff.NewValueKnown("reschedule_maintenance.0.delayed_until") {
+		return nil
+	}
+
+	rescheduleType, ok := rdiff.GetOk("reschedule_maintenance.0.reschedule_type")
+	if !ok {
+		return nil
+	}
+	_, delayedUntilSet := rdiff.GetOk("reschedule_maintenance.0.delayed_until")
+
+	switch {
+	case rescheduleType == "SPECIFIC_TIME" && !delayedUntilSet:
+		return fmt.Errorf("reschedule_maintenance.0.delayed_until should be set with SPECIFIC_TIME reschedule type")
+	case rescheduleType != "SPECIFIC_TIME" && delayedUntilSet:
+		return fmt.Errorf("reschedule_maintenance.0.delayed_until should be omitted with %s reschedule type", rescheduleType)
+	}
+	return nil
+}
+
+// A dedicated ZooKeeper subcluster needs a quorum, so the API only accepts 1 or 3 hosts
+// placed in distinct zones. Check it on plan instead of failing after a long apply.
+func clickHouseZooKeeperHostsDiffCustomize(ctx context.Context, rdiff *schema.ResourceDiff, _ interface{}) error {
+	if rdiff.Id() != "" && !rdiff.HasChange("host") {
+		return nil
+	}
+
+	count := 0
+	zones := map[string]struct{}{}
+	for i, h := range rdiff.Get("host").([]interface{}) {
+		host := h.(map[string]interface{})
+		if host["type"] != "ZOOKEEPER" {
+			continue
+		}
+		count++
+
+		if !rdiff.NewValueKnown(fmt.Sprintf("host.%d.zone", i)) {
+			continue
+		}
+		zone := host["zone"].(string)
+		if _, ok := zones[zone]; ok {
+			return fmt.Errorf("ZooKeeper hosts of ClickHouse cluster must be placed in distinct zones, "+
+				"zone %q is used more than once", zone)
+		}
+		zones[zone] = struct{}{}
+	}
+
+	if count != 0 && count != 1 && count != 3 {
+		return fmt.Errorf("ClickHouse cluster must have 1 or 3 ZooKeeper hosts, got %d", count)
+	}
+	return nil
+}
+
+func clickHouseShardDiskTypes(shards *schema.Set) map[string]string {
+	result := map[string]string{}
+	for _, v := range shards.List() {
+		shard := v.(map[string]interface{})
+		resources, ok := shard["resources"].([]interface{})
+		if !ok || len(resources) == 0 || resources[0] == nil {
+			continue
+		}
+		result[shard["name"].(string)] = resources[0].(map[string]interface{})["disk_type_id"].(string)
+	}
+	return result
+}
+
 func isShardResourceDiskTypeIdChanged(fromCluster, fromSpec *clickhouse.Resources) bool {
 	if fromCluster != nil && fromSpec == nil {
 		log.Printf("[DEBUG] shard's DiskTypeId is removed from configuration. set default value.")
@@ -1963,7 +2638,9 @@ func isShardResourceDiskTypeIdChanged(fromCluster, fromSpec *clickhouse.Resource
 	return false
 }
 
-func updateClickHouseShard(ctx context.Context, config *Config, d *schema.ResourceData, shardName string, shardSpec *clickhouse.ShardConfigSpec) error {
+// updateClickHouseShard updates weight, resources and config overrides of the shard.
+// Config settings removed from the shard are reset to the values of clusterConfig, if it is given.
+func updateClickHouseShard(ctx context.Context, config *Config, d *schema.ResourceData, shardName string, shardSpec *clickhouse.ShardConfigSpec, clusterConfig *clickhouseConfig.ClickhouseConfig) error {
 	resp, err := config.sdk.MDB().Clickhouse().Cluster().GetShard(context.Background(), &clickhouse.GetClusterShardRequest{
 		ClusterId: d.Id(),
 		ShardName: shardName,
@@ -2002,6 +2679,12 @@ func updateClickHouseShard(ctx context.Context, config *Config, d *schema.Resour
 		}
 	}
 
+	if configPaths := clickHouseShardConfigUpdatePaths(resp.Config.Clickhouse.GetConfig().GetEffectiveConfig(), shardSpec, clusterConfig); len(configPaths) > 0 {
+		log.Printf("[DEBUG] shard=%s has changed config settings: %v\n", shardName, configPaths)
+		updateRequired = true
+		updatePath = append(updatePath, configPaths...)
+	}
+
 	if !updateRequired {
 		return nil
 	}
@@ -2245,6 +2928,24 @@ func createClickHouseZooKeeper(ctx context.Context, config *Config, d *schema.Re
 //	return nil
 //}
 
+func listClickHouseClusters(ctx context.Context, config *Config, folderID string) ([]*clickhouse.Cluster, error) {
+	clusters, err := listMDBPages(ctx, func(ctx context.Context, pageToken string) ([]*clickhouse.Cluster, string, error) {
+		resp, err := config.sdk.MDB().Clickhouse().Cluster().List(ctx, &clickhouse.ListClustersRequest{
+			FolderId:  folderID,
+			PageSize:  defaultMDBPageSize,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Clusters, resp.NextPageToken, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error while getting list of clusters in folder '%s': %s", folderID, err)
+	}
+	return clusters, nil
+}
+
 func listClickHouseHosts(ctx context.Context, config *Config, id string) ([]*clickhouse.Host, error) {
 	hosts := []*clickhouse.Host{}
 	pageToken := ""
@@ -2341,6 +3042,12 @@ func resourceYandexMDBClickHouseClusterDelete(d *schema.ResourceData, meta inter
 	ctx, cancel := config.ContextWithTimeout(d.Timeout(schema.TimeoutDelete))
 	defer cancel()
 
+	if d.Get("force_destroy").(bool) {
+		if err := disableClickHouseClusterDeletionProtection(ctx, config, d.Id()); err != nil {
+			return err
+		}
+	}
+
 	op, err := config.sdk.WrapOperation(config.sdk.MDB().Clickhouse().Cluster().Delete(ctx, req))
 	if err != nil {
 		return handleNotFoundError(err, d, fmt.Sprintf("ClickHouse Cluster %q", d.Get("name").(string)))
@@ -2360,6 +3067,40 @@ func resourceYandexMDBClickHouseClusterDelete(d *schema.ResourceData, meta inter
 	return nil
 }
 
+func disableClickHouseClusterDeletionProtection(ctx context.Context, config *Config, id string) error {
+	cluster, err := config.sdk.MDB().Clickhouse().Cluster().Get(ctx, &clickhouse.GetClusterRequest{
+		ClusterId: id,
+	})
+	if err != nil {
+		if isStatusWithCode(err, codes.NotFound) {
+			// let the delete request report the missing cluster
+			return nil
+		}
+		return fmt.Errorf("error while getting ClickHouse Cluster %q: %s", id, err)
+	}
+	if !cluster.DeletionProtection {
+		return nil
+	}
+
+	log.Printf("[DEBUG] Disabling deletion protection of ClickHouse Cluster %q before deletion", id)
+
+	req := &clickhouse.UpdateClusterRequest{
+		ClusterId:          id,
+		DeletionProtection: false,
+		UpdateMask:         &field_mask.FieldMask{Paths: []string{"deletion_protection"}},
+	}
+
+	op, err := config.sdk.WrapOperation(config.sdk.MDB().Clickhouse().Cluster().Update(ctx, req))
+	if err == nil {
+		err = op.Wait(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("error while disabling deletion protection of ClickHouse Cluster %q: %s", id, err)
+	}
+
+	return nil
+}
+
 func listClickHouseShardGroups(ctx context.Context, config *Config, id string) ([]*clickhouse.ShardGroup, error) {
 	var groups []*clickhouse.ShardGroup
 	pageToken := ""
@@ -2436,13 +3177,18 @@ func suppressZooKeeperResourcesDIff(k, old, new string, d *schema.ResourceData)
 	return true
 }
 
-func setShardsToSchema(ctx context.Context, config *Config, d *schema.ResourceData) error {
+func setShardsToSchema(ctx context.Context, config *Config, d *schema.ResourceData, clusterConfig *clickhouseConfig.ClickhouseConfig) error {
 	shardsOnCluster, err := listClickHouseShards(ctx, config, d.Id())
 	if err != nil {
 		return fmt.Errorf("read cluster: failed to get list of current shards: %s", err)
 	}
 
-	shards, err := flattenClickHouseShards(shardsOnCluster)
+	shardsFromSpec, err := expandClickhouseShardSpecs(d)
+	if err != nil {
+		return fmt.Errorf("read cluster: failed to expand shards from schema: %s", err)
+	}
+
+	shards, err := flattenClickHouseShards(shardsOnCluster, clusterConfig, shardsFromSpec)
 	if err != nil {
 		return fmt.Errorf("read cluster: failed to flat current shards: %s", err)
 	}
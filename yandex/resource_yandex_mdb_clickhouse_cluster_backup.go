@@ -0,0 +1,244 @@
+package yandex
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/mdb/clickhouse/v1"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/operation"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	yandexMDBClickHouseClusterBackupCreateTimeout = 60 * time.Minute
+	yandexMDBClickHouseClusterBackupReadTimeout   = 1 * time.Minute
+	yandexMDBClickHouseClusterBackupDeleteTimeout = 1 * time.Minute
+)
+
+func resourceYandexMDBClickHouseClusterBackup() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceYandexMDBClickHouseClusterBackupCreate,
+		Read:   resourceYandexMDBClickHouseClusterBackupRead,
+		Delete: resourceYandexMDBClickHouseClusterBackupDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(yandexMDBClickHouseClusterBackupCreateTimeout),
+			Read:   schema.DefaultTimeout(yandexMDBClickHouseClusterBackupReadTimeout),
+			Delete: schema.DefaultTimeout(yandexMDBClickHouseClusterBackupDeleteTimeout),
+		},
+
+		SchemaVersion: 0,
+
+		Schema: map[string]*schema.Schema{
+			"cluster_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"backup_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"backup_ids": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"folder_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"source_shard_names": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"started_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"created_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceYandexMDBClickHouseClusterBackupCreate(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	ctx, cancel := config.ContextWithTimeout(d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+
+	clusterID := d.Get("cluster_id").(string)
+
+	// Backup operation doesn't report ID of the created backup,
+	// so it is detected by comparing cluster backups before and after the operation.
+	existing, err := listClickHouseClusterBackups(ctx, config, clusterID)
+	if err != nil {
+		return err
+	}
+
+	op, err := retryConflictingOperation(ctx, config, func() (*operation.Operation, error) {
+		log.Printf("[DEBUG] Sending ClickHouse cluster backup request for cluster %q", clusterID)
+		return config.sdk.MDB().Clickhouse().Cluster().Backup(ctx, &clickhouse.BackupClusterRequest{
+			ClusterId: clusterID,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("error while requesting API to backup ClickHouse Cluster %q: %s", clusterID, err)
+	}
+
+	if err := op.Wait(ctx); err != nil {
+		return fmt.Errorf("error while waiting for backup of ClickHouse Cluster %q: %s", clusterID, err)
+	}
+
+	if _, err := op.Response(); err != nil {
+		return fmt.Errorf("backup of ClickHouse Cluster %q failed: %s", clusterID, err)
+	}
+
+	backups, err := listClickHouseClusterBackups(ctx, config, clusterID)
+	if err != nil {
+		return err
+	}
+
+	// A sharded cluster is backed up into one backup per shard.
+	created := findNewClickHouseBackups(existing, backups)
+	if len(created) == 0 {
+		return fmt.Errorf("backup of ClickHouse Cluster %q is finished but not found in the list of cluster backups", clusterID)
+	}
+
+	d.SetId(created[0].Id)
+	backupIDs, _ := flattenClickHouseBackupIDs(created)
+	if err := d.Set("backup_ids", backupIDs); err != nil {
+		return fmt.Errorf("error setting backup_ids: %s", err)
+	}
+
+	return resourceYandexMDBClickHouseClusterBackupRead(d, meta)
+}
+
+func resourceYandexMDBClickHouseClusterBackupRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+
+	ctx, cancel := config.ContextWithTimeout(d.Timeout(schema.TimeoutRead))
+	defer cancel()
+
+	backup, err := config.sdk.MDB().Clickhouse().Backup().Get(ctx, &clickhouse.GetBackupRequest{
+		BackupId: d.Id(),
+	})
+	if err != nil {
+		return handleNotFoundError(err, d, fmt.Sprintf("ClickHouse backup %q", d.Id()))
+	}
+
+	backups := []*clickhouse.Backup{backup}
+	known := map[string]bool{backup.Id: true}
+	for _, v := range d.Get("backup_ids").(map[string]interface{}) {
+		id := v.(string)
+		if known[id] {
+			continue
+		}
+		known[id] = true
+
+		shardBackup, err := config.sdk.MDB().Clickhouse().Backup().Get(ctx, &clickhouse.GetBackupRequest{
+			BackupId: id,
+		})
+		if err != nil {
+			if isStatusWithCode(err, codes.NotFound) {
+				log.Printf("[DEBUG] ClickHouse backup %q is not found, removing it from backup_ids", id)
+				continue
+			}
+			return fmt.Errorf("error while getting ClickHouse backup %q: %s", id, err)
+		}
+		backups = append(backups, shardBackup)
+	}
+
+	d.Set("cluster_id", backup.SourceClusterId)
+	d.Set("backup_id", backup.Id)
+	d.Set("folder_id", backup.FolderId)
+	d.Set("started_at", getTimestamp(backup.StartedAt))
+	d.Set("created_at", getTimestamp(backup.CreatedAt))
+
+	backupIDs, shardNames := flattenClickHouseBackupIDs(backups)
+	if err := d.Set("backup_ids", backupIDs); err != nil {
+		return fmt.Errorf("error setting backup_ids: %s", err)
+	}
+	return d.Set("source_shard_names", shardNames)
+}
+
+func resourceYandexMDBClickHouseClusterBackupDelete(d *schema.ResourceData, meta interface{}) error {
+	// Backups can't be deleted via API, they are removed automatically according to the cluster retention policy.
+	log.Printf("[DEBUG] Removing ClickHouse backup %q from state, the backup itself is retained", d.Id())
+	return nil
+}
+
+func listClickHouseClusterBackups(ctx context.Context, config *Config, clusterID string) ([]*clickhouse.Backup, error) {
+	backups, err := listMDBPages(ctx, func(ctx context.Context, pageToken string) ([]*clickhouse.Backup, string, error) {
+		resp, err := config.sdk.MDB().Clickhouse().Cluster().ListBackups(ctx, &clickhouse.ListClusterBackupsRequest{
+			ClusterId: clusterID,
+			PageSize:  defaultMDBPageSize,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Backups, resp.NextPageToken, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error while getting list of backups for '%s': %s", clusterID, err)
+	}
+	return backups, nil
+}
+
+// findNewClickHouseBackups returns backups from after that are absent in before, ordered by their first shard name.
+func findNewClickHouseBackups(before, after []*clickhouse.Backup) []*clickhouse.Backup {
+	known := make(map[string]bool, len(before))
+	for _, b := range before {
+		known[b.Id] = true
+	}
+
+	var result []*clickhouse.Backup
+	for _, b := range after {
+		if !known[b.Id] {
+			result = append(result, b)
+		}
+	}
+
+	firstShard := func(b *clickhouse.Backup) string {
+		if len(b.SourceShardNames) == 0 {
+			return ""
+		}
+		return b.SourceShardNames[0]
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if firstShard(result[i]) != firstShard(result[j]) {
+			return firstShard(result[i]) < firstShard(result[j])
+		}
+		return result[i].Id < result[j].Id
+	})
+	return result
+}
+
+// flattenClickHouseBackupIDs maps source shard names to IDs of the backups they are stored in,
+// and returns the sorted shard names.
+func flattenClickHouseBackupIDs(backups []*clickhouse.Backup) (map[string]string, []string) {
+	backupIDs := map[string]string{}
+	var shardNames []string
+	for _, b := range backups {
+		for _, shard := range b.SourceShardNames {
+			if _, ok := backupIDs[shard]; !ok {
+				shardNames = append(shardNames, shard)
+			}
+			backupIDs[shard] = b.Id
+		}
+	}
+	sort.Strings(shardNames)
+	return backupIDs, shardNames
+}
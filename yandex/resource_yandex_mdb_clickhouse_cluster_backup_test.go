@@ -0,0 +1,134 @@
+package yandex
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/mdb/clickhouse/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const chBackupResource = "yandex_mdb_clickhouse_cluster_backup.foo"
+
+func TestFindNewClickHouseBackups(t *testing.T) {
+	now := time.Now()
+	backup := func(id string, createdAt time.Time, shards ...string) *clickhouse.Backup {
+		return &clickhouse.Backup{Id: id, CreatedAt: timestamppb.New(createdAt), SourceShardNames: shards}
+	}
+
+	old := backup("old", now.Add(-time.Hour), "shard1")
+	first := backup("first", now, "shard2")
+	second := backup("second", now.Add(-time.Minute), "shard1")
+
+	require.Empty(t, findNewClickHouseBackups(nil, nil))
+	require.Empty(t, findNewClickHouseBackups([]*clickhouse.Backup{old}, []*clickhouse.Backup{old}))
+	require.Equal(t, []*clickhouse.Backup{first}, findNewClickHouseBackups(nil, []*clickhouse.Backup{first}))
+	require.Equal(t, []*clickhouse.Backup{second, first},
+		findNewClickHouseBackups([]*clickhouse.Backup{old}, []*clickhouse.Backup{first, old, second}))
+}
+
+func TestFlattenClickHouseBackupIDs(t *testing.T) {
+	backupIDs, shardNames := flattenClickHouseBackupIDs([]*clickhouse.Backup{
+		{Id: "backup2", SourceShardNames: []string{"shard2", "shard3"}},
+		{Id: "backup1", SourceShardNames: []string{"shard1"}},
+	})
+
+	require.Equal(t, map[string]string{"shard1": "backup1", "shard2": "backup2", "shard3": "backup2"}, backupIDs)
+	require.Equal(t, []string{"shard1", "shard2", "shard3"}, shardNames)
+}
+
+func TestAccMDBClickHouseClusterBackup_basic(t *testing.T) {
+	t.Parallel()
+
+	var r clickhouse.Cluster
+	chName := acctest.RandomWithPrefix("tf-clickhouse-backup")
+	chDesc := "ClickHouse Cluster Backup Test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMDBClickHouseClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMDBClickHouseClusterBackupConfig(chName, chDesc),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMDBClickHouseClusterExists(chResource, &r, 1),
+					testAccCheckMDBClickHouseClusterBackupExists(chBackupResource),
+					resource.TestCheckResourceAttrPair(chBackupResource, "cluster_id", chResource, "id"),
+					resource.TestCheckResourceAttrPair(chBackupResource, "backup_id", chBackupResource, "id"),
+					resource.TestCheckResourceAttrPair(chBackupResource, "backup_ids.shard1", chBackupResource, "id"),
+					resource.TestCheckResourceAttrSet(chBackupResource, "created_at"),
+				),
+			},
+			{
+				ResourceName:      chBackupResource,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckMDBClickHouseClusterBackupExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		config := testAccProvider.Meta().(*Config)
+
+		found, err := config.sdk.MDB().Clickhouse().Backup().Get(context.Background(), &clickhouse.GetBackupRequest{
+			BackupId: rs.Primary.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if found.SourceClusterId != rs.Primary.Attributes["cluster_id"] {
+			return fmt.Errorf("ClickHouse backup %s belongs to cluster %s, expected %s",
+				found.Id, found.SourceClusterId, rs.Primary.Attributes["cluster_id"])
+		}
+
+		return nil
+	}
+}
+
+func testAccMDBClickHouseClusterBackupConfig(name, desc string) string {
+	return fmt.Sprintf(clickHouseVPCDependencies+`
+resource "yandex_mdb_clickhouse_cluster" "foo" {
+  name           = "%s"
+  description    = "%s"
+  environment    = "PRESTABLE"
+  network_id     = "${yandex_vpc_network.mdb-ch-test-net.id}"
+
+  clickhouse {
+    resources {
+      resource_preset_id = "s2.micro"
+      disk_type_id       = "network-ssd"
+      disk_size          = 16
+    }
+  }
+
+  host {
+    type      = "CLICKHOUSE"
+    zone      = "ru-central1-a"
+    subnet_id = "${yandex_vpc_subnet.mdb-ch-test-subnet-a.id}"
+  }
+}
+
+resource "yandex_mdb_clickhouse_cluster_backup" "foo" {
+  cluster_id = "${yandex_mdb_clickhouse_cluster.foo.id}"
+}
+`, name, desc)
+}
@@ -2,6 +2,7 @@ package yandex
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"reflect"
@@ -14,13 +15,18 @@ import (
 	"google.golang.org/genproto/protobuf/field_mask"
 
 	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
 
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/mdb/clickhouse/v1"
 	cfg "github.com/yandex-cloud/go-genproto/yandex/cloud/mdb/clickhouse/v1/config"
+	"github.com/yandex-cloud/go-genproto/yandex/cloud/operation"
 )
 
 const chVersion = "22.8"
@@ -113,6 +119,8 @@ func mdbClickHouseClusterImportStep(name string) resource.TestStep {
 			"zookeeper",                         // zookeeper spec is not imported by default
 			"health",                            // volatile value
 			"copy_schema_on_new_hosts",          // special parameter
+			"restore",                           // special parameter
+			"reschedule_maintenance",            // special parameter
 			"admin_password",                    // passwords are not returned
 			"clickhouse.0.config.0.kafka",       // passwords are not returned
 			"clickhouse.0.config.0.kafka_topic", // passwords are not returned
@@ -147,6 +155,7 @@ func TestAccMDBClickHouseCluster_full(t *testing.T) {
 					resource.TestCheckResourceAttr(chResource, "security_group_ids.#", "1"),
 					resource.TestCheckResourceAttrSet(chResource, "service_account_id"),
 					resource.TestCheckResourceAttrSet(chResource, "host.0.fqdn"),
+					resource.TestCheckResourceAttrSet(chResource, "host.0.health"),
 
 					resource.TestCheckResourceAttr(chResource, "access.0.web_sql", "true"),
 					resource.TestCheckResourceAttr(chResource, "access.0.data_lens", "true"),
@@ -169,6 +178,9 @@ func TestAccMDBClickHouseCluster_full(t *testing.T) {
 					testAccCheckMDBClickHouseClusterHasMlModels(chResource, map[string]map[string]string{}),
 					testAccCheckCreatedAtAttr(chResource),
 					resource.TestCheckResourceAttr(chResource, "maintenance_window.0.type", "ANYTIME"),
+					resource.TestCheckResourceAttr(chResource, "backup_window_start.0.hours", "3"),
+					resource.TestCheckResourceAttr(chResource, "backup_window_start.0.minutes", "15"),
+					resource.TestCheckResourceAttrSet(chResource, "planned_operation.#"),
 					resource.TestCheckResourceAttr(chResource, "deletion_protection", "false"),
 				),
 			},
@@ -215,7 +227,7 @@ func TestAccMDBClickHouseCluster_full(t *testing.T) {
 			// test 'deletion_protection
 			{
 				Config:      testAccMDBClickHouseClusterConfigMain(chName, "Step 3", "PRODUCTION", true, bucketName, rInt, MaintenanceWindowWeekly),
-				ExpectError: regexp.MustCompile(".*The operation was rejected because cluster has 'deletion_protection' = ON.*"),
+				ExpectError: regexp.MustCompile(".*requires ClickHouse cluster recreation.*"),
 			},
 			mdbClickHouseClusterImportStep(chResource),
 			// Change some options
@@ -223,6 +235,10 @@ func TestAccMDBClickHouseCluster_full(t *testing.T) {
 				Config: testAccMDBClickHouseClusterConfigUpdated(chName, "Step 4", bucketName, rInt),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckMDBClickHouseClusterExists(chResource, &r, 1),
+					// mary is added and john is updated in place
+					testAccCheckMDBClickHouseClusterUserNotDeleted(chResource, "john"),
+					// newdb is added next to testdb
+					testAccCheckMDBClickHouseClusterDatabaseNotDeleted(chResource, "testdb"),
 					resource.TestCheckResourceAttr(chResource, "name", chName),
 					resource.TestCheckResourceAttr(chResource, "folder_id", folderID),
 
@@ -257,16 +273,30 @@ func TestAccMDBClickHouseCluster_full(t *testing.T) {
 					}),
 					testAccCheckCreatedAtAttr(chResource),
 					resource.TestCheckResourceAttr(chResource, "maintenance_window.0.type", "ANYTIME"),
+					resource.TestCheckResourceAttr(chResource, "backup_window_start.0.hours", "4"),
+					resource.TestCheckResourceAttr(chResource, "backup_window_start.0.minutes", "30"),
 					resource.TestCheckResourceAttr(chResource, "cloud_storage.0.enabled", "true"),
 					resource.TestCheckResourceAttr(chResource, "deletion_protection", "false"),
 				),
 			},
 			mdbClickHouseClusterImportStep(chResource),
+			// Reorder security groups (no changes expected)
+			{
+				Config: testAccMDBClickHouseClusterConfigUpdatedWithSecurityGroups(chName, "Step 4", bucketName, rInt,
+					`"${yandex_vpc_security_group.mdb-ch-test-sg-y.id}", "${yandex_vpc_security_group.mdb-ch-test-sg-x.id}"`),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
 			// Check quota, schemas, model, users
 			{
 				Config: testAccMDBClickHouseClusterConfigUser(chName, "Step 5", bucketName, rInt),
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckMDBClickHouseClusterExists(chResource, &r, 1),
+					testAccCheckMDBClickHouseClusterUserNotDeleted(chResource, "john"),
+					testAccCheckMDBClickHouseClusterDatabaseNotDeleted(chResource, "testdb"),
+					// backup_window_start is omitted, so the previously set value is kept
+					resource.TestCheckResourceAttr(chResource, "backup_window_start.0.hours", "4"),
+					resource.TestCheckResourceAttr(chResource, "backup_window_start.0.minutes", "30"),
 					resource.TestCheckResourceAttr(chResource, "name", chName),
 					resource.TestCheckResourceAttr(chResource, "folder_id", folderID),
 
@@ -356,6 +386,7 @@ func TestAccMDBClickHouseCluster_keeper(t *testing.T) {
 					resource.TestCheckResourceAttr(chResourceKeeper, "folder_id", folderID),
 					resource.TestCheckResourceAttr(chResourceKeeper, "description", chDesc),
 					resource.TestCheckResourceAttrSet(chResourceKeeper, "host.0.fqdn"),
+					resource.TestCheckResourceAttrSet(chResourceKeeper, "keeper_hosts.0"),
 					testAccCheckMDBClickHouseClusterContainsLabel(&r, "test_key", "test_value"),
 					testAccCheckMDBClickHouseClusterHasResources(&r, "s2.micro", "network-ssd", 17179869184),
 					testAccCheckMDBClickHouseClusterHasUsers(chResourceKeeper, map[string][]string{}, map[string]map[string]interface{}{}, map[string][]map[string]interface{}{}),
@@ -367,6 +398,177 @@ func TestAccMDBClickHouseCluster_keeper(t *testing.T) {
 	})
 }
 
+func TestAccMDBClickHouseCluster_move(t *testing.T) {
+	t.Parallel()
+
+	targetFolderID := os.Getenv("MDB_TARGET_FOLDER")
+	sourceFolderID := getExampleFolderID()
+	if targetFolderID == "" {
+		t.Skip("Required var MDB_TARGET_FOLDER is not set.")
+	}
+
+	var r clickhouse.Cluster
+	chName := acctest.RandomWithPrefix("tf-clickhouse-move")
+	chDesc := "ClickHouse Cluster Move Test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMDBClickHouseClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMDBClickHouseClusterConfigFolder(chName, chDesc, sourceFolderID),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMDBClickHouseClusterExists(chResource, &r, 1),
+					resource.TestCheckResourceAttr(chResource, "folder_id", sourceFolderID),
+				),
+			},
+			{
+				Config: testAccMDBClickHouseClusterConfigFolder(chName, chDesc, targetFolderID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(chResource, "folder_id", targetFolderID),
+					resource.TestCheckResourceAttrPtr(chResource, "id", &r.Id),
+				),
+			},
+			{
+				Config: testAccMDBClickHouseClusterConfigFolder(chName, chDesc, sourceFolderID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(chResource, "folder_id", sourceFolderID),
+					resource.TestCheckResourceAttrPtr(chResource, "id", &r.Id),
+				),
+			},
+		},
+	})
+}
+
+// Test that a ClickHouse Cluster with deletion protection can be destroyed when force_destroy is set
+func TestAccMDBClickHouseCluster_forceDestroy(t *testing.T) {
+	t.Parallel()
+
+	var r clickhouse.Cluster
+	chName := acctest.RandomWithPrefix("tf-clickhouse-force-destroy")
+	chDesc := "ClickHouse Cluster Force Destroy Test"
+
+	importStep := mdbClickHouseClusterImportStep(chResource)
+	importStep.ImportStateVerifyIgnore = append(importStep.ImportStateVerifyIgnore, "force_destroy") // set to false on import
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMDBClickHouseClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMDBClickHouseClusterConfigForceDestroy(chName, chDesc),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMDBClickHouseClusterExists(chResource, &r, 1),
+					resource.TestCheckResourceAttr(chResource, "deletion_protection", "true"),
+					resource.TestCheckResourceAttr(chResource, "force_destroy", "true"),
+				),
+			},
+			importStep,
+		},
+	})
+}
+
+// Test that changing a label value back and forth is applied to the cluster
+func TestAccMDBClickHouseCluster_labels(t *testing.T) {
+	t.Parallel()
+
+	var r clickhouse.Cluster
+	chName := acctest.RandomWithPrefix("tf-clickhouse-labels")
+	chDesc := "ClickHouse Cluster Labels Test"
+
+	labelsStep := func(value string) resource.TestStep {
+		return resource.TestStep{
+			Config: testAccMDBClickHouseClusterConfigLabels(chName, chDesc, value),
+			Check: resource.ComposeTestCheckFunc(
+				testAccCheckMDBClickHouseClusterExists(chResource, &r, 1),
+				testAccCheckMDBClickHouseClusterContainsLabel(&r, "test_key", value),
+				resource.TestCheckResourceAttr(chResource, "labels.%", "1"),
+				resource.TestCheckResourceAttr(chResource, "labels.test_key", value),
+			),
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMDBClickHouseClusterDestroy,
+		Steps: []resource.TestStep{
+			labelsStep("test_value"),
+			labelsStep("new_value"),
+			mdbClickHouseClusterImportStep(chResource),
+			labelsStep("test_value"),
+		},
+	})
+}
+
+func TestAccMDBClickHouseCluster_rescheduleMaintenance(t *testing.T) {
+	t.Parallel()
+
+	var r clickhouse.Cluster
+	chName := acctest.RandomWithPrefix("tf-clickhouse-reschedule")
+	chDesc := "ClickHouse Cluster Reschedule Maintenance Test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMDBClickHouseClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMDBClickHouseClusterConfigRescheduleMaintenance(chName, chDesc, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMDBClickHouseClusterExists(chResource, &r, 1),
+					resource.TestCheckResourceAttr(chResource, "reschedule_maintenance.#", "0"),
+				),
+			},
+			{
+				Config: testAccMDBClickHouseClusterConfigRescheduleMaintenance(chName, chDesc, `
+  reschedule_maintenance {
+    reschedule_type = "NEXT_AVAILABLE_WINDOW"
+  }
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMDBClickHouseClusterExists(chResource, &r, 1),
+					resource.TestCheckResourceAttr(chResource, "reschedule_maintenance.#", "1"),
+					resource.TestCheckResourceAttr(chResource, "reschedule_maintenance.0.reschedule_type", "NEXT_AVAILABLE_WINDOW"),
+				),
+			},
+			mdbClickHouseClusterImportStep(chResource),
+		},
+	})
+}
+
+func TestAccMDBClickHouseCluster_restore(t *testing.T) {
+	t.Parallel()
+
+	backupID := os.Getenv("CLICKHOUSE_RESTORE_BACKUP_ID")
+	if backupID == "" {
+		t.Skip("Required var CLICKHOUSE_RESTORE_BACKUP_ID is not set.")
+	}
+
+	var r clickhouse.Cluster
+	chName := acctest.RandomWithPrefix("tf-clickhouse-restore")
+	chDesc := "ClickHouse Cluster Restore Test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckMDBClickHouseClusterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMDBClickHouseClusterConfigRestore(chName, chDesc, backupID),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMDBClickHouseClusterExists(chResource, &r, 1),
+					resource.TestCheckResourceAttr(chResource, "name", chName),
+					resource.TestCheckResourceAttr(chResource, "restore.0.backup_id", backupID),
+				),
+			},
+			mdbClickHouseClusterImportStep(chResource),
+		},
+	})
+}
+
 /**
 * Test that a sharded ClickHouse Cluster can be created, updated and destroyed.
 * Also it checks changes shard's configuration.
@@ -404,14 +606,23 @@ func TestAccMDBClickHouseCluster_sharded(t *testing.T) {
 					resource.TestCheckResourceAttr(chResourceSharded, "shard.0.resources.0.disk_size", strconv.Itoa(createFirstShardDiskSize)),
 					resource.TestCheckResourceAttr(chResourceSharded, "shard.0.resources.0.resource_preset_id", "s3-c4-m16"),
 					resource.TestCheckResourceAttr(chResourceSharded, "shard.0.resources.0.disk_type_id", "network-ssd"),
+					resource.TestCheckResourceAttr(chResourceSharded, "shard.0.config.0.mark_cache_size", "10737418240"),
+					resource.TestCheckResourceAttr(chResourceSharded, "shard.0.config.0.max_concurrent_queries", "200"),
 
 					resource.TestCheckResourceAttr(chResourceSharded, "shard.1.name", "shard2"),
 					resource.TestCheckResourceAttr(chResourceSharded, "shard.1.weight", "22"),
 					resource.TestCheckResourceAttr(chResourceSharded, "shard.1.resources.0.disk_size", strconv.Itoa(createSecondShardDiskSize)),
 					resource.TestCheckResourceAttr(chResourceSharded, "shard.1.resources.0.resource_preset_id", "s3-c2-m8"),
 					resource.TestCheckResourceAttr(chResourceSharded, "shard.1.resources.0.disk_type_id", "network-ssd"),
+					resource.TestCheckResourceAttr(chResourceSharded, "shard.1.config.#", "0"),
 
 					resource.TestCheckResourceAttrSet(chResourceSharded, "host.0.fqdn"),
+
+					resource.TestCheckResourceAttr(chResourceSharded, "shard_hosts.#", "2"),
+					resource.TestCheckResourceAttr(chResourceSharded, "shard_hosts.0.shard_name", "shard1"),
+					resource.TestCheckResourceAttr(chResourceSharded, "shard_hosts.0.fqdns.#", "1"),
+					resource.TestCheckResourceAttr(chResourceSharded, "shard_hosts.1.shard_name", "shard2"),
+					resource.TestCheckResourceAttr(chResourceSharded, "shard_hosts.1.fqdns.#", "1"),
 					testAccCheckMDBClickHouseClusterHasShards(&r, []string{"shard1", "shard2"}),
 					testAccCheckMDBClickHouseClusterHasShardGroups(&r, map[string][]string{
 						"test_group":   {"shard1", "shard2"},
@@ -435,6 +646,7 @@ func TestAccMDBClickHouseCluster_sharded(t *testing.T) {
 					resource.TestCheckResourceAttr(chResourceSharded, "shard.0.weight", "110"),
 					resource.TestCheckResourceAttr(chResourceSharded, "shard.0.resources.0.disk_size", strconv.Itoa(updateClusterDiskSize)),
 					resource.TestCheckResourceAttr(chResourceSharded, "shard.0.resources.0.resource_preset_id", "s3-c2-m8"),
+					resource.TestCheckResourceAttr(chResourceSharded, "shard.0.config.#", "0"),
 
 					resource.TestCheckResourceAttr(chResourceSharded, "shard.1.name", "shard3"),
 					resource.TestCheckResourceAttr(chResourceSharded, "shard.1.weight", "330"),
@@ -697,6 +909,16 @@ func TestAccMDBClickHouseCluster_CheckClickhouseConfig(t *testing.T) {
 			MinBytesForWidePart:                            &wrappers.Int64Value{Value: 0},
 			MinRowsForWidePart:                             &wrappers.Int64Value{Value: 0},
 			TtlOnlyDropParts:                               &wrappers.BoolValue{Value: false},
+			InactivePartsToDelayInsert:                     &wrappers.Int64Value{Value: 101},
+			InactivePartsToThrowInsert:                     &wrappers.Int64Value{Value: 110},
+			MaxBytesToMergeAtMaxSpaceInPool:                &wrappers.Int64Value{Value: 16106127360},
+			AllowRemoteFsZeroCopyReplication:               &wrappers.BoolValue{Value: true},
+			MergeWithTtlTimeout:                            &wrappers.Int64Value{Value: 100000},
+			MergeWithRecompressionTtlTimeout:               &wrappers.Int64Value{Value: 100000},
+			MaxPartsInTotal:                                &wrappers.Int64Value{Value: 100000},
+			MaxNumberOfMergesWithTtlInPool:                 &wrappers.Int64Value{Value: 1},
+			CleanupDelayPeriod:                             &wrappers.Int64Value{Value: 120},
+			NumberOfFreeEntriesInPoolToExecuteMutation:     &wrappers.Int64Value{Value: 15},
 		},
 		Kafka: &cfg.ClickhouseConfig_Kafka{
 			SecurityProtocol: cfg.ClickhouseConfig_Kafka_SECURITY_PROTOCOL_PLAINTEXT,
@@ -744,38 +966,39 @@ func TestAccMDBClickHouseCluster_CheckClickhouseConfig(t *testing.T) {
 				},
 			},
 		},
-		LogLevel:                    cfg.ClickhouseConfig_TRACE,
-		MaxConnections:              &wrappers.Int64Value{Value: 512},
-		MaxConcurrentQueries:        &wrappers.Int64Value{Value: 100},
-		KeepAliveTimeout:            &wrappers.Int64Value{Value: 123000},
-		UncompressedCacheSize:       &wrappers.Int64Value{Value: 8096},
-		MarkCacheSize:               &wrappers.Int64Value{Value: 8096},
-		MaxTableSizeToDrop:          &wrappers.Int64Value{Value: 1024},
-		MaxPartitionSizeToDrop:      &wrappers.Int64Value{Value: 1024},
-		Timezone:                    "UTC",
-		GeobaseUri:                  "",
-		QueryLogRetentionSize:       &wrappers.Int64Value{Value: 1024},
-		QueryLogRetentionTime:       &wrappers.Int64Value{Value: 123000},
-		QueryThreadLogEnabled:       &wrappers.BoolValue{Value: false},
-		QueryThreadLogRetentionSize: &wrappers.Int64Value{Value: 1024},
-		QueryThreadLogRetentionTime: &wrappers.Int64Value{Value: 123000},
-		PartLogRetentionSize:        &wrappers.Int64Value{Value: 1024},
-		PartLogRetentionTime:        &wrappers.Int64Value{Value: 123000},
-		MetricLogEnabled:            &wrappers.BoolValue{Value: true},
-		MetricLogRetentionSize:      &wrappers.Int64Value{Value: 1024},
-		MetricLogRetentionTime:      &wrappers.Int64Value{Value: 123000},
-		TraceLogEnabled:             &wrappers.BoolValue{Value: true},
-		TraceLogRetentionSize:       &wrappers.Int64Value{Value: 1024},
-		TraceLogRetentionTime:       &wrappers.Int64Value{Value: 123000},
-		TextLogEnabled:              &wrappers.BoolValue{Value: true},
-		TextLogRetentionSize:        &wrappers.Int64Value{Value: 1024},
-		TextLogRetentionTime:        &wrappers.Int64Value{Value: 123000},
-		TextLogLevel:                cfg.ClickhouseConfig_WARNING,
-		BackgroundPoolSize:          &wrappers.Int64Value{Value: 16},
-		BackgroundSchedulePoolSize:  &wrappers.Int64Value{Value: 32},
-		BackgroundFetchesPoolSize:   &wrappers.Int64Value{Value: 8},
-		DefaultDatabase:             &wrappers.StringValue{Value: "default"},
-		TotalMemoryProfilerStep:     &wrappers.Int64Value{Value: 4194304},
+		LogLevel:                            cfg.ClickhouseConfig_TRACE,
+		MaxConnections:                      &wrappers.Int64Value{Value: 512},
+		MaxConcurrentQueries:                &wrappers.Int64Value{Value: 100},
+		KeepAliveTimeout:                    &wrappers.Int64Value{Value: 123000},
+		UncompressedCacheSize:               &wrappers.Int64Value{Value: 8096},
+		MarkCacheSize:                       &wrappers.Int64Value{Value: 8096},
+		MaxTableSizeToDrop:                  &wrappers.Int64Value{Value: 1024},
+		MaxPartitionSizeToDrop:              &wrappers.Int64Value{Value: 1024},
+		Timezone:                            "UTC",
+		GeobaseUri:                          "",
+		QueryLogRetentionSize:               &wrappers.Int64Value{Value: 1024},
+		QueryLogRetentionTime:               &wrappers.Int64Value{Value: 123000},
+		QueryThreadLogEnabled:               &wrappers.BoolValue{Value: false},
+		QueryThreadLogRetentionSize:         &wrappers.Int64Value{Value: 1024},
+		QueryThreadLogRetentionTime:         &wrappers.Int64Value{Value: 123000},
+		PartLogRetentionSize:                &wrappers.Int64Value{Value: 1024},
+		PartLogRetentionTime:                &wrappers.Int64Value{Value: 123000},
+		MetricLogEnabled:                    &wrappers.BoolValue{Value: true},
+		MetricLogRetentionSize:              &wrappers.Int64Value{Value: 1024},
+		MetricLogRetentionTime:              &wrappers.Int64Value{Value: 123000},
+		TraceLogEnabled:                     &wrappers.BoolValue{Value: true},
+		TraceLogRetentionSize:               &wrappers.Int64Value{Value: 1024},
+		TraceLogRetentionTime:               &wrappers.Int64Value{Value: 123000},
+		TextLogEnabled:                      &wrappers.BoolValue{Value: true},
+		TextLogRetentionSize:                &wrappers.Int64Value{Value: 1024},
+		TextLogRetentionTime:                &wrappers.Int64Value{Value: 123000},
+		TextLogLevel:                        cfg.ClickhouseConfig_WARNING,
+		BackgroundPoolSize:                  &wrappers.Int64Value{Value: 16},
+		BackgroundSchedulePoolSize:          &wrappers.Int64Value{Value: 32},
+		BackgroundFetchesPoolSize:           &wrappers.Int64Value{Value: 8},
+		DefaultDatabase:                     &wrappers.StringValue{Value: "default"},
+		TotalMemoryProfilerStep:             &wrappers.Int64Value{Value: 4194304},
+		TotalMemoryTrackerSampleProbability: &wrappers.DoubleValue{Value: 0.5},
 	}
 
 	configForSecondStep := &cfg.ClickhouseConfig{
@@ -790,6 +1013,16 @@ func TestAccMDBClickHouseCluster_CheckClickhouseConfig(t *testing.T) {
 			MinBytesForWidePart:                            &wrappers.Int64Value{Value: 512},
 			MinRowsForWidePart:                             &wrappers.Int64Value{Value: 16},
 			TtlOnlyDropParts:                               &wrappers.BoolValue{Value: true},
+			InactivePartsToDelayInsert:                     &wrappers.Int64Value{Value: 200},
+			InactivePartsToThrowInsert:                     &wrappers.Int64Value{Value: 220},
+			MaxBytesToMergeAtMaxSpaceInPool:                &wrappers.Int64Value{Value: 21474836480},
+			AllowRemoteFsZeroCopyReplication:               &wrappers.BoolValue{Value: false},
+			MergeWithTtlTimeout:                            &wrappers.Int64Value{Value: 200000},
+			MergeWithRecompressionTtlTimeout:               &wrappers.Int64Value{Value: 200000},
+			MaxPartsInTotal:                                &wrappers.Int64Value{Value: 150000},
+			MaxNumberOfMergesWithTtlInPool:                 &wrappers.Int64Value{Value: 2},
+			CleanupDelayPeriod:                             &wrappers.Int64Value{Value: 60},
+			NumberOfFreeEntriesInPoolToExecuteMutation:     &wrappers.Int64Value{Value: 8},
 		},
 		Kafka: &cfg.ClickhouseConfig_Kafka{
 			SecurityProtocol: cfg.ClickhouseConfig_Kafka_SECURITY_PROTOCOL_PLAINTEXT,
@@ -822,17 +1055,18 @@ func TestAccMDBClickHouseCluster_CheckClickhouseConfig(t *testing.T) {
 			Password: "rabbit_pass2",
 			Vhost:    "clickhouse",
 		},
+		// Not sorted by min_part_size on purpose: must import without diff.
 		Compression: []*cfg.ClickhouseConfig_Compression{
-			{
-				Method:           cfg.ClickhouseConfig_Compression_LZ4,
-				MinPartSize:      2024,
-				MinPartSizeRatio: 0.3,
-			},
 			{
 				Method:           cfg.ClickhouseConfig_Compression_ZSTD,
 				MinPartSize:      4048,
 				MinPartSizeRatio: 0.77,
 			},
+			{
+				Method:           cfg.ClickhouseConfig_Compression_LZ4,
+				MinPartSize:      2024,
+				MinPartSizeRatio: 0.3,
+			},
 		},
 		GraphiteRollup: []*cfg.ClickhouseConfig_GraphiteRollup{
 			{
@@ -861,6 +1095,24 @@ func TestAccMDBClickHouseCluster_CheckClickhouseConfig(t *testing.T) {
 								Age:       3000,
 								Precision: 7,
 							},
+							{
+								Age:       6000,
+								Precision: 14,
+							},
+						},
+					},
+					{
+						Regexp:   "def",
+						Function: "func4",
+						Retention: []*cfg.ClickhouseConfig_GraphiteRollup_Pattern_Retention{
+							{
+								Age:       1000,
+								Precision: 1,
+							},
+							{
+								Age:       2000,
+								Precision: 2,
+							},
 						},
 					},
 				},
@@ -881,24 +1133,25 @@ func TestAccMDBClickHouseCluster_CheckClickhouseConfig(t *testing.T) {
 		QueryThreadLogEnabled:       &wrappers.BoolValue{Value: true},
 		QueryThreadLogRetentionSize: &wrappers.Int64Value{Value: 2048},
 
-		QueryThreadLogRetentionTime: &wrappers.Int64Value{Value: 246000},
-		PartLogRetentionSize:        &wrappers.Int64Value{Value: 2048},
-		PartLogRetentionTime:        &wrappers.Int64Value{Value: 246000},
-		MetricLogEnabled:            &wrappers.BoolValue{Value: true},
-		MetricLogRetentionSize:      &wrappers.Int64Value{Value: 2048},
-		MetricLogRetentionTime:      &wrappers.Int64Value{Value: 246000},
-		TraceLogEnabled:             &wrappers.BoolValue{Value: true},
-		TraceLogRetentionSize:       &wrappers.Int64Value{Value: 2048},
-		TraceLogRetentionTime:       &wrappers.Int64Value{Value: 246000},
-		TextLogEnabled:              &wrappers.BoolValue{Value: true},
-		TextLogRetentionSize:        &wrappers.Int64Value{Value: 2048},
-		TextLogRetentionTime:        &wrappers.Int64Value{Value: 246000},
-		TextLogLevel:                cfg.ClickhouseConfig_ERROR,
-		BackgroundPoolSize:          &wrappers.Int64Value{Value: 32},
-		BackgroundSchedulePoolSize:  &wrappers.Int64Value{Value: 64},
-		BackgroundFetchesPoolSize:   &wrappers.Int64Value{Value: 16},
-		DefaultDatabase:             &wrappers.StringValue{Value: "new_default"},
-		TotalMemoryProfilerStep:     &wrappers.Int64Value{Value: 4194303},
+		QueryThreadLogRetentionTime:         &wrappers.Int64Value{Value: 246000},
+		PartLogRetentionSize:                &wrappers.Int64Value{Value: 2048},
+		PartLogRetentionTime:                &wrappers.Int64Value{Value: 246000},
+		MetricLogEnabled:                    &wrappers.BoolValue{Value: true},
+		MetricLogRetentionSize:              &wrappers.Int64Value{Value: 2048},
+		MetricLogRetentionTime:              &wrappers.Int64Value{Value: 246000},
+		TraceLogEnabled:                     &wrappers.BoolValue{Value: true},
+		TraceLogRetentionSize:               &wrappers.Int64Value{Value: 2048},
+		TraceLogRetentionTime:               &wrappers.Int64Value{Value: 246000},
+		TextLogEnabled:                      &wrappers.BoolValue{Value: true},
+		TextLogRetentionSize:                &wrappers.Int64Value{Value: 2048},
+		TextLogRetentionTime:                &wrappers.Int64Value{Value: 246000},
+		TextLogLevel:                        cfg.ClickhouseConfig_ERROR,
+		BackgroundPoolSize:                  &wrappers.Int64Value{Value: 32},
+		BackgroundSchedulePoolSize:          &wrappers.Int64Value{Value: 64},
+		BackgroundFetchesPoolSize:           &wrappers.Int64Value{Value: 16},
+		DefaultDatabase:                     &wrappers.StringValue{Value: "new_default"},
+		TotalMemoryProfilerStep:             &wrappers.Int64Value{Value: 4194303},
+		TotalMemoryTrackerSampleProbability: &wrappers.DoubleValue{Value: 0.25},
 	}
 
 	resource.Test(t, resource.TestCase{
@@ -946,6 +1199,7 @@ func TestAccMDBClickHouseCluster_CheckClickhouseConfig(t *testing.T) {
 					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.background_fetches_pool_size", "8"),
 					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.default_database", "default"),
 					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.total_memory_profiler_step", "4194304"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.total_memory_tracker_sample_probability", "0.5"),
 
 					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.replicated_deduplication_window", "1000"),
 					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.replicated_deduplication_window_seconds", "1000"),
@@ -957,6 +1211,16 @@ func TestAccMDBClickHouseCluster_CheckClickhouseConfig(t *testing.T) {
 					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.min_bytes_for_wide_part", "0"),
 					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.min_rows_for_wide_part", "0"),
 					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.ttl_only_drop_parts", "false"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.inactive_parts_to_delay_insert", "101"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.inactive_parts_to_throw_insert", "110"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.max_bytes_to_merge_at_max_space_in_pool", "16106127360"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.allow_remote_fs_zero_copy_replication", "true"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.merge_with_ttl_timeout", "100000"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.merge_with_recompression_ttl_timeout", "100000"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.max_parts_in_total", "100000"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.max_number_of_merges_with_ttl_in_pool", "1"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.cleanup_delay_period", "120"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.number_of_free_entries_in_pool_to_execute_mutation", "15"),
 
 					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.kafka.0.security_protocol", "SECURITY_PROTOCOL_PLAINTEXT"),
 					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.kafka.0.sasl_mechanism", "SASL_MECHANISM_GSSAPI"),
@@ -1019,6 +1283,7 @@ func TestAccMDBClickHouseCluster_CheckClickhouseConfig(t *testing.T) {
 					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.background_fetches_pool_size", "16"),
 					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.default_database", "new_default"),
 					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.total_memory_profiler_step", "4194303"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.total_memory_tracker_sample_probability", "0.25"),
 
 					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.replicated_deduplication_window", "100"),
 					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.replicated_deduplication_window_seconds", "604800"),
@@ -1030,6 +1295,16 @@ func TestAccMDBClickHouseCluster_CheckClickhouseConfig(t *testing.T) {
 					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.min_bytes_for_wide_part", "512"),
 					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.min_rows_for_wide_part", "16"),
 					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.ttl_only_drop_parts", "true"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.inactive_parts_to_delay_insert", "200"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.inactive_parts_to_throw_insert", "220"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.max_bytes_to_merge_at_max_space_in_pool", "21474836480"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.allow_remote_fs_zero_copy_replication", "false"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.merge_with_ttl_timeout", "200000"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.merge_with_recompression_ttl_timeout", "200000"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.max_parts_in_total", "150000"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.max_number_of_merges_with_ttl_in_pool", "2"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.cleanup_delay_period", "60"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.merge_tree.0.number_of_free_entries_in_pool_to_execute_mutation", "8"),
 
 					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.kafka.0.security_protocol", "SECURITY_PROTOCOL_PLAINTEXT"),
 					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.kafka.0.sasl_mechanism", "SASL_MECHANISM_GSSAPI"),
@@ -1055,6 +1330,14 @@ func TestAccMDBClickHouseCluster_CheckClickhouseConfig(t *testing.T) {
 
 					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.compression.#", "2"),
 
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.graphite_rollup.#", "2"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.graphite_rollup.1.pattern.#", "2"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.graphite_rollup.1.pattern.0.retention.#", "2"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.graphite_rollup.1.pattern.1.regexp", "def"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.graphite_rollup.1.pattern.1.function", "func4"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.graphite_rollup.1.pattern.1.retention.1.age", "2000"),
+					resource.TestCheckResourceAttr(chResource, "clickhouse.0.config.0.graphite_rollup.1.pattern.1.retention.1.precision", "2"),
+
 					testAccCheckCreatedAtAttr(chResource)),
 			},
 			mdbClickHouseClusterImportStep(chResource),
@@ -1144,20 +1427,27 @@ func testAccCheckMDBClickHouseClusterHasShards(r *clickhouse.Cluster, shards []s
 	return func(s *terraform.State) error {
 		config := testAccProvider.Meta().(*Config)
 
-		resp, err := config.sdk.MDB().Clickhouse().Cluster().ListShards(context.Background(), &clickhouse.ListClusterShardsRequest{
-			ClusterId: r.Id,
-			PageSize:  defaultMDBPageSize,
+		clusterShards, err := listMDBPages(context.Background(), func(ctx context.Context, pageToken string) ([]*clickhouse.Shard, string, error) {
+			resp, err := config.sdk.MDB().Clickhouse().Cluster().ListShards(ctx, &clickhouse.ListClusterShardsRequest{
+				ClusterId: r.Id,
+				PageSize:  defaultMDBPageSize,
+				PageToken: pageToken,
+			})
+			if err != nil {
+				return nil, "", err
+			}
+			return resp.Shards, resp.NextPageToken, nil
 		})
 		if err != nil {
 			return err
 		}
 
-		if len(resp.Shards) != len(shards) {
-			return fmt.Errorf("Expected %d shards, got %d", len(shards), len(resp.Shards))
+		if len(clusterShards) != len(shards) {
+			return fmt.Errorf("Expected %d shards, got %d", len(shards), len(clusterShards))
 		}
 		for _, s := range shards {
 			found := false
-			for _, rs := range resp.Shards {
+			for _, rs := range clusterShards {
 				if s == rs.Name {
 					found = true
 				}
@@ -1219,6 +1509,80 @@ func testAccCheckMDBClickHouseZooKeeperSubclusterHasResources(r *clickhouse.Clus
 	}
 }
 
+// testAccCheckMDBClickHouseClusterUserNotDeleted checks that the user was never dropped
+// (and then re-created) during the cluster updates.
+func testAccCheckMDBClickHouseClusterUserNotDeleted(r string, userName string) resource.TestCheckFunc {
+	return testAccCheckMDBClickHouseClusterOperations(r, func(op *operation.Operation) error {
+		metadata := &clickhouse.DeleteUserMetadata{}
+		if !op.GetMetadata().MessageIs(metadata) {
+			return nil
+		}
+		if err := op.GetMetadata().UnmarshalTo(metadata); err != nil {
+			return err
+		}
+		if metadata.UserName == userName {
+			return fmt.Errorf("ClickHouse user %q was deleted by operation %s", userName, op.Id)
+		}
+		return nil
+	})
+}
+
+// testAccCheckMDBClickHouseClusterDatabaseNotDeleted checks that the database was never dropped
+// (and then re-created) during the cluster updates.
+func testAccCheckMDBClickHouseClusterDatabaseNotDeleted(r string, dbName string) resource.TestCheckFunc {
+	return testAccCheckMDBClickHouseClusterOperations(r, func(op *operation.Operation) error {
+		metadata := &clickhouse.DeleteDatabaseMetadata{}
+		if !op.GetMetadata().MessageIs(metadata) {
+			return nil
+		}
+		if err := op.GetMetadata().UnmarshalTo(metadata); err != nil {
+			return err
+		}
+		if metadata.DatabaseName == dbName {
+			return fmt.Errorf("ClickHouse database %q was deleted by operation %s", dbName, op.Id)
+		}
+		return nil
+	})
+}
+
+// testAccCheckMDBClickHouseClusterOperations calls check for every operation of the cluster.
+func testAccCheckMDBClickHouseClusterOperations(r string, check func(op *operation.Operation) error) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[r]
+		if !ok {
+			return fmt.Errorf("Not found: %s", r)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		config := testAccProvider.Meta().(*Config)
+
+		operations, err := listMDBPages(context.Background(), func(ctx context.Context, pageToken string) ([]*operation.Operation, string, error) {
+			resp, err := config.sdk.MDB().Clickhouse().Cluster().ListOperations(ctx, &clickhouse.ListClusterOperationsRequest{
+				ClusterId: rs.Primary.ID,
+				PageSize:  defaultMDBPageSize,
+				PageToken: pageToken,
+			})
+			if err != nil {
+				return nil, "", err
+			}
+			return resp.Operations, resp.NextPageToken, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, op := range operations {
+			if err := check(op); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
 func testAccCheckMDBClickHouseClusterHasUsers(r string, perms map[string][]string, settings map[string]map[string]interface{},
 	quotas map[string][]map[string]interface{}) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
@@ -1233,14 +1597,20 @@ func testAccCheckMDBClickHouseClusterHasUsers(r string, perms map[string][]strin
 
 		config := testAccProvider.Meta().(*Config)
 
-		resp, err := config.sdk.MDB().Clickhouse().User().List(context.Background(), &clickhouse.ListUsersRequest{
-			ClusterId: rs.Primary.ID,
-			PageSize:  defaultMDBPageSize,
+		users, err := listMDBPages(context.Background(), func(ctx context.Context, pageToken string) ([]*clickhouse.User, string, error) {
+			resp, err := config.sdk.MDB().Clickhouse().User().List(ctx, &clickhouse.ListUsersRequest{
+				ClusterId: rs.Primary.ID,
+				PageSize:  defaultMDBPageSize,
+				PageToken: pageToken,
+			})
+			if err != nil {
+				return nil, "", err
+			}
+			return resp.Users, resp.NextPageToken, nil
 		})
 		if err != nil {
 			return err
 		}
-		users := resp.Users
 
 		if len(users) != len(perms) {
 			return fmt.Errorf("Expected %d users, found %d", len(perms), len(users))
@@ -1378,6 +1748,19 @@ func testAccCheckMDBClickHouseClusterContainsLabel(r *clickhouse.Cluster, key st
 		if v != value {
 			return fmt.Errorf("Incorrect label value for key '%s': expected '%s' but found '%s'", key, value, v)
 		}
+
+		// Labels in state must match the ones of the actual cluster.
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != "yandex_mdb_clickhouse_cluster" || rs.Primary.ID != r.Id {
+				continue
+			}
+			if sv := rs.Primary.Attributes["labels."+key]; sv != value {
+				return fmt.Errorf("Incorrect label value in state for key '%s': expected '%s' but found '%s'", key, value, sv)
+			}
+			if sc, rc := rs.Primary.Attributes["labels.%"], strconv.Itoa(len(r.Labels)); sc != rc {
+				return fmt.Errorf("Incorrect number of labels in state: expected %s but found %s", rc, sc)
+			}
+		}
 		return nil
 	}
 }
@@ -1601,7 +1984,6 @@ resource "yandex_mdb_clickhouse_cluster" "foo" {
   environment    = "%s"
   version        = "%s"
   network_id     = "${yandex_vpc_network.mdb-ch-test-net.id}"
-  admin_password = "strong_password"
 
   labels = {
     test_key = "test_value"
@@ -1731,6 +2113,11 @@ resource "yandex_mdb_clickhouse_cluster" "foo" {
   security_group_ids = ["${yandex_vpc_security_group.mdb-ch-test-sg-x.id}"]
   service_account_id = "${yandex_iam_service_account.sa.id}"
 
+  backup_window_start {
+    hours   = 3
+    minutes = 15
+  }
+
   maintenance_window {
     %s
   }
@@ -1750,6 +2137,11 @@ resource "yandex_mdb_clickhouse_cluster" "foo" {
 }
 
 func testAccMDBClickHouseClusterConfigUpdated(name, desc, bucket string, randInt int) string {
+	return testAccMDBClickHouseClusterConfigUpdatedWithSecurityGroups(name, desc, bucket, randInt,
+		`"${yandex_vpc_security_group.mdb-ch-test-sg-x.id}", "${yandex_vpc_security_group.mdb-ch-test-sg-y.id}"`)
+}
+
+func testAccMDBClickHouseClusterConfigUpdatedWithSecurityGroups(name, desc, bucket string, randInt int, securityGroupIds string) string {
 	return fmt.Sprintf(clickHouseVPCDependencies+clickhouseObjectStorageDependencies(bucket, randInt)+`
 resource "yandex_mdb_clickhouse_cluster" "foo" {
   name           = "%s"
@@ -1757,7 +2149,6 @@ resource "yandex_mdb_clickhouse_cluster" "foo" {
   environment    = "PRESTABLE"
   version        = "%s"
   network_id     = "${yandex_vpc_network.mdb-ch-test-net.id}"
-  admin_password = "strong_password"
 
   labels = {
     new_key = "new_value"
@@ -2002,7 +2393,13 @@ resource "yandex_mdb_clickhouse_cluster" "foo" {
     subnet_id = "${yandex_vpc_subnet.mdb-ch-test-subnet-a.id}"
   }
 
-  security_group_ids = ["${yandex_vpc_security_group.mdb-ch-test-sg-x.id}", "${yandex_vpc_security_group.mdb-ch-test-sg-y.id}"]
+  security_group_ids = [%s]
+  service_account_id = "${yandex_iam_service_account.sa.id}"
+
+  backup_window_start {
+    hours   = 4
+    minutes = 30
+  }
 
   format_schema {
     name = "test_schema"
@@ -2026,7 +2423,7 @@ resource "yandex_mdb_clickhouse_cluster" "foo" {
 
   deletion_protection = false
 }
-`, name, desc, chVersion, StorageEndpointUrl, StorageEndpointUrl)
+`, name, desc, chVersion, securityGroupIds, StorageEndpointUrl, StorageEndpointUrl)
 }
 
 func testAccMDBClickHouseClusterConfigUser(name, desc, bucket string, randInt int) string {
@@ -2287,6 +2684,7 @@ resource "yandex_mdb_clickhouse_cluster" "foo" {
   }
 
   security_group_ids = ["${yandex_vpc_security_group.mdb-ch-test-sg-x.id}"]
+  service_account_id = "${yandex_iam_service_account.sa.id}"
 
   format_schema {
     name = "test_schema"
@@ -2399,7 +2797,6 @@ resource "yandex_mdb_clickhouse_cluster" "bar" {
   description    = "ClickHouse Sharded Cluster Terraform Test"
   environment    = "PRESTABLE"
   network_id     = yandex_vpc_network.mdb-ch-test-net.id
-  admin_password = "strong_password"
 
   clickhouse {
     resources {
@@ -2428,6 +2825,10 @@ resource "yandex_mdb_clickhouse_cluster" "bar" {
       resource_preset_id = "s3-c4-m16"
       disk_type_id       = "network-ssd"
       disk_size          = %d
+    }
+	config {
+      mark_cache_size        = 10737418240
+      max_concurrent_queries = 200
     }
   }
 
@@ -2484,7 +2885,6 @@ resource "yandex_mdb_clickhouse_cluster" "bar" {
   description    = "ClickHouse Sharded Cluster Terraform Test"
   environment    = "PRESTABLE"
   network_id     = yandex_vpc_network.mdb-ch-test-net.id
-  admin_password = "strong_password"
 
   clickhouse {
     resources {
@@ -2602,7 +3002,6 @@ resource "yandex_mdb_clickhouse_cluster" "cloud" {
   description             = "%s"
   environment             = "PRESTABLE"
   network_id              = "${yandex_vpc_network.mdb-ch-test-net.id}"
-  admin_password          = "strong_password"
   version                 = "%s"
 
   labels = {
@@ -2746,7 +3145,6 @@ resource "yandex_mdb_clickhouse_cluster" "cloud" {
   description             = "%s"
   environment             = "PRESTABLE"
   network_id              = "${yandex_vpc_network.mdb-ch-test-net.id}"
-  admin_password          = "strong_password"
   version                 = "%s"
 
   labels = {
@@ -2925,6 +3323,148 @@ resource "yandex_mdb_clickhouse_cluster" "keeper" {
 `, name, desc)
 }
 
+func testAccMDBClickHouseClusterConfigFolder(name, desc, folderID string) string {
+	return fmt.Sprintf(clickHouseVPCDependencies+`
+resource "yandex_mdb_clickhouse_cluster" "foo" {
+  name           = "%s"
+  description    = "%s"
+  environment    = "PRESTABLE"
+  network_id     = "${yandex_vpc_network.mdb-ch-test-net.id}"
+  folder_id      = "%s"
+
+  clickhouse {
+    resources {
+      resource_preset_id = "s2.micro"
+      disk_type_id       = "network-ssd"
+      disk_size          = 16
+    }
+  }
+
+  host {
+    type      = "CLICKHOUSE"
+    zone      = "ru-central1-a"
+    subnet_id = "${yandex_vpc_subnet.mdb-ch-test-subnet-a.id}"
+  }
+}
+`, name, desc, folderID)
+}
+
+func testAccMDBClickHouseClusterConfigForceDestroy(name, desc string) string {
+	return fmt.Sprintf(clickHouseVPCDependencies+`
+resource "yandex_mdb_clickhouse_cluster" "foo" {
+  name                = "%s"
+  description         = "%s"
+  environment         = "PRESTABLE"
+  network_id          = "${yandex_vpc_network.mdb-ch-test-net.id}"
+  deletion_protection = true
+  force_destroy       = true
+
+  clickhouse {
+    resources {
+      resource_preset_id = "s2.micro"
+      disk_type_id       = "network-ssd"
+      disk_size          = 16
+    }
+  }
+
+  host {
+    type      = "CLICKHOUSE"
+    zone      = "ru-central1-a"
+    subnet_id = "${yandex_vpc_subnet.mdb-ch-test-subnet-a.id}"
+  }
+}
+`, name, desc)
+}
+
+func testAccMDBClickHouseClusterConfigLabels(name, desc, labelValue string) string {
+	return fmt.Sprintf(clickHouseVPCDependencies+`
+resource "yandex_mdb_clickhouse_cluster" "foo" {
+  name           = "%s"
+  description    = "%s"
+  environment    = "PRESTABLE"
+  network_id     = "${yandex_vpc_network.mdb-ch-test-net.id}"
+
+  labels = {
+    test_key = "%s"
+  }
+
+  clickhouse {
+    resources {
+      resource_preset_id = "s2.micro"
+      disk_type_id       = "network-ssd"
+      disk_size          = 16
+    }
+  }
+
+  host {
+    type      = "CLICKHOUSE"
+    zone      = "ru-central1-a"
+    subnet_id = "${yandex_vpc_subnet.mdb-ch-test-subnet-a.id}"
+  }
+}
+`, name, desc, labelValue)
+}
+
+func testAccMDBClickHouseClusterConfigRescheduleMaintenance(name, desc, reschedule string) string {
+	return fmt.Sprintf(clickHouseVPCDependencies+`
+resource "yandex_mdb_clickhouse_cluster" "foo" {
+  name           = "%s"
+  description    = "%s"
+  environment    = "PRESTABLE"
+  network_id     = "${yandex_vpc_network.mdb-ch-test-net.id}"
+
+  maintenance_window {
+    type = "WEEKLY"
+    day  = "SAT"
+    hour = 12
+  }
+%s
+  clickhouse {
+    resources {
+      resource_preset_id = "s2.micro"
+      disk_type_id       = "network-ssd"
+      disk_size          = 16
+    }
+  }
+
+  host {
+    type      = "CLICKHOUSE"
+    zone      = "ru-central1-a"
+    subnet_id = "${yandex_vpc_subnet.mdb-ch-test-subnet-a.id}"
+  }
+}
+`, name, desc, reschedule)
+}
+
+func testAccMDBClickHouseClusterConfigRestore(name, desc, backupID string) string {
+	return fmt.Sprintf(clickHouseVPCDependencies+`
+resource "yandex_mdb_clickhouse_cluster" "foo" {
+  name           = "%s"
+  description    = "%s"
+  environment    = "PRESTABLE"
+  network_id     = "${yandex_vpc_network.mdb-ch-test-net.id}"
+
+  restore {
+    backup_id = "%s"
+  }
+
+  clickhouse {
+    resources {
+      resource_preset_id = "s2.micro"
+      disk_type_id       = "network-ssd"
+      disk_size          = 16
+    }
+  }
+
+  host {
+    type      = "CLICKHOUSE"
+    zone      = "ru-central1-a"
+    subnet_id = "${yandex_vpc_subnet.mdb-ch-test-subnet-a.id}"
+  }
+}
+`, name, desc, backupID)
+}
+
 func testAccMDBClickHouseClusterResources(name, desc, bucket string, randInt int, version string, resources *clickhouse.Resources) string {
 	return fmt.Sprintf(clickHouseVPCDependencies+clickhouseObjectStorageDependencies(bucket, randInt)+`
 resource "yandex_mdb_clickhouse_cluster" "foo"{
@@ -2932,7 +3472,6 @@ resource "yandex_mdb_clickhouse_cluster" "foo"{
   description    = "%s"
   environment    = "PRESTABLE"
   network_id     = "${yandex_vpc_network.mdb-ch-test-net.id}"
-  admin_password = "strong_password"
   version        = "%s"
 
   labels = {
@@ -2960,7 +3499,6 @@ resource "yandex_mdb_clickhouse_cluster" "foo"{
   description    = "%s"
   environment    = "PRESTABLE"
   network_id     = "${yandex_vpc_network.mdb-ch-test-net.id}"
-  admin_password = "strong_password"
   version        = "%s"
 
   labels = {
@@ -3035,6 +3573,7 @@ config {
 		background_fetches_pool_size 	= %d
 		default_database 				= "%s"
 		total_memory_profiler_step 		= %d
+		total_memory_tracker_sample_probability = %g
 
 		# merge_tree
 		%s
@@ -3087,6 +3626,7 @@ config {
 		config.BackgroundFetchesPoolSize.GetValue(),
 		config.DefaultDatabase.GetValue(),
 		config.TotalMemoryProfilerStep.GetValue(),
+		config.TotalMemoryTrackerSampleProbability.GetValue(),
 		buildConfigForMergeTree(config.MergeTree),
 		buildConfigForKafka(config.Kafka),
 		buildConfigForKafkaTopics(config.KafkaTopics),
@@ -3106,9 +3646,19 @@ merge_tree {
 			max_replicated_merges_in_queue                            = %d
 			number_of_free_entries_in_pool_to_lower_max_size_of_merge = %d
 			max_bytes_to_merge_at_min_space_in_pool                   = %d
-			min_bytes_for_wide_part 								  = %d
-            min_rows_for_wide_part 									  = %d
-            ttl_only_drop_parts 									  = %t
+			min_bytes_for_wide_part                                   = %d
+			min_rows_for_wide_part                                    = %d
+			ttl_only_drop_parts                                       = %t
+			inactive_parts_to_delay_insert                            = %d
+			inactive_parts_to_throw_insert                            = %d
+			max_bytes_to_merge_at_max_space_in_pool                   = %d
+			allow_remote_fs_zero_copy_replication                     = %t
+			merge_with_ttl_timeout                                    = %d
+			merge_with_recompression_ttl_timeout                      = %d
+			max_parts_in_total                                        = %d
+			max_number_of_merges_with_ttl_in_pool                     = %d
+			cleanup_delay_period                                      = %d
+			number_of_free_entries_in_pool_to_execute_mutation        = %d
 		}
 `,
 		mergeTree.ReplicatedDeduplicationWindow.GetValue(),
@@ -3120,7 +3670,17 @@ merge_tree {
 		mergeTree.MaxBytesToMergeAtMinSpaceInPool.GetValue(),
 		mergeTree.MinBytesForWidePart.GetValue(),
 		mergeTree.MinRowsForWidePart.GetValue(),
-		mergeTree.TtlOnlyDropParts.GetValue())
+		mergeTree.TtlOnlyDropParts.GetValue(),
+		mergeTree.InactivePartsToDelayInsert.GetValue(),
+		mergeTree.InactivePartsToThrowInsert.GetValue(),
+		mergeTree.MaxBytesToMergeAtMaxSpaceInPool.GetValue(),
+		mergeTree.AllowRemoteFsZeroCopyReplication.GetValue(),
+		mergeTree.MergeWithTtlTimeout.GetValue(),
+		mergeTree.MergeWithRecompressionTtlTimeout.GetValue(),
+		mergeTree.MaxPartsInTotal.GetValue(),
+		mergeTree.MaxNumberOfMergesWithTtlInPool.GetValue(),
+		mergeTree.CleanupDelayPeriod.GetValue(),
+		mergeTree.NumberOfFreeEntriesInPoolToExecuteMutation.GetValue())
 }
 
 func buildConfigForKafka(kafka *cfg.ClickhouseConfig_Kafka) string {
@@ -3194,24 +3754,34 @@ compression {
 func buildGraphiteRollup(graphiteRollup []*cfg.ClickhouseConfig_GraphiteRollup) string {
 	var result string
 	for _, v := range graphiteRollup {
-		result += fmt.Sprintf(`
-graphite_rollup {
-        name = "%s"
-        pattern {
-          regexp   = "%s"
-          function = "%s"
+		var patterns string
+		for _, p := range v.Patterns {
+			var retentions string
+			for _, r := range p.Retention {
+				retentions += fmt.Sprintf(`
           retention {
             age       = %d
             precision = %d
-          }
-        }
+          }`,
+					r.Age,
+					r.Precision)
+			}
+			patterns += fmt.Sprintf(`
+        pattern {
+          regexp   = "%s"
+          function = "%s"%s
+        }`,
+				p.Regexp,
+				p.Function,
+				retentions)
+		}
+		result += fmt.Sprintf(`
+graphite_rollup {
+        name = "%s"%s
 }
 `,
 			v.Name,
-			v.Patterns[0].Regexp,
-			v.Patterns[0].Function,
-			v.Patterns[0].Retention[0].Age,
-			v.Patterns[0].Retention[0].Precision)
+			patterns)
 	}
 	return result
 }
@@ -3228,7 +3798,6 @@ resource "yandex_mdb_clickhouse_cluster" "foo" {
   environment    = "%s"
   version        = "%s"
   network_id     = "${yandex_vpc_network.mdb-ch-test-net.id}"
-  admin_password = "strong_password"
 
   labels = {
     test_key = "test_value"
@@ -3298,7 +3867,6 @@ resource "yandex_mdb_clickhouse_cluster" "foo" {
   environment    = "%s"
   version        = "%s"
   network_id     = "${yandex_vpc_network.mdb-ch-test-net.id}"
-  admin_password = "strong_password"
 
   labels = {
     test_key = "test_value"
@@ -3355,3 +3923,664 @@ resource "yandex_mdb_clickhouse_cluster" "foo" {
 }
 `, name, desc, environment, chVersion)
 }
+
+func TestClickHouseClusterUpdateRequest_RotateRabbitmqPassword(t *testing.T) {
+	rawInitial := map[string]interface{}{
+		"name":        "clickhouse",
+		"network_id":  "network",
+		"environment": "PRESTABLE",
+		"clickhouse": []interface{}{map[string]interface{}{
+			"config": []interface{}{map[string]interface{}{
+				"rabbitmq": []interface{}{map[string]interface{}{
+					"username": "rabbit_user",
+					"password": "rabbit_pass",
+					"vhost":    "clickhouse",
+				}},
+			}},
+		}},
+		"host": []interface{}{map[string]interface{}{
+			"type": "CLICKHOUSE",
+			"zone": "ru-central1-a",
+		}},
+	}
+	diffAttributes := map[string]*terraform.ResourceAttrDiff{
+		"clickhouse.0.config.0.rabbitmq.0.password": {Old: "rabbit_pass", New: "rabbit_pass2"},
+	}
+	d := createClickHouseResourceData(t, rawInitial, diffAttributes)
+
+	paths := getClickHouseClusterUpdatePaths(d)
+	if !reflect.DeepEqual(paths, []string{"config_spec.clickhouse.config.rabbitmq"}) {
+		t.Fatalf("unexpected update mask: %v", paths)
+	}
+
+	req, err := getClickHouseClusterUpdateRequest(d)
+	if err != nil {
+		t.Fatalf("failed to build update request: %s", err)
+	}
+	rabbitmq := req.GetConfigSpec().GetClickhouse().GetConfig().GetRabbitmq()
+	if rabbitmq.GetPassword() != "rabbit_pass2" || rabbitmq.GetUsername() != "rabbit_user" || rabbitmq.GetVhost() != "clickhouse" {
+		t.Fatalf("unexpected rabbitmq settings in update request: %v", rabbitmq)
+	}
+}
+
+func TestClickHouseClusterUpdateRequest_ConfigChangeWithRabbitmq(t *testing.T) {
+	rawInitial := map[string]interface{}{
+		"name":        "clickhouse",
+		"network_id":  "network",
+		"environment": "PRESTABLE",
+		"clickhouse": []interface{}{map[string]interface{}{
+			"config": []interface{}{map[string]interface{}{
+				"max_connections": 100,
+				"rabbitmq": []interface{}{map[string]interface{}{
+					"password": "rabbit_pass",
+				}},
+			}},
+		}},
+		"host": []interface{}{map[string]interface{}{
+			"type": "CLICKHOUSE",
+			"zone": "ru-central1-a",
+		}},
+	}
+	diffAttributes := map[string]*terraform.ResourceAttrDiff{
+		"clickhouse.0.config.0.max_connections":     {Old: "100", New: "200"},
+		"clickhouse.0.config.0.rabbitmq.0.password": {Old: "rabbit_pass", New: "rabbit_pass2"},
+	}
+	d := createClickHouseResourceData(t, rawInitial, diffAttributes)
+
+	paths := getClickHouseClusterUpdatePaths(d)
+	if !reflect.DeepEqual(paths, []string{"config_spec.clickhouse"}) {
+		t.Fatalf("unexpected update mask: %v", paths)
+	}
+}
+
+func TestClickHouseClusterUpdateRequest_VersionWithResources(t *testing.T) {
+	rawInitial := map[string]interface{}{
+		"name":        "clickhouse",
+		"network_id":  "network",
+		"environment": "PRESTABLE",
+		"version":     "23.3",
+		"clickhouse": []interface{}{map[string]interface{}{
+			"resources": []interface{}{map[string]interface{}{
+				"resource_preset_id": "s2.micro",
+				"disk_type_id":       "network-ssd",
+				"disk_size":          10,
+			}},
+		}},
+		"host": []interface{}{map[string]interface{}{
+			"type": "CLICKHOUSE",
+			"zone": "ru-central1-a",
+		}},
+	}
+	diffAttributes := map[string]*terraform.ResourceAttrDiff{
+		"version": {Old: "23.3", New: "23.8"},
+		"clickhouse.0.resources.0.resource_preset_id": {Old: "s2.micro", New: "s2.small"},
+		"clickhouse.0.resources.0.disk_size":          {Old: "10", New: "16"},
+	}
+	d := createClickHouseResourceData(t, rawInitial, diffAttributes)
+
+	paths := getClickHouseClusterUpdatePaths(d)
+	require.ElementsMatch(t, []string{"config_spec.version", "config_spec.clickhouse.resources"}, paths)
+
+	req, err := getClickHouseClusterUpdateRequest(d)
+	if err != nil {
+		t.Fatalf("failed to build update request: %s", err)
+	}
+	require.Equal(t, "23.8", req.GetConfigSpec().GetVersion())
+	require.Equal(t, "s2.small", req.GetConfigSpec().GetClickhouse().GetResources().GetResourcePresetId())
+	require.Equal(t, toBytes(16), req.GetConfigSpec().GetClickhouse().GetResources().GetDiskSize())
+}
+
+// clickHouseDiffTestConfig returns a raw config of a cluster with a single ClickHouse host
+// for tests running r.Diff, top-level attributes of the config are replaced by overrides.
+func clickHouseDiffTestConfig(overrides map[string]interface{}) map[string]interface{} {
+	raw := map[string]interface{}{
+		"name":        "test",
+		"environment": "PRESTABLE",
+		"network_id":  "network",
+		"clickhouse":  clickHouseDiffTestResources("network-ssd"),
+		"host": []interface{}{map[string]interface{}{
+			"type": "CLICKHOUSE",
+			"zone": "ru-central1-a",
+		}},
+	}
+	for k, v := range overrides {
+		raw[k] = v
+	}
+	return raw
+}
+
+func clickHouseDiffTestResources(diskTypeID string) []interface{} {
+	return []interface{}{map[string]interface{}{
+		"resources": []interface{}{map[string]interface{}{
+			"resource_preset_id": "s2.micro",
+			"disk_size":          10,
+			"disk_type_id":       diskTypeID,
+		}},
+	}}
+}
+
+func TestClickHouseClusterDiff_SecurityGroupIdsOrder(t *testing.T) {
+	clickHouseWithSecurityGroups := func(securityGroupIds ...interface{}) map[string]interface{} {
+		return clickHouseDiffTestConfig(map[string]interface{}{"security_group_ids": securityGroupIds})
+	}
+	hasSecurityGroupIdsDiff := func(diff *terraform.InstanceDiff) bool {
+		if diff == nil {
+			return false
+		}
+		for k := range diff.Attributes {
+			if strings.HasPrefix(k, "security_group_ids.") {
+				return true
+			}
+		}
+		return false
+	}
+	r := resourceYandexMDBClickHouseCluster()
+	initial := schema.TestResourceDataRaw(t, r.Schema, clickHouseWithSecurityGroups("sg-x", "sg-y"))
+	initial.SetId("cluster")
+
+	diff, err := r.Diff(context.Background(), initial.State(), terraform.NewResourceConfigRaw(clickHouseWithSecurityGroups("sg-y", "sg-x")), nil)
+	require.NoError(t, err)
+	require.False(t, hasSecurityGroupIdsDiff(diff), "reordering security groups must not produce a diff")
+
+	diff, err = r.Diff(context.Background(), initial.State(), terraform.NewResourceConfigRaw(clickHouseWithSecurityGroups("sg-y", "sg-z")), nil)
+	require.NoError(t, err)
+	require.True(t, hasSecurityGroupIdsDiff(diff), "changing security groups must produce a diff")
+}
+
+func TestClickHouseClusterValidate_EmptySecurityGroupId(t *testing.T) {
+	raw := clickHouseDiffTestConfig(map[string]interface{}{"security_group_ids": []interface{}{"sg-x", ""}})
+	r := resourceYandexMDBClickHouseCluster()
+
+	diags := r.Validate(terraform.NewResourceConfigRaw(raw))
+	require.True(t, diags.HasError(), "empty security group id must be rejected")
+
+	raw["security_group_ids"] = []interface{}{"sg-x"}
+	diags = r.Validate(terraform.NewResourceConfigRaw(raw))
+	require.False(t, diags.HasError(), "unexpected validation error: %v", diags)
+}
+
+func TestPrepareUpdateClickHouseUserRequest_OnlyConfiguredSettings(t *testing.T) {
+	r := resourceYandexMDBClickHouseCluster()
+	raw := map[string]interface{}{
+		"name":        "test",
+		"environment": "PRESTABLE",
+		"network_id":  "network",
+		"user": []interface{}{
+			map[string]interface{}{
+				"name":     "john",
+				"password": "password",
+				"settings": []interface{}{
+					map[string]interface{}{
+						"max_threads": 10,
+						"allow_ddl":   false,
+					},
+				},
+			},
+		},
+	}
+
+	initial := schema.TestResourceDataRaw(t, r.Schema, raw)
+	initial.SetId("cluster")
+	state := initial.State()
+
+	rawJSON, err := json.Marshal(raw)
+	require.NoError(t, err)
+	state.RawConfig, err = ctyjson.Unmarshal(rawJSON, r.CoreConfigSchema().ImpliedType())
+	require.NoError(t, err)
+	d := r.Data(state)
+
+	users, err := expandClickHouseUserSpecs(d)
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+
+	req := prepareUpdateClickHouseUserRequest(d, users[0])
+	require.Equal(t, &clickhouse.UserSettings{
+		MaxThreads: &wrappers.Int64Value{Value: 10},
+		AllowDdl:   &wrappers.BoolValue{Value: false},
+	}, req.Settings)
+	require.Equal(t, []string{"password", "permissions", "quotas", "settings.allow_ddl", "settings.max_threads"}, req.UpdateMask.GetPaths())
+}
+
+// Unlike CreateResourceData, drops unknown computed values from the initial state,
+// there are too many of them in the ClickHouse schema.
+func createClickHouseResourceData(t *testing.T, rawInitialState map[string]interface{}, diffAttributes map[string]*terraform.ResourceAttrDiff) *schema.ResourceData {
+	t.Helper()
+	schemaObject := resourceYandexMDBClickHouseCluster().Schema
+	initial := schema.TestResourceDataRaw(t, schemaObject, rawInitialState)
+	initial.SetId("cluster")
+	state := initial.State()
+
+	resourceData, err := schema.InternalMap(schemaObject).Data(state, &terraform.InstanceDiff{Attributes: diffAttributes})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return resourceData
+}
+
+func TestClickHouseClusterCloudStorageValidation(t *testing.T) {
+	cloudStorage := resourceYandexMDBClickHouseCluster().Schema["cloud_storage"].Elem.(*schema.Resource).Schema
+
+	for _, moveFactor := range []float64{0, 0.5, 1} {
+		_, errs := cloudStorage["move_factor"].ValidateFunc(moveFactor, "move_factor")
+		require.Empty(t, errs, "move_factor %v", moveFactor)
+	}
+	for _, moveFactor := range []float64{-0.1, 1.1} {
+		_, errs := cloudStorage["move_factor"].ValidateFunc(moveFactor, "move_factor")
+		require.NotEmpty(t, errs, "move_factor %v", moveFactor)
+	}
+
+	_, errs := cloudStorage["data_cache_max_size"].ValidateFunc(0, "data_cache_max_size")
+	require.Empty(t, errs)
+	_, errs = cloudStorage["data_cache_max_size"].ValidateFunc(-1, "data_cache_max_size")
+	require.NotEmpty(t, errs)
+}
+
+func TestClickHouseClusterCloudStorageDiffCustomize(t *testing.T) {
+	clickHouseWithCloudStorage := func(cloudStorage map[string]interface{}) map[string]interface{} {
+		return clickHouseDiffTestConfig(map[string]interface{}{"cloud_storage": []interface{}{cloudStorage}})
+	}
+
+	tests := []struct {
+		name         string
+		cloudStorage map[string]interface{}
+		wantErr      string
+	}{
+		{
+			name:         "cache enabled with size",
+			cloudStorage: map[string]interface{}{"enabled": true, "data_cache_enabled": true, "data_cache_max_size": 1024},
+		},
+		{
+			name:         "cache disabled without size",
+			cloudStorage: map[string]interface{}{"enabled": true, "data_cache_enabled": false, "data_cache_max_size": 0},
+		},
+		{
+			name:         "cache disabled with size",
+			cloudStorage: map[string]interface{}{"enabled": true, "data_cache_enabled": false, "data_cache_max_size": 1024},
+			wantErr:      "data_cache_max_size must be 0 when cloud_storage.0.data_cache_enabled is false, got 1024",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resourceYandexMDBClickHouseCluster()
+			_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(clickHouseWithCloudStorage(tt.cloudStorage)), nil)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	r := resourceYandexMDBClickHouseCluster()
+	diags := r.Validate(terraform.NewResourceConfigRaw(clickHouseWithCloudStorage(map[string]interface{}{"enabled": true, "move_factor": 1.5})))
+	require.True(t, diags.HasError(), "move_factor 1.5 must be rejected")
+}
+
+func TestClickHouseClusterRescheduleMaintenanceDiffCustomize(t *testing.T) {
+	tests := []struct {
+		name       string
+		reschedule map[string]interface{}
+		wantErr    string
+	}{
+		{
+			name:       "next available window",
+			reschedule: map[string]interface{}{"reschedule_type": "NEXT_AVAILABLE_WINDOW"},
+		},
+		{
+			name:       "specific time with delayed_until",
+			reschedule: map[string]interface{}{"reschedule_type": "SPECIFIC_TIME", "delayed_until": "2023-08-01T12:00:00Z"},
+		},
+		{
+			name:       "specific time without delayed_until",
+			reschedule: map[string]interface{}{"reschedule_type": "SPECIFIC_TIME"},
+			wantErr:    "delayed_until should be set with SPECIFIC_TIME reschedule type",
+		},
+		{
+			name:       "immediate with delayed_until",
+			reschedule: map[string]interface{}{"reschedule_type": "IMMEDIATE", "delayed_until": "2023-08-01T12:00:00Z"},
+			wantErr:    "delayed_until should be omitted with IMMEDIATE reschedule type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resourceYandexMDBClickHouseCluster()
+			raw := clickHouseDiffTestConfig(map[string]interface{}{"reschedule_maintenance": []interface{}{tt.reschedule}})
+			_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), nil)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestClickHouseClusterDiskTypeDiffCustomize(t *testing.T) {
+	clickHouseWithDiskType := func(diskTypeID string) map[string]interface{} {
+		return clickHouseDiffTestConfig(map[string]interface{}{"clickhouse": clickHouseDiffTestResources(diskTypeID)})
+	}
+
+	tests := []struct {
+		name     string
+		from, to string
+		wantErr  bool
+	}{
+		{name: "hdd to ssd", from: "network-hdd", to: "network-ssd"},
+		{name: "ssd to hdd", from: "network-ssd", to: "network-hdd"},
+		{name: "same type", from: "local-ssd", to: "local-ssd"},
+		{name: "ssd to nonreplicated", from: "network-ssd", to: "network-ssd-nonreplicated", wantErr: true},
+		{name: "local to network", from: "local-ssd", to: "network-ssd", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resourceYandexMDBClickHouseCluster()
+			initial := schema.TestResourceDataRaw(t, r.Schema, clickHouseWithDiskType(tt.from))
+			initial.SetId("cluster")
+
+			_, err := r.Diff(context.Background(), initial.State(), terraform.NewResourceConfigRaw(clickHouseWithDiskType(tt.to)), nil)
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "clickhouse.0.resources.0.disk_type_id")
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestClickHouseClusterEnvironmentDiffCustomize(t *testing.T) {
+	clickHouseWithEnvironment := func(environment string, deletionProtection, forceDestroy bool) map[string]interface{} {
+		return clickHouseDiffTestConfig(map[string]interface{}{
+			"environment":         environment,
+			"deletion_protection": deletionProtection,
+			"force_destroy":       forceDestroy,
+		})
+	}
+
+	tests := []struct {
+		name               string
+		from, to           string
+		deletionProtection bool
+		forceDestroy       bool
+		wantErr            bool
+		wantRequiresNew    bool
+	}{
+		{name: "same environment", from: "PRESTABLE", to: "PRESTABLE", deletionProtection: true},
+		{name: "change without deletion protection", from: "PRESTABLE", to: "PRODUCTION", wantRequiresNew: true},
+		{name: "change with deletion protection", from: "PRESTABLE", to: "PRODUCTION", deletionProtection: true, wantErr: true},
+		{name: "change with deletion protection and force destroy", from: "PRESTABLE", to: "PRODUCTION", deletionProtection: true, forceDestroy: true, wantRequiresNew: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resourceYandexMDBClickHouseCluster()
+			initial := schema.TestResourceDataRaw(t, r.Schema, clickHouseWithEnvironment(tt.from, tt.deletionProtection, tt.forceDestroy))
+			initial.SetId("cluster")
+
+			diff, err := r.Diff(context.Background(), initial.State(), terraform.NewResourceConfigRaw(clickHouseWithEnvironment(tt.to, tt.deletionProtection, tt.forceDestroy)), nil)
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "disable deletion_protection first")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantRequiresNew, diff.RequiresNew())
+		})
+	}
+}
+
+func TestClickHouseClusterSqlManagementDiffCustomize(t *testing.T) {
+	user := []interface{}{map[string]interface{}{"name": "john", "password": "password"}}
+	database := []interface{}{map[string]interface{}{"name": "testdb"}}
+
+	tests := []struct {
+		name    string
+		extra   map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:  "admin password with sql user management",
+			extra: map[string]interface{}{"admin_password": "strong_password", "sql_user_management": true},
+		},
+		{
+			name:  "users and databases without sql management",
+			extra: map[string]interface{}{"user": user, "database": database},
+		},
+		{
+			name:    "admin password without sql user management",
+			extra:   map[string]interface{}{"admin_password": "strong_password"},
+			wantErr: "admin_password can only be set when sql_user_management is enabled",
+		},
+		{
+			name:    "user with sql user management",
+			extra:   map[string]interface{}{"sql_user_management": true, "user": user},
+			wantErr: "user blocks can't be used when sql_user_management is enabled",
+		},
+		{
+			name: "database with sql database management",
+			extra: map[string]interface{}{
+				"sql_user_management":     true,
+				"sql_database_management": true,
+				"database":                database,
+			},
+			wantErr: "database blocks can't be used when sql_database_management is enabled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resourceYandexMDBClickHouseCluster()
+			_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(clickHouseDiffTestConfig(tt.extra)), nil)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestClickHouseClusterServiceAccountDiffCustomize(t *testing.T) {
+	formatSchema := []interface{}{map[string]interface{}{
+		"name": "test_schema",
+		"type": "FORMAT_SCHEMA_TYPE_CAPNPROTO",
+		"uri":  "https://storage.yandexcloud.net/bucket/test.capnp",
+	}}
+	mlModel := []interface{}{map[string]interface{}{
+		"name": "test_model",
+		"type": "ML_MODEL_TYPE_CATBOOST",
+		"uri":  "https://storage.yandexcloud.net/bucket/train.csv",
+	}}
+
+	tests := []struct {
+		name    string
+		extra   map[string]interface{}
+		wantErr string
+	}{
+		{
+			name: "no object storage references",
+		},
+		{
+			name: "format schema with service account",
+			extra: map[string]interface{}{
+				"format_schema":      formatSchema,
+				"service_account_id": "sa",
+			},
+		},
+		{
+			name: "ml model with service account",
+			extra: map[string]interface{}{
+				"ml_model":           mlModel,
+				"service_account_id": "sa",
+			},
+		},
+		{
+			name:    "format schema without service account",
+			extra:   map[string]interface{}{"format_schema": formatSchema},
+			wantErr: "service_account_id must be set when format_schema or ml_model is used",
+		},
+		{
+			name:    "ml model without service account",
+			extra:   map[string]interface{}{"ml_model": mlModel},
+			wantErr: "service_account_id must be set when format_schema or ml_model is used",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resourceYandexMDBClickHouseCluster()
+			initial := schema.TestResourceDataRaw(t, r.Schema, clickHouseDiffTestConfig(nil))
+			initial.SetId("test-cluster-id")
+
+			_, err := r.Diff(context.Background(), initial.State(), terraform.NewResourceConfigRaw(clickHouseDiffTestConfig(tt.extra)), nil)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestClickHouseClusterNetworkIdForceNew(t *testing.T) {
+	clickHouseInNetwork := func(networkID string) map[string]interface{} {
+		return clickHouseDiffTestConfig(map[string]interface{}{"network_id": networkID})
+	}
+
+	r := resourceYandexMDBClickHouseCluster()
+	require.True(t, r.Schema["network_id"].ForceNew)
+
+	initial := schema.TestResourceDataRaw(t, r.Schema, clickHouseInNetwork("network1"))
+	initial.SetId("test-cluster-id")
+
+	diff, err := r.Diff(context.Background(), initial.State(), terraform.NewResourceConfigRaw(clickHouseInNetwork("network1")), nil)
+	require.NoError(t, err)
+	require.False(t, diff != nil && diff.RequiresNew(), "unchanged network_id must not recreate the cluster")
+
+	diff, err = r.Diff(context.Background(), initial.State(), terraform.NewResourceConfigRaw(clickHouseInNetwork("network2")), nil)
+	require.NoError(t, err)
+	require.NotNil(t, diff)
+	require.True(t, diff.RequiresNew())
+	require.Equal(t, "network1", diff.Attributes["network_id"].Old)
+	require.Equal(t, "network2", diff.Attributes["network_id"].New)
+	require.True(t, diff.Attributes["network_id"].RequiresNew)
+}
+
+func TestClickHouseHostSubnetsDiffCustomize(t *testing.T) {
+	clickHouseInSubnet := func(networkID, subnetID string) map[string]interface{} {
+		return clickHouseDiffTestConfig(map[string]interface{}{
+			"network_id": networkID,
+			"host": []interface{}{map[string]interface{}{
+				"type":      "CLICKHOUSE",
+				"zone":      "ru-central1-a",
+				"subnet_id": subnetID,
+			}},
+		})
+	}
+
+	subnetNetworks := map[string]string{"subnet1": "network1", "subnet2": "network2"}
+	r := resourceYandexMDBClickHouseCluster()
+	r.CustomizeDiff = clickHouseHostSubnetsDiffCustomize(func(_ context.Context, _ interface{}, subnetID string) (string, error) {
+		return subnetNetworks[subnetID], nil
+	})
+
+	_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(clickHouseInSubnet("network1", "subnet1")), nil)
+	require.NoError(t, err)
+
+	_, err = r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(clickHouseInSubnet("network1", "subnet2")), nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `subnet "subnet2" of ClickHouse host in zone "ru-central1-a" belongs to network "network2"`)
+}
+
+func TestIsClickHouseHostSubnetConfigured(t *testing.T) {
+	hostType := cty.Object(map[string]cty.Type{"subnet_id": cty.String})
+	rawHosts := cty.ListVal([]cty.Value{
+		cty.ObjectVal(map[string]cty.Value{"subnet_id": cty.StringVal("subnet1")}),
+		cty.ObjectVal(map[string]cty.Value{"subnet_id": cty.NullVal(cty.String)}),
+		cty.ObjectVal(map[string]cty.Value{"subnet_id": cty.UnknownVal(cty.String)}),
+	})
+
+	require.True(t, isClickHouseHostSubnetConfigured(rawHosts, 0))
+	require.False(t, isClickHouseHostSubnetConfigured(rawHosts, 1), "computed subnet_id must not be checked")
+	require.False(t, isClickHouseHostSubnetConfigured(rawHosts, 2), "unknown subnet_id must not be checked")
+	require.False(t, isClickHouseHostSubnetConfigured(rawHosts, 3))
+	require.False(t, isClickHouseHostSubnetConfigured(cty.NullVal(cty.List(hostType)), 0))
+	require.True(t, isClickHouseHostSubnetConfigured(cty.NilVal, 0))
+}
+
+func TestClickHouseClusterZooKeeperHostsDiffCustomize(t *testing.T) {
+	clickHouseWithZooKeeperZones := func(zones ...string) map[string]interface{} {
+		hosts := []interface{}{map[string]interface{}{
+			"type": "CLICKHOUSE",
+			"zone": "ru-central1-a",
+		}}
+		for _, zone := range zones {
+			hosts = append(hosts, map[string]interface{}{
+				"type": "ZOOKEEPER",
+				"zone": zone,
+			})
+		}
+		return clickHouseDiffTestConfig(map[string]interface{}{"host": hosts})
+	}
+
+	tests := []struct {
+		name    string
+		zones   []string
+		wantErr string
+	}{
+		{name: "no zookeeper hosts"},
+		{name: "three hosts in distinct zones", zones: []string{"ru-central1-a", "ru-central1-b", "ru-central1-c"}},
+		{name: "two hosts", zones: []string{"ru-central1-a", "ru-central1-b"}, wantErr: "must have 1 or 3 ZooKeeper hosts, got 2"},
+		{name: "three hosts in same zone", zones: []string{"ru-central1-a", "ru-central1-b", "ru-central1-a"}, wantErr: "zone \"ru-central1-a\" is used more than once"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resourceYandexMDBClickHouseCluster()
+			_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(clickHouseWithZooKeeperZones(tt.zones...)), nil)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestClickHouseClusterRestoreRequest(t *testing.T) {
+	req := &clickhouse.CreateClusterRequest{
+		FolderId:           "folder",
+		Name:               "restored",
+		Description:        "description",
+		Labels:             map[string]string{"key": "value"},
+		Environment:        clickhouse.Cluster_PRESTABLE,
+		ConfigSpec:         &clickhouse.ConfigSpec{Version: "22.8"},
+		HostSpecs:          []*clickhouse.HostSpec{{ZoneId: "ru-central1-a", Type: clickhouse.Host_CLICKHOUSE}},
+		NetworkId:          "network",
+		ServiceAccountId:   "sa",
+		SecurityGroupIds:   []string{"sg1"},
+		DeletionProtection: true,
+	}
+
+	restoreReq := prepareRestoreClickHouseClusterRequest(req, "backup")
+
+	require.Equal(t, "backup", restoreReq.BackupId)
+	require.Equal(t, req.FolderId, restoreReq.FolderId)
+	require.Equal(t, req.Name, restoreReq.Name)
+	require.Equal(t, req.Description, restoreReq.Description)
+	require.Equal(t, req.Labels, restoreReq.Labels)
+	require.Equal(t, req.Environment, restoreReq.Environment)
+	require.Equal(t, req.ConfigSpec, restoreReq.ConfigSpec)
+	require.Equal(t, req.HostSpecs, restoreReq.HostSpecs)
+	require.Equal(t, req.NetworkId, restoreReq.NetworkId)
+	require.Equal(t, req.ServiceAccountId, restoreReq.ServiceAccountId)
+	require.Equal(t, req.SecurityGroupIds, restoreReq.SecurityGroupIds)
+}
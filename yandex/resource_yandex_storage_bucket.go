@@ -2,6 +2,7 @@ package yandex
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -9,6 +10,7 @@ import (
 	"reflect"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	awspolicy "github.com/jen20/awspolicyequivalence"
@@ -24,6 +26,8 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/s3"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -35,6 +39,16 @@ const (
 	storageClassIce      = "ICE"
 )
 
+// Bucket policies larger than this are rejected by the API.
+const storageBucketPolicyMaxSize = 20 * 1024
+
+const (
+	// DeleteObjects accepts at most this many keys per request.
+	storageBucketDeleteObjectsBatchSize = 1000
+	// Number of DeleteObjects requests force_destroy keeps in flight.
+	storageBucketForceDestroyWorkers = 8
+)
+
 var storageClassSet = []string{
 	storageClassStandard,
 	storageClassCold,
@@ -48,8 +62,15 @@ func resourceYandexStorageBucket() *schema.Resource {
 		Update: resourceYandexStorageBucketUpdate,
 		Delete: resourceYandexStorageBucketDelete,
 
+		CustomizeDiff: customdiff.All(
+			storageBucketVersioningDiffCustomize,
+			storageBucketLifecycleDiffCustomize,
+			storageBucketHTTPSDiffCustomize,
+			storageBucketInventoryDiffCustomize,
+		),
+
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceYandexStorageBucketImportState,
 		},
 
 		SchemaVersion: 0,
@@ -134,10 +155,38 @@ func resourceYandexStorageBucket() *schema.Resource {
 				},
 			},
 
+			"computed_grants": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Set:      grantHash,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"uri": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"permissions": {
+							Type:     schema.TypeSet,
+							Computed: true,
+							Set:      schema.HashString,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+
 			"policy": {
 				Type:             schema.TypeString,
 				Optional:         true,
-				ValidateFunc:     validateStringIsJSON,
+				ValidateFunc:     validateStorageBucketPolicy,
 				DiffSuppressFunc: suppressEquivalentAwsPolicyDiffs,
 			},
 
@@ -196,19 +245,78 @@ func resourceYandexStorageBucket() *schema.Resource {
 								"website.0.index_document",
 								"website.0.error_document",
 								"website.0.routing_rules",
+								"website.0.routing_rule",
 							},
 							Optional: true,
 						},
 
 						"routing_rules": {
-							Type:         schema.TypeString,
-							Optional:     true,
-							ValidateFunc: validateStringIsJSON,
+							Type:          schema.TypeString,
+							Optional:      true,
+							ValidateFunc:  validateStringIsJSON,
+							ConflictsWith: []string{"website.0.routing_rule"},
 							StateFunc: func(v interface{}) string {
 								json, _ := NormalizeJsonString(v)
 								return json
 							},
 						},
+
+						"routing_rule": {
+							Type:          schema.TypeList,
+							Optional:      true,
+							ConflictsWith: []string{"website.0.routing_rules"},
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"condition": {
+										Type:     schema.TypeList,
+										Optional: true,
+										MaxItems: 1,
+										Elem: &schema.Resource{
+											Schema: map[string]*schema.Schema{
+												"key_prefix_equals": {
+													Type:     schema.TypeString,
+													Optional: true,
+												},
+												"http_error_code_returned_equals": {
+													Type:     schema.TypeString,
+													Optional: true,
+												},
+											},
+										},
+									},
+									"redirect": {
+										Type:     schema.TypeList,
+										Required: true,
+										MaxItems: 1,
+										Elem: &schema.Resource{
+											Schema: map[string]*schema.Schema{
+												"hostname": {
+													Type:     schema.TypeString,
+													Optional: true,
+												},
+												"http_redirect_code": {
+													Type:     schema.TypeString,
+													Optional: true,
+												},
+												"protocol": {
+													Type:         schema.TypeString,
+													Optional:     true,
+													ValidateFunc: validation.StringInSlice(s3.Protocol_Values(), false),
+												},
+												"replace_key_prefix_with": {
+													Type:     schema.TypeString,
+													Optional: true,
+												},
+												"replace_key_with": {
+													Type:     schema.TypeString,
+													Optional: true,
+												},
+											},
+										},
+									},
+								},
+							},
+						},
 					},
 				},
 			},
@@ -426,6 +534,84 @@ func resourceYandexStorageBucket() *schema.Resource {
 				},
 			},
 
+			"request_payer": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      s3.PayerBucketOwner,
+				ValidateFunc: validation.StringInSlice(s3.Payer_Values(), false),
+			},
+
+			"inventory": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Set:      storageBucketInventoryHash,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringLenBetween(1, 64),
+						},
+						"enabled": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+						"included_object_versions": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(s3.InventoryIncludedObjectVersions_Values(), false),
+						},
+						"schedule": {
+							Type:     schema.TypeList,
+							Required: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"frequency": {
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validation.StringInSlice(s3.InventoryFrequency_Values(), false),
+									},
+								},
+							},
+						},
+						"destination": {
+							Type:     schema.TypeList,
+							Required: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"bucket": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"prefix": {
+										Type:     schema.TypeString,
+										Optional: true,
+									},
+									"format": {
+										Type:     schema.TypeString,
+										Required: true,
+										ValidateFunc: validation.StringInSlice([]string{
+											s3.InventoryFormatCsv, s3.InventoryFormatParquet,
+										}, false),
+									},
+								},
+							},
+						},
+						"optional_fields": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validation.StringInSlice(s3.InventoryOptionalField_Values(), false),
+							},
+						},
+					},
+				},
+			},
+
 			"force_destroy": {
 				Type:     schema.TypeBool,
 				Optional: true,
@@ -471,6 +657,68 @@ func resourceYandexStorageBucket() *schema.Resource {
 				},
 			},
 
+			"replication_configuration": {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"role": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"rules": {
+							Type:     schema.TypeList,
+							Required: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"id": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										Computed:     true,
+										ValidateFunc: validation.StringLenBetween(0, 255),
+									},
+									"status": {
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validation.StringInSlice(s3.ReplicationRuleStatus_Values(), false),
+									},
+									"prefix": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										ValidateFunc: validation.StringLenBetween(0, 1024),
+									},
+									"priority": {
+										Type:         schema.TypeInt,
+										Optional:     true,
+										ValidateFunc: validation.IntAtLeast(0),
+									},
+									"destination": {
+										Type:     schema.TypeList,
+										MaxItems: 1,
+										Required: true,
+										Elem: &schema.Resource{
+											Schema: map[string]*schema.Schema{
+												"bucket": {
+													Type:     schema.TypeString,
+													Required: true,
+												},
+												"storage_class": {
+													Type:         schema.TypeString,
+													Optional:     true,
+													Computed:     true,
+													ValidateFunc: validation.StringInSlice(storageClassSet, false),
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+
 			// These fields use extended API and requires IAM token
 			// to be set in order to operate.
 			"default_storage_class": {
@@ -519,12 +767,30 @@ func resourceYandexStorageBucket() *schema.Resource {
 				Type:     schema.TypeSet,
 				Optional: true,
 				MaxItems: 1,
-				Set:      storageBucketS3SetFunc("certificate_id"),
+				Set:      storageBucketS3SetFunc("certificate_id", "self_managed"),
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"certificate_id": {
 							Type:     schema.TypeString,
-							Required: true,
+							Optional: true,
+						},
+						"self_managed": {
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"certificate": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"private_key": {
+										Type:      schema.TypeString,
+										Required:  true,
+										Sensitive: true,
+									},
+								},
+							},
 						},
 					},
 				},
@@ -536,10 +802,27 @@ func resourceYandexStorageBucket() *schema.Resource {
 
 func tagsSchema() *schema.Schema {
 	return &schema.Schema{
-		Type:     schema.TypeMap,
-		Optional: true,
-		Elem:     &schema.Schema{Type: schema.TypeString},
+		Type:             schema.TypeMap,
+		Optional:         true,
+		Elem:             &schema.Schema{Type: schema.TypeString},
+		DiffSuppressFunc: suppressStorageSystemTagDiff,
+	}
+}
+
+// Tags with these prefixes are set by the service itself and are never managed by user.
+var storageSystemTagPrefixes = []string{"aws:", "yc:"}
+
+func isStorageSystemTag(key string) bool {
+	for _, prefix := range storageSystemTagPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
 	}
+	return false
+}
+
+func suppressStorageSystemTagDiff(k, _, _ string, _ *schema.ResourceData) bool {
+	return isStorageSystemTag(strings.TrimPrefix(k, "tags."))
 }
 
 const (
@@ -676,6 +959,13 @@ func resourceYandexStorageBucketCreateByS3Client(d *schema.ResourceData, meta in
 	})
 }
 
+func resourceYandexStorageBucketImportState(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	if err := d.Set("force_destroy", false); err != nil {
+		return nil, fmt.Errorf("error setting force_destroy: %s", err)
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceYandexStorageBucketCreate(d *schema.ResourceData, meta interface{}) error {
 	// Get the bucket and acl
 	var bucket string
@@ -752,6 +1042,7 @@ func resourceYandexStorageBucketUpdateBasic(d *schema.ResourceData, meta interfa
 		{"cors_rule", resourceYandexStorageBucketCORSUpdate},
 		{"website", resourceYandexStorageBucketWebsiteUpdate},
 		{"versioning", resourceYandexStorageBucketVersioningUpdate},
+		{"replication_configuration", resourceYandexStorageBucketReplicationConfigurationUpdate},
 		{"acl", resourceYandexStorageBucketACLUpdate},
 		{"grant", resourceYandexStorageBucketGrantsUpdate},
 		{"logging", resourceYandexStorageBucketLoggingUpdate},
@@ -759,6 +1050,8 @@ func resourceYandexStorageBucketUpdateBasic(d *schema.ResourceData, meta interfa
 		{"server_side_encryption_configuration", resourceYandexStorageBucketServerSideEncryptionConfigurationUpdate},
 		{"object_lock_configuration", resourceYandexStorageBucketObjectLockConfigurationUpdate},
 		{"tags", resourceYandexStorageBucketTagsUpdate},
+		{"request_payer", resourceYandexStorageBucketRequestPayerUpdate},
+		{"inventory", resourceYandexStorageBucketInventoryUpdate},
 	}
 
 	for _, property := range resourceProperties {
@@ -770,6 +1063,11 @@ func resourceYandexStorageBucketUpdateBasic(d *schema.ResourceData, meta interfa
 			continue
 		}
 
+		// New buckets are paid by the owner already, do not call the API for the default value.
+		if property.name == "request_payer" && d.IsNewResource() && d.Get("request_payer").(string) == s3.PayerBucketOwner {
+			continue
+		}
+
 		err := property.updateHandler(s3Client, d)
 		if err != nil {
 			return fmt.Errorf("handling %s: %w", property.name, err)
@@ -870,15 +1168,10 @@ func resourceYandexStorageBucketUpdateExtended(d *schema.ResourceData, meta inte
 
 	schemaSet := d.Get("https").(*schema.Set)
 	if schemaSet.Len() > 0 {
-		httpsUpdateRequest := &storagepb.SetBucketHTTPSConfigRequest{
-			Name: bucket,
-		}
-
 		params := schemaSet.List()[0].(map[string]interface{})
-		httpsUpdateRequest.Params = &storagepb.SetBucketHTTPSConfigRequest_CertificateManager{
-			CertificateManager: &storagepb.CertificateManagerHTTPSConfigParams{
-				CertificateId: params["certificate_id"].(string),
-			},
+		httpsUpdateRequest, err := expandStorageBucketHTTPSConfig(bucket, params)
+		if err != nil {
+			return err
 		}
 
 		log.Printf("[INFO] updating S3 bucket https config: %s", protojson.Format(httpsUpdateRequest))
@@ -932,6 +1225,36 @@ func resourceYandexStorageBucketUpdateExtended(d *schema.ResourceData, meta inte
 	return nil
 }
 
+func expandStorageBucketHTTPSConfig(bucket string, params map[string]interface{}) (*storagepb.SetBucketHTTPSConfigRequest, error) {
+	request := &storagepb.SetBucketHTTPSConfigRequest{
+		Name: bucket,
+	}
+
+	certificateID, _ := params["certificate_id"].(string)
+	selfManaged, _ := params["self_managed"].([]interface{})
+
+	switch {
+	case certificateID != "":
+		request.Params = &storagepb.SetBucketHTTPSConfigRequest_CertificateManager{
+			CertificateManager: &storagepb.CertificateManagerHTTPSConfigParams{
+				CertificateId: certificateID,
+			},
+		}
+	case len(selfManaged) > 0 && selfManaged[0] != nil:
+		certificate := selfManaged[0].(map[string]interface{})
+		request.Params = &storagepb.SetBucketHTTPSConfigRequest_SelfManaged{
+			SelfManaged: &storagepb.SelfManagedHTTPSConfigParams{
+				CertificatePem: certificate["certificate"].(string),
+				PrivateKeyPem:  certificate["private_key"].(string),
+			},
+		}
+	default:
+		return nil, fmt.Errorf("https: one of certificate_id or self_managed must be specified")
+	}
+
+	return request, nil
+}
+
 func resourceYandexStorageBucketRead(d *schema.ResourceData, meta interface{}) error {
 	err := resourceYandexStorageBucketReadBasic(d, meta)
 	if err != nil {
@@ -1027,26 +1350,46 @@ func resourceYandexStorageBucketReadBasic(d *schema.ResourceData, meta interface
 	if cors, ok := corsResponse.(*s3.GetBucketCorsOutput); ok && len(cors.CORSRules) > 0 {
 		log.Printf("[DEBUG] Storage get bucket CORS output: %#v", cors)
 
-		corsRules = make([]map[string]interface{}, 0, len(cors.CORSRules))
-		for _, ruleObject := range cors.CORSRules {
-			rule := make(map[string]interface{})
-			rule["allowed_headers"] = flattenStringList(ruleObject.AllowedHeaders)
-			rule["allowed_methods"] = flattenStringList(ruleObject.AllowedMethods)
-			rule["allowed_origins"] = flattenStringList(ruleObject.AllowedOrigins)
-			// Both the "ExposeHeaders" and "MaxAgeSeconds" might not be set.
-			if ruleObject.ExposeHeaders != nil {
-				rule["expose_headers"] = flattenStringList(ruleObject.ExposeHeaders)
-			}
-			if ruleObject.MaxAgeSeconds != nil {
-				rule["max_age_seconds"] = int(*ruleObject.MaxAgeSeconds)
-			}
-			corsRules = append(corsRules, rule)
-		}
+		corsRules = flattenStorageBucketCORSRules(cors.CORSRules)
 	}
 	if err := d.Set("cors_rule", corsRules); err != nil {
 		return fmt.Errorf("error setting cors_rule: %s", err)
 	}
 
+	// Read the request payment configuration
+	paymentResponse, err := retryFlakyS3Responses(func() (interface{}, error) {
+		return s3Client.GetBucketRequestPayment(&s3.GetBucketRequestPaymentInput{
+			Bucket: bucketAWS,
+		})
+	})
+	if err != nil && !isAWSErr(err, "NotImplemented", "") {
+		if handleS3BucketNotFoundError(d, err) {
+			return nil
+		}
+		return fmt.Errorf("error getting Storage Bucket request payment configuration: %s", err)
+	}
+
+	// Without the request payment API the bucket owner pays for requests.
+	requestPayer := s3.PayerBucketOwner
+	if payment, ok := paymentResponse.(*s3.GetBucketRequestPaymentOutput); ok && payment.Payer != nil {
+		requestPayer = aws.StringValue(payment.Payer)
+	}
+	d.Set("request_payer", requestPayer)
+
+	// Read the inventory configurations
+	inventories, err := listStorageBucketInventoryConfigurations(s3Client, bucketAWS)
+	if err != nil && !isAWSErr(err, "NotImplemented", "") && !isAWSErr(err, "AccessDenied", "") {
+		if handleS3BucketNotFoundError(d, err) {
+			return nil
+		}
+		return fmt.Errorf("error getting Storage Bucket inventory configurations: %s", err)
+	} else if err != nil {
+		log.Printf("[DEBUG] Got an error while trying to read Storage Bucket (%s) inventory configurations: %s", d.Id(), err)
+	}
+	if err := d.Set("inventory", flattenStorageBucketInventoryConfigurations(inventories)); err != nil {
+		return fmt.Errorf("error setting inventory: %s", err)
+	}
+
 	// Read the website configuration
 	wsResponse, err := retryFlakyS3Responses(func() (interface{}, error) {
 		return s3Client.GetBucketWebsite(&s3.GetBucketWebsiteInput{
@@ -1101,11 +1444,16 @@ func resourceYandexStorageBucketReadBasic(d *schema.ResourceData, meta interface
 		}
 
 		if v := ws.RoutingRules; v != nil {
-			rr, err := normalizeRoutingRules(v)
-			if err != nil {
-				return fmt.Errorf("Error while marshaling routing rules: %s", err)
+			// Typed routing rules are kept as such, otherwise the rules are read as JSON.
+			if len(d.Get("website.0.routing_rule").([]interface{})) > 0 {
+				w["routing_rule"] = flattenStorageBucketWebsiteRoutingRules(v)
+			} else {
+				rr, err := normalizeRoutingRules(v)
+				if err != nil {
+					return fmt.Errorf("Error while marshaling routing rules: %s", err)
+				}
+				w["routing_rules"] = rr
 			}
-			w["routing_rules"] = rr
 		}
 
 		// We have special handling for the website configuration,
@@ -1152,6 +1500,9 @@ func resourceYandexStorageBucketReadBasic(d *schema.ResourceData, meta interface
 			if err := d.Set("grant", nil); err != nil {
 				return fmt.Errorf("error resetting Storage Bucket `grant` %s", err)
 			}
+			if err := d.Set("computed_grants", nil); err != nil {
+				return fmt.Errorf("error resetting Storage Bucket `computed_grants` %s", err)
+			}
 
 			return nil
 		}
@@ -1163,6 +1514,10 @@ func resourceYandexStorageBucketReadBasic(d *schema.ResourceData, meta interface
 		if err := d.Set("grant", schema.NewSet(grantHash, grants)); err != nil {
 			return fmt.Errorf("error setting Storage Bucket `grant` %s", err)
 		}
+		// Populated regardless of `acl`/`grant`, so canned ACLs can be migrated to explicit grants.
+		if err := d.Set("computed_grants", schema.NewSet(grantHash, grants)); err != nil {
+			return fmt.Errorf("error setting Storage Bucket `computed_grants` %s", err)
+		}
 	}
 
 	// Read the versioning configuration
@@ -1404,6 +1759,28 @@ func resourceYandexStorageBucketReadBasic(d *schema.ResourceData, meta interface
 		return fmt.Errorf("error setting server_side_encryption_configuration: %s", err)
 	}
 
+	// Read the bucket replication configuration
+
+	replicationResponse, err := retryFlakyS3Responses(func() (interface{}, error) {
+		return s3Client.GetBucketReplication(&s3.GetBucketReplicationInput{
+			Bucket: bucketAWS,
+		})
+	})
+	switch {
+	case err == nil, isAWSErr(err, "ReplicationConfigurationNotFoundError", ""):
+		replicationConfiguration := make([]map[string]interface{}, 0)
+		if replication, ok := replicationResponse.(*s3.GetBucketReplicationOutput); ok && replication.ReplicationConfiguration != nil {
+			replicationConfiguration = flattenStorageBucketReplicationConfiguration(replication.ReplicationConfiguration)
+		}
+		if err := d.Set("replication_configuration", replicationConfiguration); err != nil {
+			return fmt.Errorf("error setting replication_configuration: %s", err)
+		}
+	case isAWSErr(err, "NotImplemented", ""), isAWSErr(err, "AccessDenied", ""):
+		log.Printf("[DEBUG] Got an error while trying to read Storage Bucket (%s) replication: %s", d.Id(), err)
+	default:
+		return fmt.Errorf("error getting S3 Bucket replication: %w", err)
+	}
+
 	getBucketTagging, err := retryFlakyS3Responses(func() (interface{}, error) {
 		return s3Client.GetBucketTagging(&s3.GetBucketTaggingInput{
 			Bucket: bucketAWS,
@@ -1504,22 +1881,46 @@ func resourceYandexStorageBucketReadExtended(d *schema.ResourceData, meta interf
 
 	log.Printf("[DEBUG] S3 bucket https config: %s", protojson.Format(https))
 
-	if https.SourceType == storagepb.HTTPSConfig_SOURCE_TYPE_MANAGED_BY_CERTIFICATE_MANAGER {
-		flatten := map[string]interface{}{
-			"certificate_id": https.CertificateId,
-		}
-
-		result := []map[string]interface{}{flatten}
-
-		err = d.Set("https", result)
-		if err != nil {
-			return fmt.Errorf("updating S3 bucket https config state: %w", err)
-		}
+	var configured []interface{}
+	if v, ok := d.Get("https").(*schema.Set); ok {
+		configured = v.List()
 	}
-
+	result, ok := flattenStorageBucketHTTPSConfig(https, configured)
+	if !ok {
+		log.Printf("[DEBUG] unsupported S3 bucket https config source type %s", https.SourceType)
+		return nil
+	}
+
+	err = d.Set("https", result)
+	if err != nil {
+		return fmt.Errorf("updating S3 bucket https config state: %w", err)
+	}
+
 	return nil
 }
 
+// flattenStorageBucketHTTPSConfig returns the https block for the config source type.
+// Self-managed certificate and private key are not returned by the API, so the configured ones are kept.
+func flattenStorageBucketHTTPSConfig(https *storagepb.HTTPSConfig, configured []interface{}) ([]map[string]interface{}, bool) {
+	switch https.SourceType {
+	case storagepb.HTTPSConfig_SOURCE_TYPE_MANAGED_BY_CERTIFICATE_MANAGER:
+		return []map[string]interface{}{{
+			"certificate_id": https.CertificateId,
+		}}, true
+	case storagepb.HTTPSConfig_SOURCE_TYPE_SELF_MANAGED:
+		var selfManaged []interface{}
+		if len(configured) > 0 && configured[0] != nil {
+			selfManaged, _ = configured[0].(map[string]interface{})["self_managed"].([]interface{})
+		}
+		return []map[string]interface{}{{
+			"certificate_id": "",
+			"self_managed":   selfManaged,
+		}}, true
+	default:
+		return nil, false
+	}
+}
+
 func resourceYandexStorageBucketDelete(d *schema.ResourceData, meta interface{}) error {
 	config := meta.(*Config)
 	s3Client, err := getS3Client(d, config)
@@ -1544,46 +1945,7 @@ func resourceYandexStorageBucketDelete(d *schema.ResourceData, meta interface{})
 			// bucket may have things delete them
 			log.Printf("[DEBUG] Storage Bucket attempting to forceDestroy %+v", err)
 
-			bucket := d.Get("bucket").(string)
-			resp, err := s3Client.ListObjectVersions(
-				&s3.ListObjectVersionsInput{
-					Bucket: aws.String(bucket),
-				},
-			)
-
-			if err != nil {
-				return fmt.Errorf("error listing Storage Bucket object versions: %s", err)
-			}
-
-			objectsToDelete := make([]*s3.ObjectIdentifier, 0)
-
-			if len(resp.DeleteMarkers) != 0 {
-				for _, v := range resp.DeleteMarkers {
-					objectsToDelete = append(objectsToDelete, &s3.ObjectIdentifier{
-						Key:       v.Key,
-						VersionId: v.VersionId,
-					})
-				}
-			}
-
-			if len(resp.Versions) != 0 {
-				for _, v := range resp.Versions {
-					objectsToDelete = append(objectsToDelete, &s3.ObjectIdentifier{
-						Key:       v.Key,
-						VersionId: v.VersionId,
-					})
-				}
-			}
-
-			params := &s3.DeleteObjectsInput{
-				Bucket: aws.String(bucket),
-				Delete: &s3.Delete{
-					Objects: objectsToDelete,
-				},
-			}
-
-			_, err = s3Client.DeleteObjects(params)
-
+			err = deleteAllStorageBucketObjectVersions(s3Client, d.Get("bucket").(string))
 			if err != nil {
 				return fmt.Errorf("error force_destroy deleting Storage Bucket (%s): %s", d.Id(), err)
 			}
@@ -1613,6 +1975,116 @@ func resourceYandexStorageBucketDelete(d *schema.ResourceData, meta interface{})
 	return nil
 }
 
+// deleteAllStorageBucketObjectVersions lists every object version and delete
+// marker in the bucket and removes them with DeleteObjects batches issued by
+// a bounded pool of workers. The first deletion error stops the listing and
+// is returned to the caller.
+func deleteAllStorageBucketObjectVersions(s3Client *s3.S3, bucket string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	batches := make(chan []*s3.ObjectIdentifier)
+	for i := 0; i < storageBucketForceDestroyWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for objects := range batches {
+				if ctx.Err() != nil {
+					continue
+				}
+				if err := deleteStorageBucketObjectsBatch(ctx, s3Client, bucket, objects); err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+	send := func(objects []*s3.ObjectIdentifier) bool {
+		select {
+		case batches <- objects:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	pending := make([]*s3.ObjectIdentifier, 0, storageBucketDeleteObjectsBatchSize)
+	listErr := s3Client.ListObjectVersionsPagesWithContext(ctx,
+		&s3.ListObjectVersionsInput{
+			Bucket: aws.String(bucket),
+		},
+		func(page *s3.ListObjectVersionsOutput, _ bool) bool {
+			for _, v := range page.DeleteMarkers {
+				pending = append(pending, &s3.ObjectIdentifier{
+					Key:       v.Key,
+					VersionId: v.VersionId,
+				})
+			}
+			for _, v := range page.Versions {
+				pending = append(pending, &s3.ObjectIdentifier{
+					Key:       v.Key,
+					VersionId: v.VersionId,
+				})
+			}
+			for len(pending) >= storageBucketDeleteObjectsBatchSize {
+				if !send(pending[:storageBucketDeleteObjectsBatchSize]) {
+					return false
+				}
+				pending = pending[storageBucketDeleteObjectsBatchSize:]
+			}
+			return true
+		},
+	)
+	if listErr == nil && len(pending) != 0 {
+		send(pending)
+	}
+
+	close(batches)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if listErr != nil {
+		return fmt.Errorf("error listing Storage Bucket object versions: %s", listErr)
+	}
+
+	return nil
+}
+
+func deleteStorageBucketObjectsBatch(ctx context.Context, s3Client *s3.S3, bucket string, objects []*s3.ObjectIdentifier) error {
+	resp, err := s3Client.DeleteObjectsWithContext(ctx, &s3.DeleteObjectsInput{
+		Bucket: aws.String(bucket),
+		Delete: &s3.Delete{
+			Objects: objects,
+			Quiet:   aws.Bool(true),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Errors) != 0 {
+		e := resp.Errors[0]
+		return fmt.Errorf("error deleting object %q (version %q): %s: %s",
+			aws.StringValue(e.Key), aws.StringValue(e.VersionId), aws.StringValue(e.Code), aws.StringValue(e.Message))
+	}
+
+	return nil
+}
+
 func resourceYandexStorageBucketCORSUpdate(s3Client *s3.S3, d *schema.ResourceData) error {
 	bucket := d.Get("bucket").(string)
 	rawCors := d.Get("cors_rule").([]interface{})
@@ -1688,6 +2160,25 @@ func resourceYandexStorageBucketCORSUpdate(s3Client *s3.S3, d *schema.ResourceDa
 	return nil
 }
 
+// flattenStorageBucketCORSRules always sets the header lists, so that headers missing
+// in the response are read the same way as the empty lists sent on put.
+func flattenStorageBucketCORSRules(rules []*s3.CORSRule) []map[string]interface{} {
+	corsRules := make([]map[string]interface{}, 0, len(rules))
+	for _, ruleObject := range rules {
+		rule := make(map[string]interface{})
+		rule["allowed_headers"] = flattenStringList(ruleObject.AllowedHeaders)
+		rule["allowed_methods"] = flattenStringList(ruleObject.AllowedMethods)
+		rule["allowed_origins"] = flattenStringList(ruleObject.AllowedOrigins)
+		rule["expose_headers"] = flattenStringList(ruleObject.ExposeHeaders)
+		// "MaxAgeSeconds" might not be set.
+		if ruleObject.MaxAgeSeconds != nil {
+			rule["max_age_seconds"] = int(*ruleObject.MaxAgeSeconds)
+		}
+		corsRules = append(corsRules, rule)
+	}
+	return corsRules
+}
+
 func resourceYandexStorageBucketWebsiteUpdate(s3Client *s3.S3, d *schema.ResourceData) error {
 	ws := d.Get("website").([]interface{})
 
@@ -1763,6 +2254,10 @@ func resourceYandexStorageBucketWebsitePut(s3Client *s3.S3, d *schema.ResourceDa
 		websiteConfiguration.RoutingRules = unmarshaledRules
 	}
 
+	if v, ok := website["routing_rule"].([]interface{}); ok && len(v) > 0 {
+		websiteConfiguration.RoutingRules = expandStorageBucketWebsiteRoutingRules(v)
+	}
+
 	putInput := &s3.PutBucketWebsiteInput{
 		Bucket:               aws.String(bucket),
 		WebsiteConfiguration: websiteConfiguration,
@@ -1783,6 +2278,78 @@ func resourceYandexStorageBucketWebsitePut(s3Client *s3.S3, d *schema.ResourceDa
 	return nil
 }
 
+func expandStorageBucketWebsiteRoutingRules(rawRules []interface{}) []*s3.RoutingRule {
+	// Empty values are not sent, so that the rules are read back the same way.
+	optionalString := func(m map[string]interface{}, key string) *string {
+		if v, ok := m[key].(string); ok && v != "" {
+			return aws.String(v)
+		}
+		return nil
+	}
+
+	rules := make([]*s3.RoutingRule, 0, len(rawRules))
+	for _, raw := range rawRules {
+		rawRule := raw.(map[string]interface{})
+		rule := &s3.RoutingRule{
+			Redirect: &s3.Redirect{},
+		}
+
+		if v, ok := rawRule["condition"].([]interface{}); ok && len(v) > 0 && v[0] != nil {
+			condition := v[0].(map[string]interface{})
+			rule.Condition = &s3.Condition{
+				KeyPrefixEquals:             optionalString(condition, "key_prefix_equals"),
+				HttpErrorCodeReturnedEquals: optionalString(condition, "http_error_code_returned_equals"),
+			}
+		}
+
+		if v, ok := rawRule["redirect"].([]interface{}); ok && len(v) > 0 && v[0] != nil {
+			redirect := v[0].(map[string]interface{})
+			rule.Redirect = &s3.Redirect{
+				HostName:             optionalString(redirect, "hostname"),
+				HttpRedirectCode:     optionalString(redirect, "http_redirect_code"),
+				Protocol:             optionalString(redirect, "protocol"),
+				ReplaceKeyPrefixWith: optionalString(redirect, "replace_key_prefix_with"),
+				ReplaceKeyWith:       optionalString(redirect, "replace_key_with"),
+			}
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+func flattenStorageBucketWebsiteRoutingRules(rules []*s3.RoutingRule) []interface{} {
+	result := make([]interface{}, 0, len(rules))
+	for _, rule := range rules {
+		r := make(map[string]interface{})
+
+		if c := rule.Condition; c != nil {
+			r["condition"] = []interface{}{
+				map[string]interface{}{
+					"key_prefix_equals":               aws.StringValue(c.KeyPrefixEquals),
+					"http_error_code_returned_equals": aws.StringValue(c.HttpErrorCodeReturnedEquals),
+				},
+			}
+		}
+
+		if rd := rule.Redirect; rd != nil {
+			r["redirect"] = []interface{}{
+				map[string]interface{}{
+					"hostname":                aws.StringValue(rd.HostName),
+					"http_redirect_code":      aws.StringValue(rd.HttpRedirectCode),
+					"protocol":                aws.StringValue(rd.Protocol),
+					"replace_key_prefix_with": aws.StringValue(rd.ReplaceKeyPrefixWith),
+					"replace_key_with":        aws.StringValue(rd.ReplaceKeyWith),
+				},
+			}
+		}
+
+		result = append(result, r)
+	}
+	return result
+}
+
 func resourceYandexStorageBucketWebsiteDelete(s3Client *s3.S3, d *schema.ResourceData) error {
 	bucket := d.Get("bucket").(string)
 	deleteInput := &s3.DeleteBucketWebsiteInput{Bucket: aws.String(bucket)}
@@ -1850,12 +2417,89 @@ func resourceYandexStorageBucketACLUpdate(s3Client *s3.S3, d *schema.ResourceDat
 	return nil
 }
 
+func resourceYandexStorageBucketRequestPayerUpdate(s3Client *s3.S3, d *schema.ResourceData) error {
+	bucket := d.Get("bucket").(string)
+
+	i := &s3.PutBucketRequestPaymentInput{
+		Bucket: aws.String(bucket),
+		RequestPaymentConfiguration: &s3.RequestPaymentConfiguration{
+			Payer: aws.String(d.Get("request_payer").(string)),
+		},
+	}
+	log.Printf("[DEBUG] Storage put bucket request payment: %#v", i)
+
+	_, err := retryFlakyS3Responses(func() (interface{}, error) {
+		return s3Client.PutBucketRequestPayment(i)
+	})
+	if err != nil {
+		return fmt.Errorf("error putting Storage Bucket request payment: %s", err)
+	}
+
+	return nil
+}
+
+func resourceYandexStorageBucketInventoryUpdate(s3Client *s3.S3, d *schema.ResourceData) error {
+	bucket := d.Get("bucket").(string)
+	o, n := d.GetChange("inventory")
+
+	oldConfigs := make(map[string]*s3.InventoryConfiguration)
+	for _, v := range o.(*schema.Set).List() {
+		c := expandStorageBucketInventoryConfiguration(v.(map[string]interface{}))
+		oldConfigs[aws.StringValue(c.Id)] = c
+	}
+
+	newConfigs := make(map[string]*s3.InventoryConfiguration)
+	for _, v := range n.(*schema.Set).List() {
+		c := expandStorageBucketInventoryConfiguration(v.(map[string]interface{}))
+		newConfigs[aws.StringValue(c.Id)] = c
+	}
+
+	for id := range oldConfigs {
+		if _, ok := newConfigs[id]; ok {
+			continue
+		}
+
+		log.Printf("[DEBUG] Storage delete bucket inventory configuration %q", id)
+		_, err := retryFlakyS3Responses(func() (interface{}, error) {
+			return s3Client.DeleteBucketInventoryConfiguration(&s3.DeleteBucketInventoryConfigurationInput{
+				Bucket: aws.String(bucket),
+				Id:     aws.String(id),
+			})
+		})
+		if err != nil {
+			return fmt.Errorf("error deleting Storage Bucket inventory configuration %q: %s", id, err)
+		}
+	}
+
+	for id, c := range newConfigs {
+		if reflect.DeepEqual(oldConfigs[id], c) {
+			continue
+		}
+
+		i := &s3.PutBucketInventoryConfigurationInput{
+			Bucket:                 aws.String(bucket),
+			Id:                     aws.String(id),
+			InventoryConfiguration: c,
+		}
+		log.Printf("[DEBUG] Storage put bucket inventory configuration: %#v", i)
+
+		_, err := retryFlakyS3Responses(func() (interface{}, error) {
+			return s3Client.PutBucketInventoryConfiguration(i)
+		})
+		if err != nil {
+			return fmt.Errorf("error putting Storage Bucket inventory configuration %q: %s", id, err)
+		}
+	}
+
+	return nil
+}
+
 func resourceYandexStorageBucketVersioningUpdate(s3conn *s3.S3, d *schema.ResourceData) error {
 	v := d.Get("versioning").([]interface{})
 	bucket := d.Get("bucket").(string)
 	vc := &s3.VersioningConfiguration{}
 
-	if len(v) > 0 {
+	if len(v) > 0 && v[0] != nil {
 		c := v[0].(map[string]interface{})
 
 		if c["enabled"].(bool) {
@@ -1868,22 +2512,269 @@ func resourceYandexStorageBucketVersioningUpdate(s3conn *s3.S3, d *schema.Resour
 		vc.Status = aws.String(s3.BucketVersioningStatusSuspended)
 	}
 
+	current, err := getStorageBucketVersioningStatus(s3conn, bucket)
+	if err != nil {
+		return err
+	}
+	// Bucket that never had versioning enabled has no status, which is the same as suspended one.
+	if current == aws.StringValue(vc.Status) ||
+		(current == "" && aws.StringValue(vc.Status) == s3.BucketVersioningStatusSuspended) {
+		log.Printf("[DEBUG] S3 bucket %q versioning is already %q, skipping update", bucket, aws.StringValue(vc.Status))
+		return nil
+	}
+
 	i := &s3.PutBucketVersioningInput{
 		Bucket:                  aws.String(bucket),
 		VersioningConfiguration: vc,
 	}
 	log.Printf("[DEBUG] S3 put bucket versioning: %#v", i)
 
-	_, err := retryFlakyS3Responses(func() (interface{}, error) {
+	_, err = retryFlakyS3Responses(func() (interface{}, error) {
 		return s3conn.PutBucketVersioning(i)
 	})
+	if isAWSErr(err, "InvalidBucketState", "") {
+		return fmt.Errorf("Error putting S3 versioning: versioning can't be suspended on bucket %q with object lock enabled: %s", bucket, err)
+	}
 	if err != nil {
 		return fmt.Errorf("Error putting S3 versioning: %s", err)
 	}
 
+	// Wait until the new status is visible, so that the following read doesn't see a stale one.
+	return resource.Retry(1*time.Minute, func() *resource.RetryError {
+		status, err := getStorageBucketVersioningStatus(s3conn, bucket)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		if status != aws.StringValue(vc.Status) {
+			return resource.RetryableError(
+				fmt.Errorf("S3 bucket %q versioning status is %q, expected %q", bucket, status, aws.StringValue(vc.Status)))
+		}
+		return nil
+	})
+}
+
+func getStorageBucketVersioningStatus(s3conn *s3.S3, bucket string) (string, error) {
+	resp, err := retryFlakyS3Responses(func() (interface{}, error) {
+		return s3conn.GetBucketVersioning(&s3.GetBucketVersioningInput{
+			Bucket: aws.String(bucket),
+		})
+	})
+	if err != nil {
+		return "", fmt.Errorf("Error getting S3 versioning: %s", err)
+	}
+
+	return aws.StringValue(resp.(*s3.GetBucketVersioningOutput).Status), nil
+}
+
+// storageBucketHTTPSDiffCustomize requires exactly one of certificate_id or self_managed in the https block.
+func storageBucketHTTPSDiffCustomize(_ context.Context, rdiff *schema.ResourceDiff, _ interface{}) error {
+	if !rdiff.NewValueKnown("https") {
+		return nil
+	}
+
+	for _, v := range rdiff.Get("https").(*schema.Set).List() {
+		params, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		certificateID, _ := params["certificate_id"].(string)
+		selfManaged, _ := params["self_managed"].([]interface{})
+
+		switch {
+		case certificateID != "" && len(selfManaged) > 0:
+			return fmt.Errorf("https: only one of certificate_id or self_managed can be specified")
+		case certificateID == "" && len(selfManaged) == 0:
+			return fmt.Errorf("https: one of certificate_id or self_managed must be specified")
+		}
+	}
+	return nil
+}
+
+// storageBucketVersioningDiffCustomize rejects suspending versioning of a bucket with object lock,
+// since object lock requires versioning and can't be disabled once enabled.
+func storageBucketVersioningDiffCustomize(_ context.Context, rdiff *schema.ResourceDiff, _ interface{}) error {
+	if !rdiff.NewValueKnown("versioning") {
+		return nil
+	}
+
+	v := rdiff.Get("versioning").([]interface{})
+	if len(v) == 0 || v[0] == nil || v[0].(map[string]interface{})["enabled"].(bool) {
+		return nil
+	}
+
+	oldLock, newLock := rdiff.GetChange("object_lock_configuration")
+	if isStorageBucketObjectLockEnabled(oldLock) || isStorageBucketObjectLockEnabled(newLock) {
+		return fmt.Errorf("versioning can't be disabled on bucket with object lock enabled, " +
+			"set versioning.0.enabled to true or remove the versioning block")
+	}
+
 	return nil
 }
 
+// Inventory configurations are hashed by their ID, so blocks with the same ID would silently
+// collapse into one. The raw configuration still has all of them.
+func storageBucketInventoryDiffCustomize(_ context.Context, rdiff *schema.ResourceDiff, _ interface{}) error {
+	rawConfig := rdiff.GetRawConfig()
+	if rawConfig.IsNull() || !rawConfig.IsKnown() {
+		return nil
+	}
+	return validateStorageBucketInventoryIDs(rawConfig.GetAttr("inventory"))
+}
+
+func validateStorageBucketInventoryIDs(rawInventory cty.Value) error {
+	if rawInventory.IsNull() || !rawInventory.IsKnown() {
+		return nil
+	}
+
+	ids := map[string]bool{}
+	for it := rawInventory.ElementIterator(); it.Next(); {
+		_, inventory := it.Element()
+		if inventory.IsNull() || !inventory.IsKnown() {
+			continue
+		}
+		id := inventory.GetAttr("id")
+		if id.IsNull() || !id.IsKnown() {
+			continue
+		}
+		if ids[id.AsString()] {
+			return fmt.Errorf("inventory id %q is used more than once", id.AsString())
+		}
+		ids[id.AsString()] = true
+	}
+	return nil
+}
+
+// A lifecycle action is scheduled either on a date or after a number of days,
+// check it on plan instead of letting the API reject the whole configuration.
+func storageBucketLifecycleDiffCustomize(_ context.Context, rdiff *schema.ResourceDiff, _ interface{}) error {
+	if !rdiff.HasChange("lifecycle_rule") {
+		return nil
+	}
+
+	oldRules, newRules := rdiff.GetChange("lifecycle_rule")
+
+	var rawRules cty.Value
+	if rawConfig := rdiff.GetRawConfig(); !rawConfig.IsNull() && rawConfig.IsKnown() {
+		rawRules = rawConfig.GetAttr("lifecycle_rule")
+	}
+	if err := validateStorageBucketLifecycleDateOrDays(newRules.([]interface{}), rawRules); err != nil {
+		return err
+	}
+
+	// Dates already applied are kept as is, a rule does not become invalid once its date has passed.
+	appliedDates := map[string]bool{}
+	for _, date := range storageBucketLifecycleDates(oldRules.([]interface{})) {
+		appliedDates[date] = true
+	}
+
+	now := time.Now()
+	for _, date := range storageBucketLifecycleDates(newRules.([]interface{})) {
+		if appliedDates[date] {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, fmt.Sprintf("%sT00:00:00Z", date))
+		if err != nil {
+			// reported by validateS3BucketLifecycleTimestamp
+			continue
+		}
+		if !t.After(now) {
+			return fmt.Errorf("lifecycle_rule date %q must be in the future", date)
+		}
+	}
+
+	return nil
+}
+
+// validateStorageBucketLifecycleDateOrDays checks that a transition has exactly one of date and days
+// and an expiration has at most one of them. days defaults to 0, which is a valid value for
+// transitions, so the raw configuration is used to tell an omitted days from a zero one.
+func validateStorageBucketLifecycleDateOrDays(rules []interface{}, rawRules cty.Value) error {
+	if rawRules.IsNull() || !rawRules.IsKnown() {
+		for i, r := range rules {
+			rule, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, e := range rule["expiration"].([]interface{}) {
+				if e, ok := e.(map[string]interface{}); ok && e["date"].(string) != "" && e["days"].(int) > 0 {
+					return fmt.Errorf("lifecycle_rule.%d.expiration: only one of date or days can be set", i)
+				}
+			}
+			for _, t := range rule["transition"].(*schema.Set).List() {
+				if t := t.(map[string]interface{}); t["date"].(string) != "" && t["days"].(int) > 0 {
+					return fmt.Errorf("lifecycle_rule.%d.transition: only one of date or days can be set", i)
+				}
+			}
+		}
+		return nil
+	}
+
+	i := 0
+	for it := rawRules.ElementIterator(); it.Next(); i++ {
+		_, rule := it.Element()
+		if rule.IsNull() || !rule.IsKnown() {
+			continue
+		}
+
+		if expiration := rule.GetAttr("expiration"); !expiration.IsNull() && expiration.IsKnown() {
+			for et := expiration.ElementIterator(); et.Next(); {
+				_, e := et.Element()
+				if !e.IsNull() && !e.GetAttr("date").IsNull() && !e.GetAttr("days").IsNull() {
+					return fmt.Errorf("lifecycle_rule.%d.expiration: only one of date or days can be set", i)
+				}
+			}
+		}
+
+		if transitions := rule.GetAttr("transition"); !transitions.IsNull() && transitions.IsKnown() {
+			for tt := transitions.ElementIterator(); tt.Next(); {
+				_, t := tt.Element()
+				if t.IsNull() {
+					continue
+				}
+				hasDate, hasDays := !t.GetAttr("date").IsNull(), !t.GetAttr("days").IsNull()
+				if hasDate && hasDays {
+					return fmt.Errorf("lifecycle_rule.%d.transition: only one of date or days can be set", i)
+				}
+				if !hasDate && !hasDays {
+					return fmt.Errorf("lifecycle_rule.%d.transition: one of date or days must be set", i)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func storageBucketLifecycleDates(rules []interface{}) []string {
+	var dates []string
+	for _, r := range rules {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, e := range rule["expiration"].([]interface{}) {
+			if e, ok := e.(map[string]interface{}); ok && e["date"].(string) != "" {
+				dates = append(dates, e["date"].(string))
+			}
+		}
+		for _, t := range rule["transition"].(*schema.Set).List() {
+			if date := t.(map[string]interface{})["date"].(string); date != "" {
+				dates = append(dates, date)
+			}
+		}
+	}
+	return dates
+}
+
+func isStorageBucketObjectLockEnabled(v interface{}) bool {
+	ol, ok := v.([]interface{})
+	if !ok || len(ol) == 0 || ol[0] == nil {
+		return false
+	}
+
+	return ol[0].(map[string]interface{})["object_lock_enabled"].(string) == s3.ObjectLockEnabledEnabled
+}
+
 type yandexStorageTaggingHandleFunc func([]*s3.Tag) error
 
 func resourceYandexStorageHandleTagsUpdate(
@@ -1939,9 +2830,21 @@ func resourceYandexStorageHandleTagsUpdate(
 func resourceYandexStorageBucketTagsUpdate(s3conn *s3.S3, d *schema.ResourceData) error {
 	bucket := aws.String(d.Get("bucket").(string))
 
-	onUpdate := func(tags []*s3.Tag) error {
-		log.Printf("[INFO] Updating Storage S3 bucket tags with %v", tags)
+	// PutBucketTagging replaces the whole tag set, so system tags set by the service
+	// have to be sent back along with the configured ones.
+	getSystemTags := func() ([]*s3.Tag, error) {
+		resp, err := retryFlakyS3Responses(func() (interface{}, error) {
+			return s3conn.GetBucketTagging(&s3.GetBucketTaggingInput{
+				Bucket: bucket,
+			})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error getting S3 Bucket tags: %w", err)
+		}
+		return mergeStorageSystemTags(nil, resp.(*s3.GetBucketTaggingOutput).TagSet), nil
+	}
 
+	putTags := func(tags []*s3.Tag) error {
 		request := &s3.PutBucketTaggingInput{
 			Bucket: bucket,
 			Tagging: &s3.Tagging{
@@ -1957,13 +2860,33 @@ func resourceYandexStorageBucketTagsUpdate(s3conn *s3.S3, d *schema.ResourceData
 		return err
 	}
 
+	onUpdate := func(tags []*s3.Tag) error {
+		systemTags, err := getSystemTags()
+		if err != nil {
+			return err
+		}
+
+		tags = mergeStorageSystemTags(tags, systemTags)
+		log.Printf("[INFO] Updating Storage S3 bucket tags with %v", tags)
+		return putTags(tags)
+	}
+
 	onDelete := func() error {
+		systemTags, err := getSystemTags()
+		if err != nil {
+			return err
+		}
+		if len(systemTags) > 0 {
+			log.Printf("[INFO] Deleting Storage S3 bucket tags, keeping system tags %v", systemTags)
+			return putTags(systemTags)
+		}
+
 		log.Printf("[INFO] Deleting Storage S3 bucket tags")
 
 		request := &s3.DeleteBucketTaggingInput{
 			Bucket: bucket,
 		}
-		_, err := retryFlakyS3Responses(func() (interface{}, error) {
+		_, err = retryFlakyS3Responses(func() (interface{}, error) {
 			return s3conn.DeleteBucketTagging(request)
 		})
 		if err != nil {
@@ -2227,6 +3150,83 @@ func waitCorsDeleted(s3Client *s3.S3, bucket string) error {
 	return nil
 }
 
+func waitReplicationPut(s3Client *s3.S3, bucket string, configuration *s3.ReplicationConfiguration) error {
+	input := &s3.GetBucketReplicationInput{Bucket: aws.String(bucket)}
+
+	check := func() (bool, error) {
+		output, err := s3Client.GetBucketReplication(input)
+		if isAWSErr(err, "ReplicationConfigurationNotFoundError", "") {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return storageBucketReplicationApplied(output.ReplicationConfiguration, configuration), nil
+	}
+
+	err := waitConditionStable(check)
+	if err != nil {
+		return fmt.Errorf("error assuring bucket %q replication updated: %s", bucket, err)
+	}
+	return nil
+}
+
+func waitReplicationDeleted(s3Client *s3.S3, bucket string) error {
+	input := &s3.GetBucketReplicationInput{Bucket: aws.String(bucket)}
+
+	check := func() (bool, error) {
+		_, err := s3Client.GetBucketReplication(input)
+		if isAWSErr(err, "ReplicationConfigurationNotFoundError", "") {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return false, nil
+	}
+
+	err := waitConditionStable(check)
+	if err != nil {
+		return fmt.Errorf("error assuring bucket %q replication deleted: %s", bucket, err)
+	}
+	return nil
+}
+
+// storageBucketReplicationApplied reports whether the actual configuration has the expected rules.
+// Rule IDs and storage classes are filled in by the server when not set, so they are compared only if expected.
+func storageBucketReplicationApplied(actual, expected *s3.ReplicationConfiguration) bool {
+	if actual == nil || aws.StringValue(actual.Role) != aws.StringValue(expected.Role) || len(actual.Rules) != len(expected.Rules) {
+		return false
+	}
+
+	for i, e := range expected.Rules {
+		a := actual.Rules[i]
+		if e.ID != nil && aws.StringValue(a.ID) != aws.StringValue(e.ID) {
+			return false
+		}
+		if aws.StringValue(a.Status) != aws.StringValue(e.Status) ||
+			aws.Int64Value(a.Priority) != aws.Int64Value(e.Priority) ||
+			storageBucketReplicationRulePrefix(a) != storageBucketReplicationRulePrefix(e) {
+			return false
+		}
+		if a.Destination == nil || aws.StringValue(a.Destination.Bucket) != aws.StringValue(e.Destination.Bucket) {
+			return false
+		}
+		if e.Destination.StorageClass != nil && aws.StringValue(a.Destination.StorageClass) != aws.StringValue(e.Destination.StorageClass) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func storageBucketReplicationRulePrefix(r *s3.ReplicationRule) string {
+	if r.Filter != nil && r.Filter.Prefix != nil {
+		return aws.StringValue(r.Filter.Prefix)
+	}
+	return aws.StringValue(r.Prefix)
+}
+
 // Returns true if the error matches all these conditions:
 //   - err is of type awserr.Error
 //   - Error.Code() matches code
@@ -2333,6 +3333,11 @@ func resourceYandexStorageBucketPolicyUpdate(s3conn *s3.S3, d *schema.ResourceDa
 		}
 		return nil
 	}
+	// Whitespace counts towards the policy size limit, so send the policy minified.
+	policy, err := NormalizeJsonString(policy)
+	if err != nil {
+		return fmt.Errorf("policy contains an invalid JSON: %s", err)
+	}
 	log.Printf("[DEBUG] S3 bucket: %s, put policy: %s", bucket, policy)
 
 	params := &s3.PutBucketPolicyInput{
@@ -2340,7 +3345,7 @@ func resourceYandexStorageBucketPolicyUpdate(s3conn *s3.S3, d *schema.ResourceDa
 		Policy: aws.String(policy),
 	}
 
-	err := resource.Retry(1*time.Minute, func() *resource.RetryError {
+	err = resource.Retry(1*time.Minute, func() *resource.RetryError {
 		_, err := s3conn.PutBucketPolicy(params)
 		if isAWSErr(err, "MalformedPolicy", "") || isAWSErr(err, s3.ErrCodeNoSuchBucket, "") {
 			return resource.RetryableError(err)
@@ -2640,6 +3645,227 @@ func resourceYandexStorageBucketServerSideEncryptionConfigurationUpdate(s3conn *
 	return nil
 }
 
+func resourceYandexStorageBucketReplicationConfigurationUpdate(s3conn *s3.S3, d *schema.ResourceData) error {
+	bucket := d.Get("bucket").(string)
+	replicationConfiguration := d.Get("replication_configuration").([]interface{})
+
+	if len(replicationConfiguration) == 0 {
+		log.Printf("[DEBUG] Storage Bucket: %s, delete replication configuration", bucket)
+
+		_, err := retryFlakyS3Responses(func() (interface{}, error) {
+			return s3conn.DeleteBucketReplication(&s3.DeleteBucketReplicationInput{
+				Bucket: aws.String(bucket),
+			})
+		})
+		if err == nil {
+			err = waitReplicationDeleted(s3conn, bucket)
+		}
+		if err != nil {
+			return fmt.Errorf("error removing S3 bucket replication configuration: %s", err)
+		}
+		return nil
+	}
+
+	rc := expandStorageBucketReplicationConfiguration(replicationConfiguration[0].(map[string]interface{}))
+	i := &s3.PutBucketReplicationInput{
+		Bucket:                   aws.String(bucket),
+		ReplicationConfiguration: rc,
+	}
+	log.Printf("[DEBUG] S3 put bucket replication configuration: %#v", i)
+
+	_, err := retryFlakyS3Responses(func() (interface{}, error) {
+		return s3conn.PutBucketReplication(i)
+	})
+	if err == nil {
+		err = waitReplicationPut(s3conn, bucket, rc)
+	}
+	if err != nil {
+		return fmt.Errorf("error putting S3 replication configuration: %s", err)
+	}
+
+	return nil
+}
+
+func expandStorageBucketReplicationConfiguration(c map[string]interface{}) *s3.ReplicationConfiguration {
+	rc := &s3.ReplicationConfiguration{
+		Role: aws.String(c["role"].(string)),
+	}
+
+	for _, v := range c["rules"].([]interface{}) {
+		rr := v.(map[string]interface{})
+		rule := &s3.ReplicationRule{
+			Status: aws.String(rr["status"].(string)),
+		}
+
+		if id := rr["id"].(string); id != "" {
+			rule.ID = aws.String(id)
+		}
+
+		// Priority can only be specified together with a filter, which in turn
+		// requires the delete marker replication to be set explicitly.
+		if priority := rr["priority"].(int); priority > 0 {
+			rule.Priority = aws.Int64(int64(priority))
+			rule.Filter = &s3.ReplicationRuleFilter{
+				Prefix: aws.String(rr["prefix"].(string)),
+			}
+			rule.DeleteMarkerReplication = &s3.DeleteMarkerReplication{
+				Status: aws.String(s3.DeleteMarkerReplicationStatusDisabled),
+			}
+		} else {
+			rule.Prefix = aws.String(rr["prefix"].(string))
+		}
+
+		destination := &s3.Destination{}
+		if dest, ok := rr["destination"].([]interface{}); ok && len(dest) > 0 && dest[0] != nil {
+			bd := dest[0].(map[string]interface{})
+			destination.Bucket = aws.String(bd["bucket"].(string))
+			if storageClass := bd["storage_class"].(string); storageClass != "" {
+				destination.StorageClass = aws.String(storageClass)
+			}
+		}
+		rule.Destination = destination
+
+		rc.Rules = append(rc.Rules, rule)
+	}
+
+	return rc
+}
+
+func flattenStorageBucketReplicationConfiguration(c *s3.ReplicationConfiguration) []map[string]interface{} {
+	rules := make([]interface{}, 0, len(c.Rules))
+	for _, v := range c.Rules {
+		rule := map[string]interface{}{
+			"id":     aws.StringValue(v.ID),
+			"status": aws.StringValue(v.Status),
+			"prefix": storageBucketReplicationRulePrefix(v),
+		}
+
+		if v.Priority != nil {
+			rule["priority"] = int(aws.Int64Value(v.Priority))
+		}
+		if v.Destination != nil {
+			rule["destination"] = []interface{}{
+				map[string]interface{}{
+					"bucket":        aws.StringValue(v.Destination.Bucket),
+					"storage_class": aws.StringValue(v.Destination.StorageClass),
+				},
+			}
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return []map[string]interface{}{
+		{
+			"role":  aws.StringValue(c.Role),
+			"rules": rules,
+		},
+	}
+}
+
+func listStorageBucketInventoryConfigurations(s3Client *s3.S3, bucket *string) ([]*s3.InventoryConfiguration, error) {
+	var configs []*s3.InventoryConfiguration
+	var token *string
+	for {
+		resp, err := retryFlakyS3Responses(func() (interface{}, error) {
+			return s3Client.ListBucketInventoryConfigurations(&s3.ListBucketInventoryConfigurationsInput{
+				Bucket:            bucket,
+				ContinuationToken: token,
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		out := resp.(*s3.ListBucketInventoryConfigurationsOutput)
+		configs = append(configs, out.InventoryConfigurationList...)
+		if !aws.BoolValue(out.IsTruncated) {
+			return configs, nil
+		}
+		token = out.NextContinuationToken
+	}
+}
+
+func expandStorageBucketInventoryConfiguration(c map[string]interface{}) *s3.InventoryConfiguration {
+	ic := &s3.InventoryConfiguration{
+		Id:                     aws.String(c["id"].(string)),
+		IsEnabled:              aws.Bool(c["enabled"].(bool)),
+		IncludedObjectVersions: aws.String(c["included_object_versions"].(string)),
+	}
+
+	if v, ok := c["schedule"].([]interface{}); ok && len(v) > 0 && v[0] != nil {
+		schedule := v[0].(map[string]interface{})
+		ic.Schedule = &s3.InventorySchedule{
+			Frequency: aws.String(schedule["frequency"].(string)),
+		}
+	}
+
+	if v, ok := c["destination"].([]interface{}); ok && len(v) > 0 && v[0] != nil {
+		dest := v[0].(map[string]interface{})
+		bucketDestination := &s3.InventoryS3BucketDestination{
+			Bucket: aws.String(dest["bucket"].(string)),
+			Format: aws.String(dest["format"].(string)),
+		}
+		if prefix := dest["prefix"].(string); prefix != "" {
+			bucketDestination.Prefix = aws.String(prefix)
+		}
+		ic.Destination = &s3.InventoryDestination{S3BucketDestination: bucketDestination}
+	}
+
+	if v, ok := c["optional_fields"].(*schema.Set); ok && v.Len() > 0 {
+		ic.OptionalFields = aws.StringSlice(convertStringSet(v))
+	}
+
+	return ic
+}
+
+func flattenStorageBucketInventoryConfigurations(configs []*s3.InventoryConfiguration) []interface{} {
+	result := make([]interface{}, 0, len(configs))
+	for _, c := range configs {
+		inventory := map[string]interface{}{
+			"id":                       aws.StringValue(c.Id),
+			"enabled":                  aws.BoolValue(c.IsEnabled),
+			"included_object_versions": aws.StringValue(c.IncludedObjectVersions),
+			"optional_fields":          schema.NewSet(schema.HashString, convertStringArrToInterface(aws.StringValueSlice(c.OptionalFields))),
+		}
+
+		if c.Schedule != nil {
+			inventory["schedule"] = []interface{}{
+				map[string]interface{}{
+					"frequency": aws.StringValue(c.Schedule.Frequency),
+				},
+			}
+		}
+
+		if c.Destination != nil && c.Destination.S3BucketDestination != nil {
+			dest := c.Destination.S3BucketDestination
+			inventory["destination"] = []interface{}{
+				map[string]interface{}{
+					"bucket": aws.StringValue(dest.Bucket),
+					"prefix": aws.StringValue(dest.Prefix),
+					"format": aws.StringValue(dest.Format),
+				},
+			}
+		}
+
+		result = append(result, inventory)
+	}
+
+	return result
+}
+
+// storageBucketInventoryHash keys inventory configurations by their ID, so that
+// changes of a configuration are applied in place.
+func storageBucketInventoryHash(v interface{}) int {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return 0
+	}
+
+	id, _ := m["id"].(string)
+	return hashcode.String(id)
+}
+
 func flattenGrants(ap *s3.GetBucketAclOutput) []interface{} {
 	//if ACL grants contains bucket owner FULL_CONTROL only - it is default "private" acl
 	if len(ap.Grants) == 1 && aws.StringValue(ap.Grants[0].Grantee.ID) == aws.StringValue(ap.Owner.ID) &&
@@ -2743,6 +3969,23 @@ func validateStringIsJSON(i interface{}, k string) (warnings []string, errors []
 	return warnings, errors
 }
 
+// validateStorageBucketPolicy checks that the policy is a valid JSON that fits
+// into the size limit once minified.
+func validateStorageBucketPolicy(i interface{}, k string) (warnings []string, errors []error) {
+	warnings, errors = validateStringIsJSON(i, k)
+	if len(errors) > 0 {
+		return warnings, errors
+	}
+
+	policy, _ := NormalizeJsonString(i)
+	if len(policy) > storageBucketPolicyMaxSize {
+		errors = append(errors, fmt.Errorf("%q is %d bytes after removing whitespace, the maximum policy size is %d bytes",
+			k, len(policy), storageBucketPolicyMaxSize))
+	}
+
+	return warnings, errors
+}
+
 func NormalizeJsonString(jsonString interface{}) (string, error) {
 	var j interface{}
 
@@ -2861,12 +4104,36 @@ func storageBucketTaggingNormalize(tags []*s3.Tag) map[string]string {
 
 	out := make(map[string]string, len(tags))
 	for _, tag := range tags {
+		if isStorageSystemTag(*tag.Key) {
+			continue
+		}
 		out[*tag.Key] = *tag.Value
 	}
 
+	if len(out) == 0 {
+		return nil
+	}
+
 	return out
 }
 
+// mergeStorageSystemTags appends system tags from current to tags unless tags already have them.
+func mergeStorageSystemTags(tags []*s3.Tag, current []*s3.Tag) []*s3.Tag {
+	keys := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		keys[*tag.Key] = true
+	}
+
+	for _, tag := range current {
+		if !isStorageSystemTag(*tag.Key) || keys[*tag.Key] {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
+
 func storageBucketTaggingFromMap(tags map[string]string) []*s3.Tag {
 	out := make([]*s3.Tag, 0, len(tags))
 	for k, v := range tags {
@@ -2,6 +2,7 @@ package yandex
 
 import (
 	"bytes"
+	"crypto/md5"
 	"encoding/base64"
 	"fmt"
 	"io"
@@ -86,6 +87,11 @@ func resourceYandexStorageObject() *schema.Resource {
 				Computed: true,
 			},
 
+			"website_redirect": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
 			"object_lock_legal_hold_status": {
 				Type:         schema.TypeString,
 				Optional:     true,
@@ -108,6 +114,14 @@ func resourceYandexStorageObject() *schema.Resource {
 				RequiredWith: []string{"object_lock_mode"},
 				ValidateFunc: validation.IsRFC3339Time,
 			},
+
+			"sse_customer_key": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ValidateFunc: validateStorageObjectSSECustomerKey,
+			},
+
 			"tags": tagsSchema(),
 		},
 	}
@@ -174,6 +188,10 @@ func resourceYandexStorageObjectCreate(d *schema.ResourceData, meta interface{})
 		putObjectInput.ContentType = aws.String(v.(string))
 	}
 
+	if v, ok := d.GetOk("website_redirect"); ok {
+		putObjectInput.WebsiteRedirectLocation = aws.String(v.(string))
+	}
+
 	if v, ok := d.GetOk("object_lock_legal_hold_status"); ok {
 		status := v.(string)
 		putObjectInput.SetObjectLockLegalHoldStatus(status)
@@ -188,6 +206,12 @@ func resourceYandexStorageObjectCreate(d *schema.ResourceData, meta interface{})
 		putObjectInput.SetObjectLockRetainUntilDate(untilDate)
 	}
 
+	if key, keyMD5, ok := storageObjectSSECustomerKey(d); ok {
+		putObjectInput.SSECustomerAlgorithm = aws.String(s3.ServerSideEncryptionAes256)
+		putObjectInput.SSECustomerKey = aws.String(key)
+		putObjectInput.SSECustomerKeyMD5 = aws.String(keyMD5)
+	}
+
 	log.Printf("[DEBUG] Sending putObjectInput %s", putObjectInput.String())
 
 	if _, err := s3conn.PutObject(putObjectInput); err != nil {
@@ -230,11 +254,18 @@ func resourceYandexStorageObjectRead(d *schema.ResourceData, meta interface{}) e
 	bucket := d.Get("bucket").(string)
 	key := d.Get("key").(string)
 
-	resp, err := s3conn.HeadObject(
-		&s3.HeadObjectInput{
-			Bucket: aws.String(bucket),
-			Key:    aws.String(key),
-		})
+	headObjectInput := &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	// Objects encrypted with a customer-provided key can be read only with the same key.
+	if sseKey, sseKeyMD5, ok := storageObjectSSECustomerKey(d); ok {
+		headObjectInput.SSECustomerAlgorithm = aws.String(s3.ServerSideEncryptionAes256)
+		headObjectInput.SSECustomerKey = aws.String(sseKey)
+		headObjectInput.SSECustomerKeyMD5 = aws.String(sseKeyMD5)
+	}
+
+	resp, err := s3conn.HeadObject(headObjectInput)
 	if err != nil {
 		// If S3 returns a 404 Request Failure, mark the object as destroyed
 		if awsErr, ok := err.(awserr.RequestFailure); ok && awsErr.StatusCode() == 404 {
@@ -247,6 +278,7 @@ func resourceYandexStorageObjectRead(d *schema.ResourceData, meta interface{}) e
 	log.Printf("[DEBUG] Reading storage object meta: %s", resp)
 
 	d.Set("content_type", resp.ContentType)
+	d.Set("website_redirect", resp.WebsiteRedirectLocation)
 
 	if resp.ObjectLockLegalHoldStatus != nil {
 		status := aws.StringValue(resp.ObjectLockLegalHoldStatus)
@@ -324,6 +356,8 @@ func hasObjectContentChanged(d *schema.ResourceData) bool {
 		"content",
 		"content_base64",
 		"content_type",
+		"website_redirect",
+		"sse_customer_key",
 	} {
 		if d.HasChange(key) {
 			return true
@@ -461,3 +495,30 @@ func resourceYandexStorageObjectDelete(d *schema.ResourceData, meta interface{})
 
 	return nil
 }
+
+// validateStorageObjectSSECustomerKey checks that the key is a base64-encoded 256-bit key.
+func validateStorageObjectSSECustomerKey(v interface{}, k string) (ws []string, errors []error) {
+	key, err := base64.StdEncoding.DecodeString(v.(string))
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q must be base64-encoded: %s", k, err))
+		return
+	}
+	if len(key) != 32 {
+		errors = append(errors, fmt.Errorf("%q must be a 256-bit key, got %d bytes after decoding", k, len(key)))
+	}
+	return
+}
+
+// storageObjectSSECustomerKey returns the decoded customer-provided encryption key
+// and its base64-encoded MD5 digest, if the key is configured.
+func storageObjectSSECustomerKey(d *schema.ResourceData) (key string, keyMD5 string, ok bool) {
+	v, ok := d.GetOk("sse_customer_key")
+	if !ok {
+		return "", "", false
+	}
+
+	// The value has been validated by the schema already.
+	raw, _ := base64.StdEncoding.DecodeString(v.(string))
+	sum := md5.Sum(raw)
+	return string(raw), base64.StdEncoding.EncodeToString(sum[:]), true
+}
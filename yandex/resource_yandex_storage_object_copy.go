@@ -0,0 +1,308 @@
+package yandex
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Metadata keys are case-insensitive and are always read back in lowercase.
+var storageObjectMetadataKeyRegexp = regexp.MustCompile(`^[^A-Z]+$`)
+
+func resourceYandexStorageObjectCopy() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceYandexStorageObjectCopyCreate,
+		Read:   resourceYandexStorageObjectCopyRead,
+		Update: resourceYandexStorageObjectCopyUpdate,
+		Delete: resourceYandexStorageObjectDelete,
+
+		SchemaVersion: 0,
+
+		CustomizeDiff: storageObjectCopyMetadataDiffCustomize,
+
+		Schema: map[string]*schema.Schema{
+			"bucket": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"key": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"access_key": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"secret_key": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+
+			"source": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateStorageObjectCopySource,
+			},
+
+			"source_version_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"acl": {
+				Type:     schema.TypeString,
+				Default:  "private",
+				Optional: true,
+			},
+
+			"metadata_directive": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      s3.MetadataDirectiveCopy,
+				ValidateFunc: validation.StringInSlice(s3.MetadataDirective_Values(), false),
+			},
+
+			"metadata": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				ValidateDiagFunc: validation.MapKeyMatch(
+					storageObjectMetadataKeyRegexp, "metadata keys must be lowercase"),
+			},
+
+			"content_type": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"tags": tagsSchema(),
+
+			"etag": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"last_modified": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// storageObjectCopyMetadataDiffCustomize rejects metadata and content_type unless metadata_directive is REPLACE,
+// since they are not sent otherwise and the plan would never converge.
+func storageObjectCopyMetadataDiffCustomize(_ context.Context, rdiff *schema.ResourceDiff, _ interface{}) error {
+	if !rdiff.NewValueKnown("metadata_directive") || rdiff.Get("metadata_directive").(string) == s3.MetadataDirectiveReplace {
+		return nil
+	}
+
+	rawConfig := rdiff.GetRawConfig()
+	for _, key := range []string{"metadata", "content_type"} {
+		var configured bool
+		if rawConfig.IsNull() {
+			_, configured = rdiff.GetOk(key)
+		} else {
+			configured = !rawConfig.GetAttr(key).IsNull()
+		}
+		if configured {
+			return fmt.Errorf("%s can be set only when metadata_directive is %q", key, s3.MetadataDirectiveReplace)
+		}
+	}
+	return nil
+}
+
+func resourceYandexStorageObjectCopyCreate(d *schema.ResourceData, meta interface{}) error {
+	if err := resourceYandexStorageObjectCopyDoCopy(d, meta); err != nil {
+		return err
+	}
+
+	d.SetId(d.Get("key").(string))
+
+	return resourceYandexStorageObjectCopyRead(d, meta)
+}
+
+func resourceYandexStorageObjectCopyDoCopy(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	s3conn, err := getS3Client(d, config)
+	if err != nil {
+		return fmt.Errorf("error getting storage client: %s", err)
+	}
+
+	bucket := d.Get("bucket").(string)
+	key := d.Get("key").(string)
+	source := d.Get("source").(string)
+
+	copySource := url.QueryEscape(source)
+	if v, ok := d.GetOk("source_version_id"); ok {
+		copySource = fmt.Sprintf("%s?versionId=%s", copySource, url.QueryEscape(v.(string)))
+	}
+
+	input := &s3.CopyObjectInput{
+		Bucket:            aws.String(bucket),
+		Key:               aws.String(key),
+		CopySource:        aws.String(copySource),
+		ACL:               aws.String(d.Get("acl").(string)),
+		MetadataDirective: aws.String(d.Get("metadata_directive").(string)),
+	}
+
+	// Metadata and content type of the destination object can only be set
+	// with the REPLACE directive, otherwise they are copied from the source.
+	if d.Get("metadata_directive").(string) == s3.MetadataDirectiveReplace {
+		if v, ok := d.GetOk("metadata"); ok {
+			input.Metadata = aws.StringMap(convertTypesMap(v))
+		}
+		if v, ok := d.GetOk("content_type"); ok {
+			input.ContentType = aws.String(v.(string))
+		}
+	}
+
+	// Tags of the source object are not copied, the destination gets only the configured ones.
+	input.TaggingDirective = aws.String(s3.TaggingDirectiveReplace)
+	if v, ok := d.GetOk("tags"); ok {
+		tags := url.Values{}
+		for k, v := range convertTypesMap(v) {
+			tags.Set(k, v)
+		}
+		input.Tagging = aws.String(tags.Encode())
+	}
+
+	log.Printf("[DEBUG] Trying to copy storage object %q to %q in bucket %q", source, key, bucket)
+
+	resp, err := s3conn.CopyObject(input)
+	if err != nil {
+		return fmt.Errorf("error copying storage object %q to %q in bucket %q: %w", source, key, bucket, err)
+	}
+
+	if resp.CopyObjectResult != nil {
+		d.Set("etag", strings.Trim(aws.StringValue(resp.CopyObjectResult.ETag), `"`))
+	}
+
+	return nil
+}
+
+func resourceYandexStorageObjectCopyRead(d *schema.ResourceData, meta interface{}) error {
+	config := meta.(*Config)
+	s3conn, err := getS3Client(d, config)
+	if err != nil {
+		return fmt.Errorf("error getting storage client: %s", err)
+	}
+
+	bucket := d.Get("bucket").(string)
+	key := d.Get("key").(string)
+
+	resp, err := s3conn.HeadObject(
+		&s3.HeadObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+	if err != nil {
+		// If S3 returns a 404 Request Failure, mark the object as destroyed
+		if awsErr, ok := err.(awserr.RequestFailure); ok && awsErr.StatusCode() == 404 {
+			d.SetId("")
+			log.Printf("[WARN] Error Reading Object (%s), object not found (HTTP status 404)", key)
+			return nil
+		}
+		return err
+	}
+	log.Printf("[DEBUG] Reading storage object copy meta: %s", resp)
+
+	d.Set("content_type", resp.ContentType)
+	d.Set("etag", strings.Trim(aws.StringValue(resp.ETag), `"`))
+	if resp.LastModified != nil {
+		d.Set("last_modified", resp.LastModified.Format(time.RFC3339))
+	}
+
+	if err := d.Set("metadata", normalizeStorageObjectMetadata(resp.Metadata)); err != nil {
+		return fmt.Errorf("error setting storage object metadata: %s", err)
+	}
+
+	tagsResponseRaw, err := retryFlakyS3Responses(func() (interface{}, error) {
+		return s3conn.GetObjectTagging(&s3.GetObjectTaggingInput{
+			Bucket:    aws.String(bucket),
+			Key:       aws.String(key),
+			VersionId: resp.VersionId,
+		})
+	})
+	if err != nil {
+		log.Printf("[ERROR] Unable to get S3 Storage Object Tagging: %s", err)
+		return err
+	}
+
+	tagsResponse := tagsResponseRaw.(*s3.GetObjectTaggingOutput)
+
+	tags := storageBucketTaggingNormalize(tagsResponse.TagSet)
+	err = d.Set("tags", tags)
+	if err != nil {
+		return fmt.Errorf("error setting S3 Storage Object Tagging: %w", err)
+	}
+
+	return nil
+}
+
+func resourceYandexStorageObjectCopyUpdate(d *schema.ResourceData, meta interface{}) error {
+	if d.HasChanges("source", "source_version_id", "metadata_directive", "metadata", "content_type") {
+		if err := resourceYandexStorageObjectCopyDoCopy(d, meta); err != nil {
+			return err
+		}
+		return resourceYandexStorageObjectCopyRead(d, meta)
+	}
+
+	config := meta.(*Config)
+	s3Client, err := getS3Client(d, config)
+	if err != nil {
+		return fmt.Errorf("error getting storage client: %s", err)
+	}
+
+	if d.HasChange("acl") {
+		if err := resourceYandexStorageObjectACLUpdate(s3Client, d); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("tags") {
+		if err := resourceYandexStorageObjectTaggingUpdate(s3Client, d); err != nil {
+			return err
+		}
+	}
+
+	return resourceYandexStorageObjectCopyRead(d, meta)
+}
+
+// validateStorageObjectCopySource checks that source is in "bucket/key" format.
+func validateStorageObjectCopySource(v interface{}, k string) (ws []string, errors []error) {
+	source := v.(string)
+	parts := strings.SplitN(strings.TrimPrefix(source, "/"), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		errors = append(errors, fmt.Errorf("%q must be in \"bucket/key\" format, got %q", k, source))
+	}
+	return
+}
+
+// normalizeStorageObjectMetadata lowercases metadata keys, since they are
+// case-insensitive and returned by the SDK in canonical header form.
+func normalizeStorageObjectMetadata(metadata map[string]*string) map[string]string {
+	result := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		result[strings.ToLower(k)] = aws.StringValue(v)
+	}
+	return result
+}
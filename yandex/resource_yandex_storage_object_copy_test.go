@@ -0,0 +1,181 @@
+package yandex
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestValidateStorageObjectCopySource(t *testing.T) {
+	cc := []struct {
+		source  string
+		wantErr bool
+	}{
+		{source: "bucket/key"},
+		{source: "bucket/path/to/key"},
+		{source: "/bucket/key"},
+		{source: "bucket", wantErr: true},
+		{source: "bucket/", wantErr: true},
+		{source: "/key", wantErr: true},
+		{source: "", wantErr: true},
+	}
+
+	for _, c := range cc {
+		t.Run(c.source, func(t *testing.T) {
+			_, errs := validateStorageObjectCopySource(c.source, "source")
+			if c.wantErr != (len(errs) > 0) {
+				t.Fatalf("validateStorageObjectCopySource(%q) errors = %v, want error: %v", c.source, errs, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestNormalizeStorageObjectMetadata(t *testing.T) {
+	actual := normalizeStorageObjectMetadata(map[string]*string{
+		"Owner":      aws.String("team-a"),
+		"Build-Hash": aws.String("abc"),
+	})
+	expected := map[string]string{
+		"owner":      "team-a",
+		"build-hash": "abc",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Got %v, expected %v", actual, expected)
+	}
+}
+
+func TestStorageObjectCopyMetadataDiffCustomize(t *testing.T) {
+	cases := []struct {
+		name        string
+		extra       map[string]interface{}
+		expectError string
+	}{
+		{
+			name: "copy",
+		},
+		{
+			name:  "replace with metadata",
+			extra: map[string]interface{}{"metadata_directive": "REPLACE", "metadata": map[string]interface{}{"owner": "tf-test"}, "content_type": "text/plain"},
+		},
+		{
+			name:        "copy with metadata",
+			extra:       map[string]interface{}{"metadata": map[string]interface{}{"owner": "tf-test"}},
+			expectError: `metadata can be set only when metadata_directive is "REPLACE"`,
+		},
+		{
+			name:        "copy with content type",
+			extra:       map[string]interface{}{"metadata_directive": "COPY", "content_type": "text/plain"},
+			expectError: `content_type can be set only when metadata_directive is "REPLACE"`,
+		},
+	}
+
+	r := resourceYandexStorageObjectCopy()
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			raw := map[string]interface{}{
+				"bucket": "target-bucket",
+				"key":    "copied-key",
+				"source": "source-bucket/source-key",
+			}
+			for k, v := range c.extra {
+				raw[k] = v
+			}
+
+			_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), nil)
+			if c.expectError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), c.expectError) {
+				t.Fatalf("expected error containing %q, got %v", c.expectError, err)
+			}
+		})
+	}
+}
+
+func TestAccStorageObjectCopy_crossBucket(t *testing.T) {
+	var obj s3.GetObjectOutput
+	resourceName := "yandex_storage_object_copy.test"
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckStorageObjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStorageObjectCopyConfig(rInt, "COPY", ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStorageObjectExists(resourceName, &obj),
+					testAccCheckStorageObjectBody(&obj, "some_bucket_content"),
+					testAccCheckStorageObjectContentType(&obj, "text/plain"),
+					resource.TestCheckResourceAttr(resourceName, "content_type", "text/plain"),
+					resource.TestCheckResourceAttrSet(resourceName, "etag"),
+				),
+			},
+			{
+				Config: testAccStorageObjectCopyConfig(rInt, "REPLACE", `
+	content_type = "application/octet-stream"
+	metadata = {
+		owner = "tf-test"
+	}
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStorageObjectExists(resourceName, &obj),
+					testAccCheckStorageObjectBody(&obj, "some_bucket_content"),
+					testAccCheckStorageObjectContentType(&obj, "application/octet-stream"),
+					resource.TestCheckResourceAttr(resourceName, "metadata.%", "1"),
+					resource.TestCheckResourceAttr(resourceName, "metadata.owner", "tf-test"),
+				),
+			},
+		},
+	})
+}
+
+func testAccStorageObjectCopyConfig(randInt int, directive, extra string) string {
+	bucketConfig := newBucketConfigBuilder(randInt).asEditor().render()
+
+	objectConfig := fmt.Sprintf(`
+resource "yandex_storage_bucket" "target" {
+	bucket = "tf-test-bucket-target-%[1]d"
+
+	access_key = yandex_iam_service_account_static_access_key.sa-key.access_key
+	secret_key = yandex_iam_service_account_static_access_key.sa-key.secret_key
+}
+
+resource "yandex_storage_object" "source" {
+	bucket = "${yandex_storage_bucket.test.bucket}"
+
+	access_key = yandex_iam_service_account_static_access_key.sa-key.access_key
+	secret_key = yandex_iam_service_account_static_access_key.sa-key.secret_key
+
+	key          = "source-key"
+	content      = "some_bucket_content"
+	content_type = "text/plain"
+}
+
+resource "yandex_storage_object_copy" "test" {
+	bucket = "${yandex_storage_bucket.target.bucket}"
+	key    = "copied-key"
+	source = "${yandex_storage_object.source.bucket}/${yandex_storage_object.source.key}"
+
+	access_key = yandex_iam_service_account_static_access_key.sa-key.access_key
+	secret_key = yandex_iam_service_account_static_access_key.sa-key.secret_key
+
+	metadata_directive = "%[2]s"
+%[3]s
+}
+`, randInt, directive, extra)
+
+	return bucketConfig + objectConfig
+}
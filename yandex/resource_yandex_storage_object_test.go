@@ -2,6 +2,7 @@
 package yandex
 
 import (
+	"crypto/md5"
 	"encoding/base64"
 	"fmt"
 	"io/ioutil"
@@ -104,6 +105,57 @@ func testSweepStorageObject(_ string) error {
 	return result.ErrorOrNil()
 }
 
+func TestValidateStorageObjectSSECustomerKey(t *testing.T) {
+	cc := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{name: "256-bit key", key: base64.StdEncoding.EncodeToString(make([]byte, 32))},
+		{name: "128-bit key", key: base64.StdEncoding.EncodeToString(make([]byte, 16)), wantErr: true},
+		{name: "not base64", key: "not a base64 key", wantErr: true},
+		{name: "empty", key: "", wantErr: true},
+	}
+
+	for _, c := range cc {
+		t.Run(c.name, func(t *testing.T) {
+			_, errs := validateStorageObjectSSECustomerKey(c.key, "sse_customer_key")
+			if c.wantErr != (len(errs) > 0) {
+				t.Fatalf("validateStorageObjectSSECustomerKey(%q) errors = %v, want error: %v", c.key, errs, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestStorageObjectSSECustomerKey(t *testing.T) {
+	raw := []byte("0123456789abcdef0123456789abcdef")
+	d := schema.TestResourceDataRaw(t, resourceYandexStorageObject().Schema, map[string]interface{}{
+		"bucket":           "bucket",
+		"key":              "key",
+		"sse_customer_key": base64.StdEncoding.EncodeToString(raw),
+	})
+
+	key, keyMD5, ok := storageObjectSSECustomerKey(d)
+	if !ok {
+		t.Fatalf("storageObjectSSECustomerKey() reported no key")
+	}
+	if key != string(raw) {
+		t.Fatalf("storageObjectSSECustomerKey() key = %q, want decoded key %q", key, raw)
+	}
+	sum := md5.Sum(raw)
+	if want := base64.StdEncoding.EncodeToString(sum[:]); keyMD5 != want {
+		t.Fatalf("storageObjectSSECustomerKey() key MD5 = %q, want %q", keyMD5, want)
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceYandexStorageObject().Schema, map[string]interface{}{
+		"bucket": "bucket",
+		"key":    "key",
+	})
+	if _, _, ok := storageObjectSSECustomerKey(d); ok {
+		t.Fatalf("storageObjectSSECustomerKey() reported a key that is not configured")
+	}
+}
+
 func TestAccStorageObject_source(t *testing.T) {
 	var obj s3.GetObjectOutput
 	resourceName := "yandex_storage_object.test"
@@ -261,6 +313,38 @@ func TestAccStorageObject_contentTypeText(t *testing.T) {
 	})
 }
 
+func TestAccStorageObject_websiteRedirect(t *testing.T) {
+	var obj s3.GetObjectOutput
+	resourceName := "yandex_storage_object.test"
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:        func() { testAccPreCheck(t) },
+		IDRefreshName:   resourceName,
+		IDRefreshIgnore: []string{"access_key", "secret_key"},
+		Providers:       testAccProviders,
+		CheckDestroy:    testAccCheckStorageObjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStorageObjectConfigWebsiteRedirect(rInt, "/index.html"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStorageObjectExists(resourceName, &obj),
+					testAccCheckStorageObjectWebsiteRedirect(&obj, "/index.html"),
+					resource.TestCheckResourceAttr(resourceName, "website_redirect", "/index.html"),
+				),
+			},
+			{
+				Config: testAccStorageObjectConfigWebsiteRedirect(rInt, "https://example.com/"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStorageObjectExists(resourceName, &obj),
+					testAccCheckStorageObjectWebsiteRedirect(&obj, "https://example.com/"),
+					resource.TestCheckResourceAttr(resourceName, "website_redirect", "https://example.com/"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccStorageObject_updateAcl(t *testing.T) {
 	var obj s3.GetObjectOutput
 	rInt := acctest.RandInt()
@@ -371,8 +455,32 @@ func TestAccStorageObject_LegalHoldOff(t *testing.T) {
 	})
 }
 
+func TestAccStorageObject_SSECustomerKey(t *testing.T) {
+	resourceName := "yandex_storage_object.test"
+	rInt := acctest.RandInt()
+	sseKey := base64.StdEncoding.EncodeToString([]byte(acctest.RandString(32)))
+
+	// The customer-provided key is never returned by the API and an object encrypted
+	// with it can't be read without the key, so the ID-only refresh check is not used.
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckStorageObjectDestroy,
+		ErrorCheck:   checkErrorSkipNotImplemented(t),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccStorageObjectConfigSSECustomerKey(rInt, "some_encrypted_content", sseKey),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStorageObjectSSECustomerKeyBody(resourceName, sseKey, "some_encrypted_content"),
+					resource.TestCheckResourceAttr(resourceName, "sse_customer_key", sseKey),
+				),
+			},
+		},
+	})
+}
+
 func TestAccStorageObject_Tagging(t *testing.T) {
-	var obj s3.GetObjectOutput
+	var obj, objBefore s3.GetObjectOutput
 	rInt := acctest.RandInt()
 	resourceName := "yandex_storage_object.test"
 
@@ -401,6 +509,17 @@ func TestAccStorageObject_Tagging(t *testing.T) {
 					testAccCheckStorageObjectExists(resourceName, &obj),
 					testAccCheckStorageObjectTagging(resourceName, &obj, tags),
 					resource.TestCheckResourceAttr(resourceName, "tags.%", "2"),
+					testAccCheckStorageObjectExists(resourceName, &objBefore),
+				),
+			},
+			{
+				Config: testAccStorageObjectTagsPreConfig(rInt, tags[:1]),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStorageObjectExists(resourceName, &obj),
+					testAccCheckStorageObjectTagging(resourceName, &obj, tags[:1]),
+					testAccCheckStorageObjectNotReuploaded(&objBefore, &obj),
+					resource.TestCheckResourceAttr(resourceName, "tags.%", "1"),
+					resource.TestCheckResourceAttr(resourceName, "tags.A", "B"),
 				),
 			},
 			{
@@ -435,7 +554,7 @@ func testAccCheckStorageObjectDestroyWithProvider(s *terraform.State, provider *
 	}
 
 	for _, rs := range s.RootModule().Resources {
-		if rs.Type != "yandex_storage_object" {
+		if rs.Type != "yandex_storage_object" && rs.Type != "yandex_storage_object_copy" {
 			continue
 		}
 
@@ -483,6 +602,55 @@ func testAccCheckStorageObjectExists(n string, obj *s3.GetObjectOutput) resource
 	}
 }
 
+func testAccCheckStorageObjectNotReuploaded(before, after *s3.GetObjectOutput) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if !aws.TimeValue(before.LastModified).Equal(aws.TimeValue(after.LastModified)) {
+			return fmt.Errorf("storage object was re-uploaded: last modified changed from %s to %s",
+				aws.TimeValue(before.LastModified), aws.TimeValue(after.LastModified))
+		}
+		return nil
+	}
+}
+
+func testAccCheckStorageObjectSSECustomerKeyBody(n string, sseKey string, want string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("not found: %s", n)
+		}
+
+		s3conn, err := getS3ClientByKeys(rs.Primary.Attributes["access_key"], rs.Primary.Attributes["secret_key"],
+			testAccProvider.Meta().(*Config))
+		if err != nil {
+			return err
+		}
+
+		key, err := base64.StdEncoding.DecodeString(sseKey)
+		if err != nil {
+			return err
+		}
+
+		if _, err := s3conn.HeadObject(&s3.HeadObjectInput{
+			Bucket: aws.String(rs.Primary.Attributes["bucket"]),
+			Key:    aws.String(rs.Primary.Attributes["key"]),
+		}); err == nil {
+			return fmt.Errorf("storage object can be read without the customer-provided key")
+		}
+
+		out, err := s3conn.GetObject(&s3.GetObjectInput{
+			Bucket:               aws.String(rs.Primary.Attributes["bucket"]),
+			Key:                  aws.String(rs.Primary.Attributes["key"]),
+			SSECustomerAlgorithm: aws.String(s3.ServerSideEncryptionAes256),
+			SSECustomerKey:       aws.String(string(key)),
+		})
+		if err != nil {
+			return fmt.Errorf("storage object error: %s", err)
+		}
+
+		return testAccCheckStorageObjectBody(out, want)(s)
+	}
+}
+
 func testAccCheckStorageObjectBody(obj *s3.GetObjectOutput, want string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		body, err := ioutil.ReadAll(obj.Body)
@@ -508,6 +676,16 @@ func testAccCheckStorageObjectContentType(obj *s3.GetObjectOutput, want string)
 	}
 }
 
+func testAccCheckStorageObjectWebsiteRedirect(obj *s3.GetObjectOutput, want string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if got := aws.StringValue(obj.WebsiteRedirectLocation); got != want {
+			return fmt.Errorf("wrong result website_redirect %q; want %q", got, want)
+		}
+
+		return nil
+	}
+}
+
 func testAccCheckStorageObjectLegalHoldStatus(obj *s3.GetObjectOutput, want string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		if got := aws.StringValue(obj.ObjectLockLegalHoldStatus); got != want {
@@ -538,8 +716,8 @@ func testAccCheckStorageObjectTagging(name string, obj *s3.GetObjectOutput, tags
 		switch {
 		case len(tags) > 0 && obj.TagCount == nil:
 			return fmt.Errorf("no object tags found but expected %d", len(tags))
-		case *obj.TagCount != int64(len(tags)):
-			return fmt.Errorf("expected tags count %d got %d", len(tags), *obj.TagCount)
+		case aws.Int64Value(obj.TagCount) != int64(len(tags)):
+			return fmt.Errorf("expected tags count %d got %d", len(tags), aws.Int64Value(obj.TagCount))
 		}
 
 		rs, ok := s.RootModule().Resources[name]
@@ -576,7 +754,7 @@ func testAccCheckStorageObjectTagging(name string, obj *s3.GetObjectOutput, tags
 				return fmt.Errorf("expected key not found: %s", k)
 			}
 
-			if k != gotV {
+			if v != gotV {
 				return fmt.Errorf(
 					"unequal values for key %s\nexp: %s got %s",
 					k, v, gotV,
@@ -659,6 +837,25 @@ resource "yandex_storage_object" "test" {
 	return bucketConfig + objectConfig
 }
 
+func testAccStorageObjectConfigSSECustomerKey(randInt int, content, sseKey string) string {
+	bucketConfig := newBucketConfigBuilder(randInt).asEditor().render()
+
+	objectConfig := fmt.Sprintf(`
+resource "yandex_storage_object" "test" {
+	bucket = "${yandex_storage_bucket.test.bucket}"
+
+	access_key = yandex_iam_service_account_static_access_key.sa-key.access_key
+	secret_key = yandex_iam_service_account_static_access_key.sa-key.secret_key
+
+	key              = "test-key"
+	content          = "%s"
+	sse_customer_key = "%s"
+}
+`, content, sseKey)
+
+	return bucketConfig + objectConfig
+}
+
 func testAccStorageObjectConfigContentBase64(randInt int, contentBase64 string) string {
 	bucketConfig := newBucketConfigBuilder(randInt).asEditor().render()
 
@@ -696,6 +893,25 @@ resource "yandex_storage_object" "test" {
 	return bucketConfig + objectConfig
 }
 
+func testAccStorageObjectConfigWebsiteRedirect(randInt int, redirect string) string {
+	bucketConfig := newBucketConfigBuilder(randInt).asEditor().render()
+
+	objectConfig := fmt.Sprintf(`
+resource "yandex_storage_object" "test" {
+	bucket = "${yandex_storage_bucket.test.bucket}"
+
+	access_key = yandex_iam_service_account_static_access_key.sa-key.access_key
+	secret_key = yandex_iam_service_account_static_access_key.sa-key.secret_key
+
+	key              = "test-key"
+	content          = "some-content"
+	website_redirect = "%[1]s"
+}
+`, redirect)
+
+	return bucketConfig + objectConfig
+}
+
 func testAccStorageObjectAclPreConfig(randInt int) string {
 	bucketConfig := newBucketConfigBuilder(randInt).asAdmin().render()
 
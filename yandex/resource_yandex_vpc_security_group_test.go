@@ -188,6 +188,43 @@ func TestAccVPCSecurityGroup_update(t *testing.T) {
 	})
 }
 
+func TestAccVPCSecurityGroup_updateLabels(t *testing.T) {
+	t.Parallel()
+
+	var securityGroup vpc.SecurityGroup
+
+	networkName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+	sgName := fmt.Sprintf("tf-test-%s", acctest.RandString(10))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVPCSecurityGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVPCSecurityGroupLabels(networkName, sgName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVPCSecurityGroupExists("yandex_vpc_security_group.sg1", &securityGroup),
+					resource.TestCheckResourceAttr("yandex_vpc_security_group.sg1", "labels.%", "1"),
+				),
+			},
+			{
+				Config: testAccVPCSecurityGroupLabels(networkName, sgName, "new-label = \"new-label-value\""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("yandex_vpc_security_group.sg1", "id", &securityGroup.Id),
+					resource.TestCheckResourceAttr("yandex_vpc_security_group.sg1", "labels.%", "2"),
+					resource.TestCheckResourceAttr("yandex_vpc_security_group.sg1", "labels.new-label", "new-label-value"),
+				),
+			},
+			{
+				ResourceName:      "yandex_vpc_security_group.sg1",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func testAccCheckVPCSecurityGroupExists(name string, securityGroup *vpc.SecurityGroup) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[name]
@@ -306,6 +343,33 @@ resource "yandex_vpc_security_group" "sg1" {
 `, networkName, sg1Name, getExampleFolderID())
 }
 
+func testAccVPCSecurityGroupLabels(networkName, sgName, extraLabel string) string {
+	return fmt.Sprintf(`
+resource "yandex_vpc_network" "foo" {
+  name = "%s"
+}
+
+resource "yandex_vpc_security_group" "sg1" {
+  name        = "%s"
+  description = "description for security group"
+  network_id  = "${yandex_vpc_network.foo.id}"
+  folder_id   = "%s"
+
+  labels = {
+    tf-label = "tf-label-value-a"
+    %s
+  }
+
+  ingress {
+    description    = "rule1 description"
+    protocol       = "TCP"
+    v4_cidr_blocks = ["10.0.1.0/24"]
+    port           = 8080
+  }
+}
+`, networkName, sgName, getExampleFolderID(), extraLabel)
+}
+
 func testAccCheckVPCSecurityGroupDestroy(s *terraform.State) error {
 	config := testAccProvider.Meta().(*Config)
 
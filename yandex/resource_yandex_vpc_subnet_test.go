@@ -281,6 +281,52 @@ func TestAccVPCSubnet_withRouteTable(t *testing.T) {
 	})
 }
 
+func TestAccVPCSubnet_routeTableInPlace(t *testing.T) {
+	var subnet vpc.Subnet
+	var subnetID string
+
+	networkName := acctest.RandomWithPrefix("tf-network")
+	subnetName := acctest.RandomWithPrefix("tf-subnet-a")
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVPCSubnetDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVPCSubnet_routeTableAssociation(networkName, subnetName, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVPCSubnetExists("yandex_vpc_subnet.subnet-a", &subnet),
+					testAccCheckVPCSubnetRouteTableIdValue(&subnet, ""),
+					func(s *terraform.State) error {
+						subnetID = subnet.Id
+						return nil
+					},
+				),
+			},
+			// Attach the route table, the subnet must be updated in place
+			{
+				Config: testAccVPCSubnet_routeTableAssociation(networkName, subnetName, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVPCSubnetExists("yandex_vpc_subnet.subnet-a", &subnet),
+					resource.TestCheckResourceAttrPtr("yandex_vpc_subnet.subnet-a", "id", &subnetID),
+					resource.TestCheckResourceAttrPair("yandex_vpc_subnet.subnet-a", "route_table_id", "yandex_vpc_route_table.rt-a", "id"),
+				),
+			},
+			// Detach the route table, the subnet must be updated in place
+			{
+				Config: testAccVPCSubnet_routeTableAssociation(networkName, subnetName, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVPCSubnetExists("yandex_vpc_subnet.subnet-a", &subnet),
+					resource.TestCheckResourceAttrPtr("yandex_vpc_subnet.subnet-a", "id", &subnetID),
+					resource.TestCheckResourceAttr("yandex_vpc_subnet.subnet-a", "route_table_id", ""),
+					testAccCheckVPCSubnetRouteTableIdValue(&subnet, ""),
+				),
+			},
+		},
+	})
+}
+
 func TestAccVPCSubnet_withDhcpOptions(t *testing.T) {
 	var (
 		subnet              vpc.Subnet
@@ -529,6 +575,36 @@ resource "yandex_vpc_route_table" "rt-a" {
 `, networkName, subnet1Name)
 }
 
+func testAccVPCSubnet_routeTableAssociation(networkName, subnetName string, attached bool) string {
+	routeTableID := `""`
+	if attached {
+		routeTableID = "yandex_vpc_route_table.rt-a.id"
+	}
+
+	return fmt.Sprintf(`
+resource "yandex_vpc_network" "foo" {
+  name = "%s"
+}
+
+resource "yandex_vpc_subnet" "subnet-a" {
+  name           = "%s"
+  zone           = "ru-central1-a"
+  network_id     = yandex_vpc_network.foo.id
+  route_table_id = %s
+  v4_cidr_blocks = ["10.0.0.0/16"]
+}
+
+resource "yandex_vpc_route_table" "rt-a" {
+  network_id = yandex_vpc_network.foo.id
+
+  static_route {
+    destination_prefix = "172.16.10.0/24"
+    next_hop_address   = "10.0.0.172"
+  }
+}
+`, networkName, subnetName, routeTableID)
+}
+
 func testAccVPCSubnet_withDhcpOptions(networkName, subnetName, domainName string) string {
 	return fmt.Sprintf(`
 resource "yandex_vpc_network" "foo" {
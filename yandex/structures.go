@@ -112,6 +112,172 @@ func flattenInstanceResources(instance *compute.Instance) ([]map[string]interfac
 	return []map[string]interface{}{resourceMap}, nil
 }
 
+// computeInstanceResourcesSpec describes one allowed combination of instance resources on a platform.
+type computeInstanceResourcesSpec struct {
+	coreFractions    []int
+	minCores         int
+	maxCores         int
+	coresStep        int
+	minMemory        float64
+	minMemoryPerCore float64
+	maxMemoryPerCore float64
+	gpus             int
+}
+
+func (s computeInstanceResourcesSpec) matches(cores, coreFraction int, memory float64, gpus int) bool {
+	const epsilon = 1e-9
+
+	if gpus != s.gpus {
+		return false
+	}
+
+	fractionAllowed := false
+	for _, f := range s.coreFractions {
+		if f == coreFraction {
+			fractionAllowed = true
+			break
+		}
+	}
+	if !fractionAllowed {
+		return false
+	}
+
+	if cores < s.minCores || cores > s.maxCores || (cores-s.minCores)%s.coresStep != 0 {
+		return false
+	}
+
+	perCore := memory / float64(cores)
+	return memory+epsilon >= s.minMemory &&
+		perCore+epsilon >= s.minMemoryPerCore &&
+		perCore-epsilon <= s.maxMemoryPerCore
+}
+
+func (s computeInstanceResourcesSpec) String() string {
+	fractions := make([]string, 0, len(s.coreFractions))
+	for _, f := range s.coreFractions {
+		fractions = append(fractions, strconv.Itoa(f))
+	}
+
+	cores := strconv.Itoa(s.minCores)
+	if s.maxCores != s.minCores {
+		cores = fmt.Sprintf("%d-%d (step %d)", s.minCores, s.maxCores, s.coresStep)
+	}
+
+	memory := fmt.Sprintf("%g-%g GB per core", s.minMemoryPerCore, s.maxMemoryPerCore)
+	if s.minMemoryPerCore == s.maxMemoryPerCore {
+		memory = fmt.Sprintf("%g GB", s.minMemoryPerCore*float64(s.minCores))
+	}
+	if s.minMemory > 0 {
+		memory = fmt.Sprintf("%s, at least %g GB", memory, s.minMemory)
+	}
+
+	return fmt.Sprintf("gpus = %d, core_fraction = %s, cores = %s, memory = %s",
+		s.gpus, strings.Join(fractions, "|"), cores, memory)
+}
+
+// gpuInstanceResourcesSpec returns a GPU platform configuration, where cores and memory are fixed.
+func gpuInstanceResourcesSpec(gpus, cores int, memory float64) computeInstanceResourcesSpec {
+	perCore := memory / float64(cores)
+	return computeInstanceResourcesSpec{
+		coreFractions:    []int{100},
+		minCores:         cores,
+		maxCores:         cores,
+		coresStep:        1,
+		minMemoryPerCore: perCore,
+		maxMemoryPerCore: perCore,
+		gpus:             gpus,
+	}
+}
+
+// computeInstancePlatformResources maps platform_id to the allowed resource configurations.
+// Platforms absent from the table are not validated by the provider.
+var computeInstancePlatformResources = map[string][]computeInstanceResourcesSpec{
+	"standard-v1": {
+		{coreFractions: []int{5, 20}, minCores: 2, maxCores: 4, coresStep: 2, minMemory: 0.5, maxMemoryPerCore: 2},
+		{coreFractions: []int{100}, minCores: 2, maxCores: 32, coresStep: 2, minMemoryPerCore: 1, maxMemoryPerCore: 8},
+	},
+	"standard-v2": {
+		{coreFractions: []int{5}, minCores: 2, maxCores: 4, coresStep: 2, minMemory: 0.5, maxMemoryPerCore: 2},
+		{coreFractions: []int{20, 50}, minCores: 2, maxCores: 32, coresStep: 2, minMemory: 0.5, maxMemoryPerCore: 8},
+		{coreFractions: []int{100}, minCores: 2, maxCores: 80, coresStep: 2, minMemoryPerCore: 1, maxMemoryPerCore: 8},
+	},
+	"standard-v3": {
+		{coreFractions: []int{20, 50}, minCores: 2, maxCores: 32, coresStep: 2, minMemory: 1, maxMemoryPerCore: 8},
+		{coreFractions: []int{100}, minCores: 2, maxCores: 96, coresStep: 2, minMemoryPerCore: 1, maxMemoryPerCore: 16},
+	},
+	"gpu-standard-v1": {
+		gpuInstanceResourcesSpec(1, 8, 96),
+		gpuInstanceResourcesSpec(2, 16, 192),
+		gpuInstanceResourcesSpec(4, 32, 384),
+	},
+	"gpu-standard-v2": {
+		gpuInstanceResourcesSpec(1, 8, 48),
+		gpuInstanceResourcesSpec(2, 16, 96),
+		gpuInstanceResourcesSpec(4, 32, 192),
+		gpuInstanceResourcesSpec(8, 64, 384),
+	},
+	"gpu-standard-v3": {
+		gpuInstanceResourcesSpec(1, 28, 119),
+		gpuInstanceResourcesSpec(2, 56, 238),
+		gpuInstanceResourcesSpec(4, 112, 476),
+		gpuInstanceResourcesSpec(8, 224, 952),
+	},
+	"standard-v3-t4": {
+		gpuInstanceResourcesSpec(1, 4, 16),
+		gpuInstanceResourcesSpec(1, 8, 32),
+		gpuInstanceResourcesSpec(1, 16, 64),
+		gpuInstanceResourcesSpec(1, 32, 128),
+	},
+}
+
+func validateInstanceResources(platformID string, cores, coreFraction int, memory float64, gpus int) error {
+	specs, ok := computeInstancePlatformResources[platformID]
+	if !ok {
+		return nil
+	}
+
+	for _, spec := range specs {
+		if spec.matches(cores, coreFraction, memory, gpus) {
+			return nil
+		}
+	}
+
+	allowed := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		allowed = append(allowed, "  "+spec.String())
+	}
+
+	return fmt.Errorf("resources with gpus = %d, core_fraction = %d, cores = %d, memory = %g GB are not allowed on platform %q, "+
+		"allowed configurations are:\n%s", gpus, coreFraction, cores, memory, platformID, strings.Join(allowed, "\n"))
+}
+
+// computeInstanceMetadataMaxSize is the API limit on the total size of instance metadata keys and values.
+const computeInstanceMetadataMaxSize = 512 * 1024
+
+func validateInstanceMetadataSize(metadata map[string]interface{}) error {
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	total := 0
+	largestKey, largestSize := "", -1
+	for _, k := range keys {
+		size := len(k) + len(metadata[k].(string))
+		total += size
+		if size > largestSize {
+			largestKey, largestSize = k, size
+		}
+	}
+
+	if total > computeInstanceMetadataMaxSize {
+		return fmt.Errorf("total size of instance metadata is %d bytes, which exceeds the limit of %d bytes, "+
+			"the largest entry is %q with %d bytes", total, computeInstanceMetadataMaxSize, largestKey, largestSize)
+	}
+	return nil
+}
+
 func flattenInstanceBootDisk(ctx context.Context, instance *compute.Instance, diskServiceClient ReducedDiskServiceClient) ([]map[string]interface{}, error) {
 	attachedDisk := instance.GetBootDisk()
 	if attachedDisk == nil {
@@ -132,15 +298,7 @@ func flattenInstanceBootDisk(ctx context.Context, instance *compute.Instance, di
 		return nil, err
 	}
 
-	bootDisk["initialize_params"] = []map[string]interface{}{{
-		"name":        disk.Name,
-		"description": disk.Description,
-		"size":        toGigabytes(disk.Size),
-		"block_size":  int(disk.BlockSize),
-		"type":        disk.TypeId,
-		"image_id":    disk.GetSourceImageId(),
-		"snapshot_id": disk.GetSourceSnapshotId(),
-	}}
+	bootDisk["initialize_params"] = flattenInstanceDiskInitializeParams(disk)
 
 	return []map[string]interface{}{bootDisk}, nil
 }
@@ -157,9 +315,70 @@ func flattenInstanceSecondaryDisks(instance *compute.Instance) ([]map[string]int
 		}
 		secondaryDisks = append(secondaryDisks, disk)
 	}
+	// Secondary disks are a list, so sort them by device name to keep the order stable.
+	sort.SliceStable(secondaryDisks, func(i, j int) bool {
+		return secondaryDisks[i]["device_name"].(string) < secondaryDisks[j]["device_name"].(string)
+	})
 	return secondaryDisks, nil
 }
 
+// orderInstanceSecondaryDisks puts attached disks in the order of the known secondary_disk blocks,
+// matching them by disk_id or by device_name if disk_id is not known yet. Disks that are not known
+// follow in the device name order, so a new disk doesn't shift the existing ones.
+// initialize_params of a matched disk are taken from the known block, they are used only on creation.
+func orderInstanceSecondaryDisks(disks []map[string]interface{}, known []interface{}) []map[string]interface{} {
+	used := make([]bool, len(disks))
+	result := make([]map[string]interface{}, 0, len(disks))
+
+	match := func(key, value string, knownDisk map[string]interface{}) {
+		if value == "" {
+			return
+		}
+		for i, disk := range disks {
+			if !used[i] && disk[key].(string) == value {
+				used[i] = true
+				if params, ok := knownDisk["initialize_params"]; ok {
+					disk["initialize_params"] = params
+				}
+				result = append(result, disk)
+				return
+			}
+		}
+	}
+
+	for _, k := range known {
+		disk, ok := k.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if id, _ := disk["disk_id"].(string); id != "" {
+			match("disk_id", id, disk)
+			continue
+		}
+		deviceName, _ := disk["device_name"].(string)
+		match("device_name", deviceName, disk)
+	}
+
+	for i, disk := range disks {
+		if !used[i] {
+			result = append(result, disk)
+		}
+	}
+	return result
+}
+
+func flattenInstanceDiskInitializeParams(disk *compute.Disk) []map[string]interface{} {
+	return []map[string]interface{}{{
+		"name":        disk.Name,
+		"description": disk.Description,
+		"size":        toGigabytes(disk.Size),
+		"block_size":  int(disk.BlockSize),
+		"type":        disk.TypeId,
+		"image_id":    disk.GetSourceImageId(),
+		"snapshot_id": disk.GetSourceSnapshotId(),
+	}}
+}
+
 func flattenInstanceNetworkInterfaces(instance *compute.Instance) ([]map[string]interface{}, string, string, error) {
 	nics := make([]map[string]interface{}, len(instance.NetworkInterfaces))
 	var externalIP, internalIP string
@@ -364,8 +583,16 @@ func expandBootDiskSpec(d *schema.ResourceData, config *Config) (*compute.Attach
 	}
 
 	var minStorageSizeBytes int64
-	if v, ok := d.GetOk("boot_disk.0.initialize_params.0.image_id"); ok {
-		imageID := v.(string)
+	imageID := d.Get("boot_disk.0.initialize_params.0.image_id").(string)
+	if v, ok := d.GetOk("boot_disk.0.initialize_params.0.image_family"); ok {
+		id, err := getStandardImageIDByFamily(v.(string), config)
+		if err != nil {
+			return nil, err
+		}
+		imageID = id
+	}
+
+	if imageID != "" {
 		diskSpec.Source = &compute.AttachedDiskSpec_DiskSpec_ImageId{
 			ImageId: imageID,
 		}
@@ -405,14 +632,14 @@ func expandBootDiskSpec(d *schema.ResourceData, config *Config) (*compute.Attach
 	return diskSpec, nil
 }
 
-func expandInstanceSecondaryDiskSpecs(d *schema.ResourceData) ([]*compute.AttachedDiskSpec, error) {
+func expandInstanceSecondaryDiskSpecs(d *schema.ResourceData, config *Config) ([]*compute.AttachedDiskSpec, error) {
 	secondaryDisksCount := d.Get("secondary_disk.#").(int)
 	ads := make([]*compute.AttachedDiskSpec, secondaryDisksCount)
 
 	for i := 0; i < secondaryDisksCount; i++ {
 		diskConfig := d.Get(fmt.Sprintf("secondary_disk.%d", i)).(map[string]interface{})
 
-		disk, err := expandSecondaryDiskSpec(diskConfig)
+		disk, err := expandSecondaryDiskSpec(diskConfig, config)
 		if err != nil {
 			return nil, err
 		}
@@ -421,7 +648,7 @@ func expandInstanceSecondaryDiskSpecs(d *schema.ResourceData) ([]*compute.Attach
 	return ads, nil
 }
 
-func expandSecondaryDiskSpec(diskConfig map[string]interface{}) (*compute.AttachedDiskSpec, error) {
+func expandSecondaryDiskSpec(diskConfig map[string]interface{}, config *Config) (*compute.AttachedDiskSpec, error) {
 	disk := &compute.AttachedDiskSpec{}
 
 	if v, ok := diskConfig["mode"]; ok {
@@ -440,14 +667,74 @@ func expandSecondaryDiskSpec(diskConfig map[string]interface{}) (*compute.Attach
 		disk.AutoDelete = v.(bool)
 	}
 
-	if v, ok := diskConfig["disk_id"]; ok {
-		// TODO: support disk creation
+	// disk_id is known for disks created with initialize_params as well, so attached disks are
+	// always identified by it and initialize_params are used only to create a new disk.
+	if v, ok := diskConfig["disk_id"]; ok && v.(string) != "" {
 		disk.Disk = &compute.AttachedDiskSpec_DiskId{
 			DiskId: v.(string),
 		}
+		return disk, nil
+	}
+
+	if v, ok := diskConfig["initialize_params"]; ok && len(v.([]interface{})) > 0 && v.([]interface{})[0] != nil {
+		diskSpec, err := expandSecondaryDiskInitializeParams(v.([]interface{})[0].(map[string]interface{}), config)
+		if err != nil {
+			return nil, err
+		}
+		disk.Disk = &compute.AttachedDiskSpec_DiskSpec_{
+			DiskSpec: diskSpec,
+		}
+		return disk, nil
+	}
+
+	return nil, fmt.Errorf("one of 'disk_id' or 'initialize_params' should be specified for secondary disk")
+}
+
+func expandSecondaryDiskInitializeParams(params map[string]interface{}, config *Config) (*compute.AttachedDiskSpec_DiskSpec, error) {
+	diskSpec := &compute.AttachedDiskSpec_DiskSpec{
+		Name:        params["name"].(string),
+		Description: params["description"].(string),
+		TypeId:      params["type"].(string),
+		Size:        toBytes(params["size"].(int)),
+		BlockSize:   int64(params["block_size"].(int)),
+	}
+
+	imageID := params["image_id"].(string)
+	snapshotID := params["snapshot_id"].(string)
+	if imageID != "" && snapshotID != "" {
+		return nil, fmt.Errorf("only one of 'image_id' or 'snapshot_id' can be specified in secondary disk 'initialize_params'")
+	}
+
+	var minStorageSizeBytes int64
+	if imageID != "" {
+		diskSpec.Source = &compute.AttachedDiskSpec_DiskSpec_ImageId{
+			ImageId: imageID,
+		}
+
+		size, err := getImageMinStorageSize(imageID, config)
+		if err != nil {
+			return nil, err
+		}
+		minStorageSizeBytes = size
+	}
+
+	if snapshotID != "" {
+		diskSpec.Source = &compute.AttachedDiskSpec_DiskSpec_SnapshotId{
+			SnapshotId: snapshotID,
+		}
+
+		size, err := getSnapshotMinStorageSize(snapshotID, config)
+		if err != nil {
+			return nil, err
+		}
+		minStorageSizeBytes = size
 	}
 
-	return disk, nil
+	if diskSpec.Size == 0 {
+		diskSpec.Size = minStorageSizeBytes
+	}
+
+	return diskSpec, nil
 }
 
 func expandPrimaryV4AddressSpec(config map[string]interface{}) (*compute.PrimaryAddressSpec, error) {
@@ -464,8 +751,11 @@ func expandPrimaryV4AddressSpec(config map[string]interface{}) (*compute.Primary
 			dnsSpecs = expandComputeInstanceDnsRecords(v1.([]interface{}))
 		}
 
+		// ip_address is empty when not configured, the address is then allocated automatically.
+		address, _ := config["ip_address"].(string)
+
 		return &compute.PrimaryAddressSpec{
-			Address:         config["ip_address"].(string),
+			Address:         address,
 			OneToOneNatSpec: natSpec,
 			DnsRecordSpecs:  dnsSpecs,
 		}, nil
@@ -883,19 +1173,35 @@ func expandInstanceFilesystemSpecs(d *schema.ResourceData) ([]*compute.AttachedF
 	return fsSpecs, nil
 }
 
-func expandInstanceGpuSettingsSpec(d *schema.ResourceData) (*compute.GpuSettings, error) {
-	if v, ok := d.GetOk("gpu_cluster_id"); ok {
-		return &compute.GpuSettings{
-			GpuClusterId: v.(string),
-		}, nil
+func expandInstanceGpuSettingsSpec(d *schema.ResourceData) *compute.GpuSettings {
+	gpuClusterID, ok := d.GetOk("gpu_settings.0.gpu_cluster_id")
+	if !ok {
+		gpuClusterID, ok = d.GetOk("gpu_cluster_id")
+	}
+	if !ok {
+		return nil
+	}
+
+	return &compute.GpuSettings{
+		GpuClusterId: gpuClusterID.(string),
+	}
+}
+
+func flattenInstanceGpuSettings(settings *compute.GpuSettings) []map[string]interface{} {
+	if settings.GetGpuClusterId() == "" {
+		return []map[string]interface{}{}
+	}
+	return []map[string]interface{}{
+		{
+			"gpu_cluster_id": settings.GetGpuClusterId(),
+		},
 	}
-	return nil, nil
 }
 
 func flattenInstanceSchedulingPolicy(instance *compute.Instance) ([]map[string]interface{}, error) {
 	schedulingPolicy := make([]map[string]interface{}, 0, 1)
 	schedulingMap := map[string]interface{}{
-		"preemptible": instance.SchedulingPolicy.Preemptible,
+		"preemptible": instance.GetSchedulingPolicy().GetPreemptible(),
 	}
 	schedulingPolicy = append(schedulingPolicy, schedulingMap)
 	return schedulingPolicy, nil
@@ -930,6 +1236,111 @@ func flattenInstanceMetadataOptions(instance *compute.Instance) []map[string]int
 	return []map[string]interface{}{metadataOptions}
 }
 
+// Metadata keys that may be set outside of terraform, e.g. when serial console
+// is enabled from the cloud console. They are tracked only if set in configuration.
+var instanceWellKnownMetadataKeys = []string{"serial-port-enable"}
+
+func isInstanceWellKnownMetadataKey(key string) bool {
+	for _, k := range instanceWellKnownMetadataKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// flattenInstanceMetadata drops well-known keys which are not present in configured metadata,
+// so they are not reported as drift.
+func flattenInstanceMetadata(metadata map[string]string, configured map[string]string) map[string]string {
+	result := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		if _, ok := configured[k]; !ok && isInstanceWellKnownMetadataKey(k) {
+			continue
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// mergeInstanceWellKnownMetadata keeps current values of well-known keys that were never set by user,
+// otherwise update of the whole metadata map would reset them.
+func mergeInstanceWellKnownMetadata(metadata, oldMetadata, current map[string]string) map[string]string {
+	for _, k := range instanceWellKnownMetadataKeys {
+		if _, ok := metadata[k]; ok {
+			continue
+		}
+		if _, ok := oldMetadata[k]; ok {
+			// removed from configuration explicitly
+			continue
+		}
+		if v, ok := current[k]; ok {
+			metadata[k] = v
+		}
+	}
+	return metadata
+}
+
+// Prefixes of labels which are added to resources by Yandex Cloud services, e.g. to
+// instances of a Managed Kubernetes node group. They are tracked only if set in configuration.
+var systemLabelKeys = []string{"managed-by"}
+var systemLabelPrefixes = []string{"managed-kubernetes-"}
+
+func isSystemLabel(key string) bool {
+	for _, k := range systemLabelKeys {
+		if key == k {
+			return true
+		}
+	}
+	for _, p := range systemLabelPrefixes {
+		if strings.HasPrefix(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// flattenResourceLabels drops system labels which are not present in configured labels,
+// so they are not reported as drift.
+func flattenResourceLabels(labels map[string]string, configured map[string]string) map[string]string {
+	result := make(map[string]string, len(labels))
+	for k, v := range labels {
+		if _, ok := configured[k]; !ok && isSystemLabel(k) {
+			continue
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// mergeSystemLabels keeps current values of system labels that were never set by user,
+// otherwise update of the whole labels map would remove them.
+func mergeSystemLabels(labels, oldLabels, current map[string]string) map[string]string {
+	for k, v := range current {
+		if !isSystemLabel(k) {
+			continue
+		}
+		if _, ok := labels[k]; ok {
+			continue
+		}
+		if _, ok := oldLabels[k]; ok {
+			// removed from configuration explicitly
+			continue
+		}
+		labels[k] = v
+	}
+	return labels
+}
+
+// matchLabels reports whether labels contain every key of selector with the same value.
+func matchLabels(labels, selector map[string]string) bool {
+	for k, v := range selector {
+		if value, ok := labels[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
 func flattenStaticRoutes(routeTable *vpc.RouteTable) *schema.Set {
 	staticRoutes := schema.NewSet(resourceYandexVPCRouteTableHash, nil)
 
@@ -3,11 +3,14 @@ package yandex
 import (
 	"context"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/durationpb"
 
 	"github.com/yandex-cloud/go-genproto/yandex/cloud/compute/v1"
@@ -69,6 +72,132 @@ func TestExpandLabels(t *testing.T) {
 	}
 }
 
+func TestFlattenResourceLabels(t *testing.T) {
+	cases := []struct {
+		name       string
+		labels     map[string]string
+		configured map[string]string
+		expected   map[string]string
+	}{
+		{
+			name: "system label is dropped",
+			labels: map[string]string{
+				"my_key":     "my_value",
+				"managed-by": "service",
+			},
+			configured: map[string]string{"my_key": "my_value"},
+			expected:   map[string]string{"my_key": "my_value"},
+		},
+		{
+			name: "configured system label is kept",
+			labels: map[string]string{
+				"managed-kubernetes-cluster-id": "cluster",
+			},
+			configured: map[string]string{"managed-kubernetes-cluster-id": "cluster"},
+			expected:   map[string]string{"managed-kubernetes-cluster-id": "cluster"},
+		},
+		{
+			name: "user label with system key prefix is kept",
+			labels: map[string]string{
+				"managed-by-team": "infra",
+			},
+			expected: map[string]string{"managed-by-team": "infra"},
+		},
+		{
+			name:     "labels is nil",
+			labels:   nil,
+			expected: map[string]string{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := flattenResourceLabels(tc.labels, tc.configured)
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Fatalf("Got:\n\n%#v\n\nExpected:\n\n%#v\n", result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestFlattenResourceLabels_NoDiff(t *testing.T) {
+	r := resourceYandexComputeInstance()
+	raw := map[string]interface{}{
+		"name": "instance",
+		"labels": map[string]interface{}{
+			"my_key": "my_value",
+		},
+	}
+
+	d := schema.TestResourceDataRaw(t, r.Schema, raw)
+	d.SetId("instance-id")
+	serverLabels := map[string]string{
+		"my_key":     "my_value",
+		"managed-by": "service",
+	}
+	if err := d.Set("labels", flattenResourceLabels(serverLabels, map[string]string{"my_key": "my_value"})); err != nil {
+		t.Fatalf("bad: %#v", err)
+	}
+
+	diff, err := r.Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(raw), nil)
+	if err != nil {
+		t.Fatalf("bad: %#v", err)
+	}
+	if diff != nil {
+		for k := range diff.Attributes {
+			if strings.HasPrefix(k, "labels") {
+				t.Fatalf("unexpected diff of %q: %#v", k, diff.Attributes[k])
+			}
+		}
+	}
+}
+
+func TestMergeSystemLabels(t *testing.T) {
+	current := map[string]string{
+		"my_key":                        "my_value",
+		"managed-by":                    "service",
+		"managed-kubernetes-cluster-id": "cluster",
+	}
+	oldLabels := map[string]string{
+		"my_key":                        "my_value",
+		"managed-kubernetes-cluster-id": "cluster",
+	}
+	labels := map[string]string{"my_key": "new_value"}
+
+	expected := map[string]string{
+		"my_key":     "new_value",
+		"managed-by": "service",
+	}
+	if result := mergeSystemLabels(labels, oldLabels, current); !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Got:\n\n%#v\n\nExpected:\n\n%#v\n", result, expected)
+	}
+}
+
+func TestMatchLabels(t *testing.T) {
+	labels := map[string]string{"env": "prod", "team": "data"}
+
+	cases := []struct {
+		name     string
+		selector map[string]string
+		expected bool
+	}{
+		{name: "empty selector", selector: nil, expected: true},
+		{name: "subset", selector: map[string]string{"env": "prod"}, expected: true},
+		{name: "all labels", selector: map[string]string{"env": "prod", "team": "data"}, expected: true},
+		{name: "other value", selector: map[string]string{"env": "test"}, expected: false},
+		{name: "missing key", selector: map[string]string{"owner": "me"}, expected: false},
+		{name: "empty value of missing key", selector: map[string]string{"owner": ""}, expected: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := matchLabels(labels, tc.selector); result != tc.expected {
+				t.Fatalf("matchLabels(%v, %v) = %v, expected %v", labels, tc.selector, result, tc.expected)
+			}
+		})
+	}
+}
+
 func TestExpandProductIds(t *testing.T) {
 	cases := []struct {
 		name       string
@@ -300,6 +429,176 @@ func TestFlattenInstanceResources(t *testing.T) {
 	}
 }
 
+func TestValidateInstanceResources(t *testing.T) {
+	cases := []struct {
+		name         string
+		platformID   string
+		cores        int
+		coreFraction int
+		memory       float64
+		gpus         int
+		expectError  bool
+	}{
+		{
+			name:         "standard-v1 cores 2 fraction 100 memory 2 gb",
+			platformID:   "standard-v1",
+			cores:        2,
+			coreFraction: 100,
+			memory:       2,
+		},
+		{
+			name:         "standard-v1 cores 2 fraction 50 is not supported",
+			platformID:   "standard-v1",
+			cores:        2,
+			coreFraction: 50,
+			memory:       2,
+			expectError:  true,
+		},
+		{
+			name:         "standard-v2 cores 4 fraction 5 memory 1 gb",
+			platformID:   "standard-v2",
+			cores:        4,
+			coreFraction: 5,
+			memory:       1,
+		},
+		{
+			name:         "standard-v2 odd number of cores",
+			platformID:   "standard-v2",
+			cores:        3,
+			coreFraction: 100,
+			memory:       3,
+			expectError:  true,
+		},
+		{
+			name:         "standard-v3 too much memory per core",
+			platformID:   "standard-v3",
+			cores:        2,
+			coreFraction: 100,
+			memory:       64,
+			expectError:  true,
+		},
+		{
+			name:         "standard-v3 gpus on cpu platform",
+			platformID:   "standard-v3",
+			cores:        8,
+			coreFraction: 100,
+			memory:       32,
+			gpus:         1,
+			expectError:  true,
+		},
+		{
+			name:         "gpu-standard-v1 cores 8 memory 96 gb 1 gpu",
+			platformID:   "gpu-standard-v1",
+			cores:        8,
+			coreFraction: 100,
+			memory:       96,
+			gpus:         1,
+		},
+		{
+			name:         "gpu-standard-v3 cores 224 memory 952 gb 8 gpus",
+			platformID:   "gpu-standard-v3",
+			cores:        224,
+			coreFraction: 100,
+			memory:       952,
+			gpus:         8,
+		},
+		{
+			name:         "gpu-standard-v3 cores do not match gpus",
+			platformID:   "gpu-standard-v3",
+			cores:        28,
+			coreFraction: 100,
+			memory:       119,
+			gpus:         2,
+			expectError:  true,
+		},
+		{
+			name:         "gpu-standard-v2 without gpus",
+			platformID:   "gpu-standard-v2",
+			cores:        8,
+			coreFraction: 100,
+			memory:       48,
+			expectError:  true,
+		},
+		{
+			name:         "unknown platform is not validated",
+			platformID:   "custom-platform",
+			cores:        3,
+			coreFraction: 42,
+			memory:       7,
+			gpus:         3,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateInstanceResources(tc.platformID, tc.cores, tc.coreFraction, tc.memory, tc.gpus)
+			if tc.expectError && err == nil {
+				t.Fatalf("expected error for platform %q, got nil", tc.platformID)
+			}
+			if !tc.expectError && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestValidateInstanceMetadataSize(t *testing.T) {
+	err := validateInstanceMetadataSize(map[string]interface{}{
+		"ssh-keys":  "ubuntu:ssh-rsa AAAA",
+		"user-data": strings.Repeat("a", computeInstanceMetadataMaxSize-len("user-data")-len("ssh-keys")-len("ubuntu:ssh-rsa AAAA")),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error for metadata at the limit: %s", err)
+	}
+
+	err = validateInstanceMetadataSize(map[string]interface{}{
+		"ssh-keys":  "ubuntu:ssh-rsa AAAA",
+		"user-data": strings.Repeat("a", computeInstanceMetadataMaxSize),
+	})
+	if err == nil {
+		t.Fatalf("expected error for oversized metadata, got nil")
+	}
+	if !strings.Contains(err.Error(), `"user-data"`) {
+		t.Fatalf("expected error to name the user-data key, got: %s", err)
+	}
+}
+
+func TestFlattenInstanceSchedulingPolicy(t *testing.T) {
+	cases := []struct {
+		name     string
+		instance *compute.Instance
+		expected []map[string]interface{}
+	}{
+		{
+			name:     "preemptible",
+			instance: &compute.Instance{SchedulingPolicy: &compute.SchedulingPolicy{Preemptible: true}},
+			expected: []map[string]interface{}{{"preemptible": true}},
+		},
+		{
+			name:     "not preemptible",
+			instance: &compute.Instance{SchedulingPolicy: &compute.SchedulingPolicy{Preemptible: false}},
+			expected: []map[string]interface{}{{"preemptible": false}},
+		},
+		{
+			name:     "no scheduling policy",
+			instance: &compute.Instance{},
+			expected: []map[string]interface{}{{"preemptible": false}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := flattenInstanceSchedulingPolicy(tc.instance)
+			if err != nil {
+				t.Fatalf("bad: %#v", err)
+			}
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Fatalf("Got:\n\n%#v\n\nExpected:\n\n%#v\n", result, tc.expected)
+			}
+		})
+	}
+}
+
 func TestFlattenInstanceBootDisk(t *testing.T) {
 	cases := []struct {
 		name     string
@@ -351,6 +650,223 @@ func TestFlattenInstanceBootDisk(t *testing.T) {
 	}
 }
 
+func TestFlattenInstanceSecondaryDisks(t *testing.T) {
+	instance := &compute.Instance{
+		SecondaryDisks: []*compute.AttachedDisk{
+			{
+				Mode:       compute.AttachedDisk_READ_ONLY,
+				DeviceName: "test-device-name",
+				AutoDelete: true,
+				DiskId:     "saeque9k",
+			},
+		},
+	}
+	expected := []map[string]interface{}{
+		{
+			"device_name": "test-device-name",
+			"auto_delete": true,
+			"disk_id":     "saeque9k",
+			"mode":        "READ_ONLY",
+		},
+	}
+
+	result, err := flattenInstanceSecondaryDisks(instance)
+	if err != nil {
+		t.Fatalf("bad: %#v", err)
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Got:\n\n%#v\n\nExpected:\n\n%#v\n", result, expected)
+	}
+}
+
+func TestFlattenInstanceSecondaryDisksSortedByDeviceName(t *testing.T) {
+	instance := &compute.Instance{
+		SecondaryDisks: []*compute.AttachedDisk{
+			{DiskId: "disk-b", DeviceName: "b", Mode: compute.AttachedDisk_READ_WRITE},
+			{DiskId: "disk-a", DeviceName: "a", Mode: compute.AttachedDisk_READ_WRITE},
+		},
+	}
+
+	result, err := flattenInstanceSecondaryDisks(instance)
+	if err != nil {
+		t.Fatalf("bad: %#v", err)
+	}
+	if len(result) != 2 || result[0]["disk_id"] != "disk-a" || result[1]["disk_id"] != "disk-b" {
+		t.Fatalf("secondary disks are not sorted by device name: %#v", result)
+	}
+}
+
+func TestOrderInstanceSecondaryDisks(t *testing.T) {
+	disk := func(id, deviceName string) map[string]interface{} {
+		return map[string]interface{}{"disk_id": id, "device_name": deviceName}
+	}
+	ids := func(disks []map[string]interface{}) []string {
+		var result []string
+		for _, d := range disks {
+			result = append(result, d["disk_id"].(string))
+		}
+		return result
+	}
+
+	cases := []struct {
+		name     string
+		disks    []map[string]interface{}
+		known    []interface{}
+		expected []string
+	}{
+		{
+			name:     "appended disk sorts before the existing one",
+			disks:    []map[string]interface{}{disk("new", "a"), disk("old", "z")},
+			known:    []interface{}{disk("old", "z"), disk("", "")},
+			expected: []string{"old", "new"},
+		},
+		{
+			name:     "known order is kept",
+			disks:    []map[string]interface{}{disk("d1", "a"), disk("d2", "b"), disk("d3", "c")},
+			known:    []interface{}{disk("d3", ""), disk("d1", "")},
+			expected: []string{"d3", "d1", "d2"},
+		},
+		{
+			name:     "disk without id is matched by device name",
+			disks:    []map[string]interface{}{disk("d1", "a"), disk("d2", "b")},
+			known:    []interface{}{disk("", "b")},
+			expected: []string{"d2", "d1"},
+		},
+		{
+			name:     "nothing known",
+			disks:    []map[string]interface{}{disk("d1", "a"), disk("d2", "b")},
+			expected: []string{"d1", "d2"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ids(orderInstanceSecondaryDisks(tc.disks, tc.known))
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("Got:\n\n%#v\n\nExpected:\n\n%#v\n", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestOrderInstanceSecondaryDisksKeepsInitializeParams(t *testing.T) {
+	params := []interface{}{map[string]interface{}{"size": 8}}
+	disks := []map[string]interface{}{{"disk_id": "d1", "device_name": "a"}}
+	known := []interface{}{map[string]interface{}{"disk_id": "d1", "initialize_params": params}}
+
+	result := orderInstanceSecondaryDisks(disks, known)
+	if !reflect.DeepEqual(result[0]["initialize_params"], params) {
+		t.Fatalf("initialize_params are not kept: %#v", result[0])
+	}
+}
+
+func TestExpandSecondaryDiskSpec(t *testing.T) {
+	initializeParams := func(params map[string]interface{}) []interface{} {
+		result := map[string]interface{}{
+			"name":        "",
+			"description": "",
+			"size":        0,
+			"block_size":  0,
+			"type":        "",
+			"image_id":    "",
+			"snapshot_id": "",
+		}
+		for k, v := range params {
+			result[k] = v
+		}
+		return []interface{}{result}
+	}
+
+	cases := []struct {
+		name       string
+		diskConfig map[string]interface{}
+		expected   *compute.AttachedDiskSpec
+		wantErr    string
+	}{
+		{
+			name: "existing disk",
+			diskConfig: map[string]interface{}{
+				"mode":    "READ_WRITE",
+				"disk_id": "saeque9k",
+			},
+			expected: &compute.AttachedDiskSpec{
+				Mode: compute.AttachedDiskSpec_READ_WRITE,
+				Disk: &compute.AttachedDiskSpec_DiskId{DiskId: "saeque9k"},
+			},
+		},
+		{
+			name: "already created disk is referenced by id",
+			diskConfig: map[string]interface{}{
+				"mode":              "READ_WRITE",
+				"disk_id":           "saeque9k",
+				"initialize_params": initializeParams(map[string]interface{}{"size": 20}),
+			},
+			expected: &compute.AttachedDiskSpec{
+				Mode: compute.AttachedDiskSpec_READ_WRITE,
+				Disk: &compute.AttachedDiskSpec_DiskId{DiskId: "saeque9k"},
+			},
+		},
+		{
+			name: "new empty disk",
+			diskConfig: map[string]interface{}{
+				"mode":        "READ_WRITE",
+				"auto_delete": true,
+				"disk_id":     "",
+				"initialize_params": initializeParams(map[string]interface{}{
+					"name":       "data",
+					"size":       20,
+					"block_size": 8192,
+					"type":       "network-ssd",
+				}),
+			},
+			expected: &compute.AttachedDiskSpec{
+				Mode:       compute.AttachedDiskSpec_READ_WRITE,
+				AutoDelete: true,
+				Disk: &compute.AttachedDiskSpec_DiskSpec_{DiskSpec: &compute.AttachedDiskSpec_DiskSpec{
+					Name:      "data",
+					TypeId:    "network-ssd",
+					Size:      toBytes(20),
+					BlockSize: 8192,
+				}},
+			},
+		},
+		{
+			name: "neither disk id nor initialize params",
+			diskConfig: map[string]interface{}{
+				"mode":    "READ_WRITE",
+				"disk_id": "",
+			},
+			wantErr: "one of 'disk_id' or 'initialize_params' should be specified",
+		},
+		{
+			name: "both image and snapshot",
+			diskConfig: map[string]interface{}{
+				"mode":              "READ_WRITE",
+				"initialize_params": initializeParams(map[string]interface{}{"image_id": "image", "snapshot_id": "snapshot"}),
+			},
+			wantErr: "only one of 'image_id' or 'snapshot_id' can be specified",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := expandSecondaryDiskSpec(tc.diskConfig, nil)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("expected error containing %q, got: %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("bad: %#v", err)
+			}
+			if !proto.Equal(result, tc.expected) {
+				t.Fatalf("Got:\n\n%v\n\nExpected:\n\n%v\n", result, tc.expected)
+			}
+		})
+	}
+}
+
 func TestFlattenInstanceNetworkInterfaces(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -569,6 +1085,27 @@ func TestExpandComputePrimaryV4AddressSpec(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "static address with nat",
+			data: map[string]interface{}{
+				"ipv4":       true,
+				"ip_address": "192.168.19.16",
+				"nat":        true,
+			},
+			spec: &compute.PrimaryAddressSpec{
+				Address: "192.168.19.16",
+				OneToOneNatSpec: &compute.OneToOneNatSpec{
+					IpVersion: compute.IpVersion_IPV4,
+				},
+			},
+		},
+		{
+			name: "automatic address",
+			data: map[string]interface{}{
+				"ipv4": true,
+			},
+			spec: &compute.PrimaryAddressSpec{},
+		},
 	}
 
 	for _, tt := range tests {
@@ -584,6 +1121,56 @@ func TestExpandComputePrimaryV4AddressSpec(t *testing.T) {
 	}
 }
 
+func TestExpandInstanceGpuSettingsSpec(t *testing.T) {
+	cases := []struct {
+		name     string
+		raw      map[string]interface{}
+		expected *compute.GpuSettings
+	}{
+		{
+			name: "gpu_settings block",
+			raw: map[string]interface{}{
+				"resources": []interface{}{map[string]interface{}{"cores": 8, "memory": 96, "gpus": 1}},
+				"gpu_settings": []interface{}{
+					map[string]interface{}{"gpu_cluster_id": "gpu-cluster-id"},
+				},
+			},
+			expected: &compute.GpuSettings{GpuClusterId: "gpu-cluster-id"},
+		},
+		{
+			name: "top-level gpu_cluster_id",
+			raw: map[string]interface{}{
+				"resources":      []interface{}{map[string]interface{}{"cores": 8, "memory": 96, "gpus": 1}},
+				"gpu_cluster_id": "gpu-cluster-id",
+			},
+			expected: &compute.GpuSettings{GpuClusterId: "gpu-cluster-id"},
+		},
+		{
+			name: "no gpu cluster",
+			raw: map[string]interface{}{
+				"resources": []interface{}{map[string]interface{}{"cores": 8, "memory": 96, "gpus": 1}},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceYandexComputeInstance().Schema, tc.raw)
+			result := expandInstanceGpuSettingsSpec(d)
+			if !proto.Equal(result, tc.expected) {
+				t.Fatalf("Got:\n\n%#v\n\nExpected:\n\n%#v\n", result, tc.expected)
+			}
+		})
+	}
+
+	if result := flattenInstanceGpuSettings(&compute.GpuSettings{GpuClusterId: "gpu-cluster-id"}); !reflect.DeepEqual(result, []map[string]interface{}{{"gpu_cluster_id": "gpu-cluster-id"}}) {
+		t.Fatalf("unexpected flattened gpu_settings: %#v", result)
+	}
+	if result := flattenInstanceGpuSettings(&compute.GpuSettings{}); len(result) != 0 {
+		t.Fatalf("unexpected flattened empty gpu_settings: %#v", result)
+	}
+}
+
 func TestExpandHostAffinityRuleSpec(t *testing.T) {
 	tests := []struct {
 		name string
@@ -911,3 +1498,88 @@ func TestFlattenSnapshotScheduleSnapshotSpec(t *testing.T) {
 		}
 	})
 }
+
+func TestFlattenInstanceMetadata(t *testing.T) {
+	cases := []struct {
+		name       string
+		metadata   map[string]string
+		configured map[string]string
+		expected   map[string]string
+	}{
+		{
+			name:       "serial port enabled outside of configuration",
+			metadata:   map[string]string{"foo": "bar", "serial-port-enable": "1"},
+			configured: map[string]string{"foo": "bar"},
+			expected:   map[string]string{"foo": "bar"},
+		},
+		{
+			name:       "serial port enabled in configuration",
+			metadata:   map[string]string{"foo": "bar", "serial-port-enable": "1"},
+			configured: map[string]string{"foo": "bar", "serial-port-enable": "0"},
+			expected:   map[string]string{"foo": "bar", "serial-port-enable": "1"},
+		},
+		{
+			name:       "unknown keys are reported",
+			metadata:   map[string]string{"foo": "bar", "baz": "qux"},
+			configured: map[string]string{"foo": "bar"},
+			expected:   map[string]string{"foo": "bar", "baz": "qux"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := flattenInstanceMetadata(tc.metadata, tc.configured)
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Fatalf("Got:\n\n%#v\n\nExpected:\n\n%#v\n", result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestMergeInstanceWellKnownMetadata(t *testing.T) {
+	cases := []struct {
+		name        string
+		metadata    map[string]string
+		oldMetadata map[string]string
+		current     map[string]string
+		expected    map[string]string
+	}{
+		{
+			name:        "keep serial port enabled outside of configuration",
+			metadata:    map[string]string{"foo": "baz"},
+			oldMetadata: map[string]string{"foo": "bar"},
+			current:     map[string]string{"foo": "bar", "serial-port-enable": "1"},
+			expected:    map[string]string{"foo": "baz", "serial-port-enable": "1"},
+		},
+		{
+			name:        "user value wins",
+			metadata:    map[string]string{"foo": "bar", "serial-port-enable": "0"},
+			oldMetadata: map[string]string{"foo": "bar"},
+			current:     map[string]string{"foo": "bar", "serial-port-enable": "1"},
+			expected:    map[string]string{"foo": "bar", "serial-port-enable": "0"},
+		},
+		{
+			name:        "removed from configuration",
+			metadata:    map[string]string{"foo": "bar"},
+			oldMetadata: map[string]string{"foo": "bar", "serial-port-enable": "1"},
+			current:     map[string]string{"foo": "bar", "serial-port-enable": "1"},
+			expected:    map[string]string{"foo": "bar"},
+		},
+		{
+			name:        "unknown keys are not kept",
+			metadata:    map[string]string{"foo": "bar"},
+			oldMetadata: map[string]string{"foo": "bar", "baz": "qux"},
+			current:     map[string]string{"foo": "bar", "baz": "qux", "other": "value"},
+			expected:    map[string]string{"foo": "bar"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := mergeInstanceWellKnownMetadata(tc.metadata, tc.oldMetadata, tc.current)
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Fatalf("Got:\n\n%#v\n\nExpected:\n\n%#v\n", result, tc.expected)
+			}
+		})
+	}
+}
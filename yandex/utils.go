@@ -484,6 +484,13 @@ func toGigabytes(bytesCount int64) int {
 	return int((datasize.ByteSize(bytesCount) * datasize.B).GBytes())
 }
 
+// toGigabytesRoundUp converts bytes to gigabytes, rounding partial gigabytes up
+// so that a size which is not a whole number of gigabytes never shrinks.
+func toGigabytesRoundUp(bytesCount int64) int {
+	gb := int64(datasize.GB)
+	return int((bytesCount + gb - 1) / gb)
+}
+
 func toGigabytesInFloat(bytesCount int64) float64 {
 	return (datasize.ByteSize(bytesCount) * datasize.B).GBytes()
 }
@@ -679,6 +686,21 @@ func getImageMinStorageSize(imageID string, config *Config) (size int64, err err
 	return image.MinDiskSize, nil
 }
 
+func getStandardImageIDByFamily(family string, config *Config) (string, error) {
+	ctx := config.Context()
+
+	image, err := config.sdk.Compute().Image().GetLatestByFamily(ctx, &compute.GetImageLatestByFamilyRequest{
+		FolderId: StandardImagesFolderID,
+		Family:   family,
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("failed to find latest image with family \"%s\": %s", family, err)
+	}
+
+	return image.Id, nil
+}
+
 func templateConfig(tmpl string, ctx ...map[string]interface{}) string {
 	p := make(map[string]interface{})
 	for _, c := range ctx {